@@ -998,8 +998,117 @@ func (c *Config) addParser(parentcategory, parentname string, table *ast.Table)
 		DataFormat: dataformat,
 	}
 	running := models.NewRunningParser(parser, conf)
-	err := running.Init()
-	return running, err
+	if err := running.Init(); err != nil {
+		return running, &parserInitError{err: err}
+	}
+	return running, nil
+}
+
+// parserInitError is returned by addParser if the parser was created but
+// failed to initialize, e.g. due to an invalid pattern
+type parserInitError struct {
+	err error
+}
+
+func (e *parserInitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *parserInitError) Unwrap() error {
+	return e.err
+}
+
+func (c *Config) addFieldParsers(parentname string, table *ast.Table, plugin telegraf.FieldParserPlugin) (*ast.Table, error) {
+	node, found := table.Fields["field"]
+	if !found {
+		return table, nil
+	}
+
+	subtables, ok := node.([]*ast.Table)
+	if !ok {
+		return nil, errors.New("field parsers must be specified as array of tables")
+	}
+
+	// The sub-tables are only consumed by their parser, so any option not
+	// used by the parser is an unknown option.
+	tracker := c.toml.MissingField
+	c.toml.MissingField = c.missingTomlField
+	defer func() { c.toml.MissingField = tracker }()
+
+	// Initialization errors are collected and returned along with the table
+	// to allow the plugin to decide whether to continue without the parser
+	var initErr error
+	for _, subtable := range subtables {
+		var field string
+		c.getFieldString(subtable, "name", &field)
+		if field == "" {
+			return nil, errors.New("missing name for field parser")
+		}
+
+		parser, err := c.addParser("processors", parentname, withoutTableField(subtable, "name"))
+		if err != nil {
+			err = fmt.Errorf("adding parser for field %q failed: %w", field, err)
+			var perr *parserInitError
+			if !errors.As(err, &perr) {
+				return nil, err
+			}
+			initErr = errors.Join(initErr, err)
+			continue
+		}
+		plugin.SetFieldParser(field, parser)
+	}
+
+	return withoutTableField(table, "field"), initErr
+}
+
+func (c *Config) addFallbackParsers(parentname string, table *ast.Table, plugin telegraf.FallbackParserPlugin) (*ast.Table, error) {
+	node, found := table.Fields["fallback"]
+	if !found {
+		return table, nil
+	}
+
+	subtables, ok := node.([]*ast.Table)
+	if !ok {
+		return nil, errors.New("fallback parsers must be specified as array of tables")
+	}
+
+	// The sub-tables are only consumed by their parser, so any option not
+	// used by the parser is an unknown option.
+	tracker := c.toml.MissingField
+	c.toml.MissingField = c.missingTomlField
+	defer func() { c.toml.MissingField = tracker }()
+
+	// Initialization errors are collected and returned along with the table
+	// to allow the plugin to decide whether to continue without the parser
+	var initErr error
+	for i, subtable := range subtables {
+		parser, err := c.addParser("processors", parentname, subtable)
+		if err != nil {
+			err = fmt.Errorf("adding fallback parser %d failed: %w", i+1, err)
+			var perr *parserInitError
+			if !errors.As(err, &perr) {
+				return nil, err
+			}
+			initErr = errors.Join(initErr, err)
+			continue
+		}
+		plugin.AddFallbackParser(parser)
+	}
+
+	return withoutTableField(table, "fallback"), initErr
+}
+
+// withoutTableField returns a shallow copy of the table with the given field
+// removed, leaving the original table untouched.
+func withoutTableField(table *ast.Table, fieldName string) *ast.Table {
+	stripped := *table
+	stripped.Fields = make(map[string]interface{}, len(table.Fields))
+	for k, v := range table.Fields {
+		if k != fieldName {
+			stripped.Fields[k] = v
+		}
+	}
+	return &stripped
 }
 
 func (c *Config) addSerializer(parentname string, table *ast.Table) (*models.RunningSerializer, error) {
@@ -1101,15 +1210,58 @@ func (c *Config) setupProcessor(name string, creator processors.StreamingCreator
 		processor = streamingProcessor
 	}
 
+	// If the (underlying) processor has a SetFieldParser function, it can use
+	// dedicated parsers for individual fields, so build the parsers requested
+	// in the "field" sub-tables and set them. The sub-tables are consumed here
+	// and removed from the table used for the remaining setup.
+	// Parser initialization errors are deferred until the processor is
+	// configured as the processor might be able to handle them.
+	var initErr error
+	if t, ok := processor.(telegraf.FieldParserPlugin); ok {
+		var err error
+		table, err = c.addFieldParsers(name, table, t)
+		if err != nil {
+			err = fmt.Errorf("adding field parsers failed: %w", err)
+			var perr *parserInitError
+			if !errors.As(err, &perr) {
+				return nil, 0, err
+			}
+			initErr = err
+		}
+	}
+
+	// If the (underlying) processor has an AddFallbackParser function, it can
+	// try further parsers if parsing fails, so build the parsers requested in
+	// the "fallback" sub-tables in order. The sub-tables are consumed here
+	// like the field parsers.
+	if t, ok := processor.(telegraf.FallbackParserPlugin); ok {
+		var err error
+		table, err = c.addFallbackParsers(name, table, t)
+		if err != nil {
+			err = fmt.Errorf("adding fallback parsers failed: %w", err)
+			var perr *parserInitError
+			if !errors.As(err, &perr) {
+				return nil, 0, err
+			}
+			initErr = errors.Join(initErr, err)
+		}
+	}
+
 	// If the (underlying) processor has a SetParser or SetParserFunc function,
 	// it can accept arbitrary data-formats, so build the requested parser and
 	// set it.
 	if t, ok := processor.(telegraf.ParserPlugin); ok {
 		parser, err := c.addParser("processors", name, table)
 		if err != nil {
-			return nil, 0, fmt.Errorf("adding parser failed: %w", err)
+			err = fmt.Errorf("adding parser failed: %w", err)
+			var perr *parserInitError
+			if !errors.As(err, &perr) {
+				return nil, 0, err
+			}
+			initErr = errors.Join(initErr, err)
+		} else {
+			t.SetParser(parser)
 		}
-		t.SetParser(parser)
 		optionTestCount++
 	}
 
@@ -1138,6 +1290,16 @@ func (c *Config) setupProcessor(name string, creator processors.StreamingCreator
 		return nil, 0, fmt.Errorf("unmarshalling failed: %w", err)
 	}
 
+	if initErr != nil {
+		t, ok := processor.(telegraf.ParserInitErrorHandler)
+		if !ok {
+			return nil, 0, initErr
+		}
+		if err := t.HandleParserInitError(initErr); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	err := c.printUserDeprecation("processors", name, processor)
 	return streamingProcessor, optionTestCount, err
 }
@@ -434,6 +434,12 @@ func TestConfig_FieldNotDefined(t *testing.T) {
 			expected: "line 1: configuration specified the fields [\"not_a_field\"], but they were not used. " +
 				"This is either a typo or this config option does not exist in this version.",
 		},
+		{
+			name:     "in field parser of processor plugin",
+			filename: "./testdata/invalid_field_processor_in_field_parser_table.toml",
+			expected: "line 1: configuration specified the fields [\"not_a_field\"], but they were not used. " +
+				"This is either a typo or this config option does not exist in this version.",
+		},
 		{
 			name:     "in parser of processor plugin with parser-func",
 			filename: "./testdata/invalid_field_processor_in_parserfunc_table.toml",
@@ -1121,6 +1127,46 @@ func TestConfig_ProcessorsWithParsers(t *testing.T) {
 	}
 }
 
+func TestConfig_ProcessorsWithFieldParsers(t *testing.T) {
+	c := config.NewConfig()
+	require.NoError(t, c.LoadAll("./testdata/processors_with_field_parsers.toml"))
+	require.Len(t, c.Processors, 1)
+
+	var processorIF telegraf.Processor
+	if p, ok := c.Processors[0].Processor.(processors.HasUnwrap); ok {
+		processorIF = p.Unwrap()
+	} else {
+		processorIF = c.Processors[0].Processor.(telegraf.Processor)
+	}
+	processor, ok := processorIF.(*MockupProcessorPluginFieldParser)
+	require.True(t, ok)
+
+	// Check the default parser
+	rp, ok := processor.Parser.(*models.RunningParser)
+	require.True(t, ok)
+	require.Equal(t, "json", rp.Config.DataFormat)
+	require.IsType(t, &json.Parser{}, rp.Parser)
+	require.Equal(t, []string{"lvl"}, rp.Parser.(*json.Parser).TagKeys)
+
+	// Check the field parsers
+	require.Len(t, processor.FieldParsers, 2)
+	rp, ok = processor.FieldParsers["payload"].(*models.RunningParser)
+	require.True(t, ok)
+	require.Equal(t, "logfmt", rp.Config.DataFormat)
+	rp, ok = processor.FieldParsers["count"].(*models.RunningParser)
+	require.True(t, ok)
+	require.Equal(t, "value", rp.Config.DataFormat)
+
+	// Check the fallback parsers
+	require.Len(t, processor.FallbackParsers, 2)
+	rp, ok = processor.FallbackParsers[0].(*models.RunningParser)
+	require.True(t, ok)
+	require.Equal(t, "logfmt", rp.Config.DataFormat)
+	rp, ok = processor.FallbackParsers[1].(*models.RunningParser)
+	require.True(t, ok)
+	require.Equal(t, "influx", rp.Config.DataFormat)
+}
+
 func TestConfigPluginIDsDifferent(t *testing.T) {
 	c := config.NewConfig()
 	c.Agent.Statefile = "/dev/null"
@@ -1373,6 +1419,32 @@ func (m *MockupProcessorPluginParser) SetParserFunc(f telegraf.ParserFunc) {
 	m.ParserFunc = f
 }
 
+/*** Mockup PROCESSOR plugin with field parsers ***/
+type MockupProcessorPluginFieldParser struct {
+	Parser          telegraf.Parser
+	FieldParsers    map[string]telegraf.Parser
+	FallbackParsers []telegraf.Parser
+}
+
+func (m *MockupProcessorPluginFieldParser) SampleConfig() string {
+	return "Mockup test processor plugin with field parsers"
+}
+func (m *MockupProcessorPluginFieldParser) Apply(_ ...telegraf.Metric) []telegraf.Metric {
+	return nil
+}
+func (m *MockupProcessorPluginFieldParser) SetParser(parser telegraf.Parser) {
+	m.Parser = parser
+}
+func (m *MockupProcessorPluginFieldParser) SetFieldParser(field string, parser telegraf.Parser) {
+	if m.FieldParsers == nil {
+		m.FieldParsers = make(map[string]telegraf.Parser)
+	}
+	m.FieldParsers[field] = parser
+}
+func (m *MockupProcessorPluginFieldParser) AddFallbackParser(parser telegraf.Parser) {
+	m.FallbackParsers = append(m.FallbackParsers, parser)
+}
+
 /*** Mockup PROCESSOR plugin without parser ***/
 type MockupProcessorPlugin struct {
 	Option string `toml:"option"`
@@ -1614,6 +1686,9 @@ func init() {
 	processors.Add("parser_test", func() telegraf.Processor {
 		return &MockupProcessorPluginParser{}
 	})
+	processors.Add("field_parser_test", func() telegraf.Processor {
+		return &MockupProcessorPluginFieldParser{}
+	})
 	processors.Add("processor", func() telegraf.Processor {
 		return &MockupProcessorPlugin{}
 	})
@@ -76,6 +76,7 @@ require (
 	github.com/eclipse/paho.mqtt.golang v1.4.3
 	github.com/facebook/time v0.0.0-20240125155343-557f84f4ad3e
 	github.com/fatih/color v1.17.0
+	github.com/fxamacker/cbor/v2 v2.6.0
 	github.com/go-ldap/ldap/v3 v3.4.6
 	github.com/go-logfmt/logfmt v0.6.0
 	github.com/go-ole/go-ole v1.3.0
@@ -314,7 +315,6 @@ require (
 	github.com/echlebek/timeproxy v1.0.0 // indirect
 	github.com/emicklei/go-restful/v3 v3.11.0 // indirect
 	github.com/felixge/httpsnoop v1.0.4 // indirect
-	github.com/fxamacker/cbor/v2 v2.6.0 // indirect
 	github.com/gabriel-vasile/mimetype v1.4.2 // indirect
 	github.com/go-asn1-ber/asn1-ber v1.5.5 // indirect
 	github.com/go-kit/log v0.2.1 // indirect
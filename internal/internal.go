@@ -240,12 +240,17 @@ func CompressWithGzip(data io.Reader) io.ReadCloser {
 //	json_time_format = "2006-01-02T15:04:05Z07:00"
 //	json_timezone = "America/Los_Angeles"
 //
-// The format can be one of "unix", "unix_ms", "unix_us", "unix_ns", or a Go
-// time layout suitable for time.Parse.
+// The format can be one of "unix", "unix_ms", "unix_us", "unix_ns",
+// "unix_auto", or a Go time layout suitable for time.Parse.
 //
 // When using the "unix" format, an optional fractional component is allowed.
 // Specific unix time precisions cannot have a fractional component.
 //
+// The "unix_auto" format infers the precision from the magnitude of the
+// integer part of each timestamp: values below 1e11 are seconds, values below
+// 1e14 are milliseconds, values below 1e17 are microseconds and everything
+// else is nanoseconds.
+//
 // Unix times may be an int64, float64, or string.  When using a Go format
 // string the timestamp must be a string.
 //
@@ -254,7 +259,7 @@ func CompressWithGzip(data io.Reader) io.ReadCloser {
 // UTC location.
 func ParseTimestamp(format string, timestamp interface{}, location *time.Location, separator ...string) (time.Time, error) {
 	switch format {
-	case "unix", "unix_ms", "unix_us", "unix_ns":
+	case "unix", "unix_ms", "unix_us", "unix_ns", "unix_auto":
 		sep := []string{",", "."}
 		if len(separator) > 0 {
 			sep = separator
@@ -293,6 +298,9 @@ func parseUnix(format string, timestamp interface{}, separator []string) (time.T
 		if err != nil {
 			return zero, err
 		}
+		if format == "unix_auto" {
+			factor = unixAutoFactor(new(big.Rat).SetInt64(t))
+		}
 		return time.Unix(0, t*factor).UTC(), nil
 	case float32, float64:
 		ts, err := ToFloat64(v)
@@ -305,6 +313,9 @@ func parseUnix(format string, timestamp interface{}, separator []string) (time.T
 		if f.SetFloat64(ts) == nil {
 			return zero, errors.New("invalid number")
 		}
+		if format == "unix_auto" {
+			factor = unixAutoFactor(&f)
+		}
 		return timeFromFraction(&f, factor), nil
 	case string:
 		// Sanitize the string to have no thousand separators and dot
@@ -316,12 +327,30 @@ func parseUnix(format string, timestamp interface{}, separator []string) (time.T
 		if _, ok := f.SetString(v); !ok {
 			return zero, errors.New("invalid number")
 		}
+		if format == "unix_auto" {
+			factor = unixAutoFactor(&f)
+		}
 		return timeFromFraction(&f, factor), nil
 	}
 
 	return zero, errors.New("unsupported type")
 }
 
+// unixAutoFactor determines the scaling factor to nanoseconds from the
+// magnitude of the given unix timestamp
+func unixAutoFactor(f *big.Rat) int64 {
+	v := new(big.Rat).Abs(f)
+	switch {
+	case v.Cmp(big.NewRat(1e11, 1)) < 0:
+		return int64(time.Second)
+	case v.Cmp(big.NewRat(1e14, 1)) < 0:
+		return int64(time.Millisecond)
+	case v.Cmp(big.NewRat(1e17, 1)) < 0:
+		return int64(time.Microsecond)
+	}
+	return int64(time.Nanosecond)
+}
+
 func timeFromFraction(f *big.Rat, factor int64) time.Time {
 	// Extract the numerator and denominator and scale to nanoseconds
 	num := f.Num()
@@ -541,6 +541,66 @@ func TestParseTimestamp(t *testing.T) {
 			timestamp: "1.5683382080000005e+18",
 			expected:  rfc3339("2019-09-13T01:30:08.000000500Z"),
 		},
+		{
+			name:      "unix auto seconds",
+			format:    "unix_auto",
+			timestamp: "1568338208",
+			expected:  rfc3339("2019-09-13T01:30:08Z"),
+		},
+		{
+			name:      "unix auto seconds with fractional",
+			format:    "unix_auto",
+			timestamp: "1568338208.5",
+			expected:  rfc3339("2019-09-13T01:30:08.500Z"),
+		},
+		{
+			name:      "unix auto seconds far future",
+			format:    "unix_auto",
+			timestamp: int64(9000000000),
+			expected:  rfc3339("2255-03-14T16:00:00Z"),
+		},
+		{
+			name:      "unix auto milliseconds lower cutoff",
+			format:    "unix_auto",
+			timestamp: int64(100000000000),
+			expected:  rfc3339("1973-03-03T09:46:40Z"),
+		},
+		{
+			name:      "unix auto milliseconds",
+			format:    "unix_auto",
+			timestamp: int64(1568338208500),
+			expected:  rfc3339("2019-09-13T01:30:08.500Z"),
+		},
+		{
+			name:      "unix auto milliseconds float",
+			format:    "unix_auto",
+			timestamp: float64(1568338208500),
+			expected:  rfc3339("2019-09-13T01:30:08.500Z"),
+		},
+		{
+			name:      "unix auto microseconds lower cutoff",
+			format:    "unix_auto",
+			timestamp: "100000000000000",
+			expected:  rfc3339("1973-03-03T09:46:40Z"),
+		},
+		{
+			name:      "unix auto microseconds",
+			format:    "unix_auto",
+			timestamp: "1568338208000500",
+			expected:  rfc3339("2019-09-13T01:30:08.000500Z"),
+		},
+		{
+			name:      "unix auto nanoseconds lower cutoff",
+			format:    "unix_auto",
+			timestamp: "100000000000000000",
+			expected:  rfc3339("1973-03-03T09:46:40Z"),
+		},
+		{
+			name:      "unix auto nanoseconds",
+			format:    "unix_auto",
+			timestamp: int64(1568338208000000500),
+			expected:  rfc3339("2019-09-13T01:30:08.000000500Z"),
+		},
 		{
 			name:      "rfc339 test",
 			format:    "RFC3339",
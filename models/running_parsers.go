@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -79,6 +80,42 @@ func (r *RunningParser) Parse(buf []byte) ([]telegraf.Metric, error) {
 	return m, err
 }
 
+// ParseSource forwards the data of the given source to parsers keeping state
+// per source and falls back to Parse for all other parsers.
+func (r *RunningParser) ParseSource(source string, buf []byte) ([]telegraf.Metric, error) {
+	p, ok := r.Parser.(telegraf.SourceParser)
+	if !ok {
+		return r.Parse(buf)
+	}
+
+	start := time.Now()
+	m, err := p.ParseSource(source, buf)
+	elapsed := time.Since(start)
+	r.ParseTime.Incr(elapsed.Nanoseconds())
+	r.MetricsParsed.Incr(int64(len(m)))
+
+	return m, err
+}
+
+// Flush returns the metrics of the data held back by parsers supporting
+// flushing and nil for all other parsers.
+func (r *RunningParser) Flush(force bool) map[string][]telegraf.Metric {
+	p, ok := r.Parser.(telegraf.FlushingParser)
+	if !ok {
+		return nil
+	}
+
+	start := time.Now()
+	result := p.Flush(force)
+	elapsed := time.Since(start)
+	r.ParseTime.Incr(elapsed.Nanoseconds())
+	for _, m := range result {
+		r.MetricsParsed.Incr(int64(len(m)))
+	}
+
+	return result
+}
+
 func (r *RunningParser) ParseLine(line string) (telegraf.Metric, error) {
 	start := time.Now()
 	m, err := r.Parser.ParseLine(line)
@@ -89,6 +126,38 @@ func (r *RunningParser) ParseLine(line string) (telegraf.Metric, error) {
 	return m, err
 }
 
+// Clone creates an independent instance of a wrapped parser that is not safe
+// for concurrent use. The instance shares the configuration, logger and
+// statistics of the running parser.
+func (r *RunningParser) Clone() (telegraf.Parser, error) {
+	p, ok := r.Parser.(telegraf.NonReentrantParser)
+	if !ok {
+		return nil, fmt.Errorf("parser %q does not support cloning", r.Config.DataFormat)
+	}
+
+	instance, err := p.Clone()
+	if err != nil {
+		return nil, err
+	}
+	SetLoggerOnPlugin(instance, r.log)
+
+	return &RunningParser{
+		Parser:        instance,
+		Config:        r.Config,
+		MetricsParsed: r.MetricsParsed,
+		ParseTime:     r.ParseTime,
+		log:           r.log,
+	}, nil
+}
+
+// SetTimeFunc forwards the function for determining the time of metrics
+// without a timestamp to parsers supporting it.
+func (r *RunningParser) SetTimeFunc(fn func() time.Time) {
+	if p, ok := r.Parser.(telegraf.TimeFuncParser); ok {
+		p.SetTimeFunc(fn)
+	}
+}
+
 func (r *RunningParser) SetDefaultTags(tags map[string]string) {
 	r.Parser.SetDefaultTags(tags)
 }
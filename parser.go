@@ -1,5 +1,7 @@
 package telegraf
 
+import "time"
+
 // Parser is an interface defining functions that a parser plugin must satisfy.
 type Parser interface {
 	// Parse takes a byte buffer separated by newlines
@@ -24,6 +26,40 @@ type Parser interface {
 
 type ParserFunc func() (Parser, error)
 
+// NonReentrantParser is an interface for parsers keeping internal state
+// across calls. Such parsers must not be shared between concurrent users,
+// instead each user should work on its own instance.
+type NonReentrantParser interface {
+	// Clone returns a new, initialized instance of the parser with the
+	// same configuration but without any of the internal state.
+	Clone() (Parser, error)
+}
+
+// TimeFuncParser is an interface for parsers allowing to replace the
+// function providing the time of metrics without a timestamp in the data.
+type TimeFuncParser interface {
+	// SetTimeFunc sets the function used to determine the time of metrics
+	// without a timestamp.
+	SetTimeFunc(fn func() time.Time)
+}
+
+// SourceParser is an interface for parsers keeping state across calls
+// separately for each source of the data, e.g. pending multiline records.
+type SourceParser interface {
+	// ParseSource parses the given data like Parse but only continues
+	// state left by previous data of the same source.
+	ParseSource(source string, buf []byte) ([]Metric, error)
+}
+
+// FlushingParser is an interface for parsers holding back data across calls,
+// e.g. pending multiline records, until further data of the same source
+// arrives or a timeout expires.
+type FlushingParser interface {
+	// Flush returns the metrics of the data held back longer than the
+	// parser's timeout, or of all data held back if forced, per source.
+	Flush(force bool) map[string][]Metric
+}
+
 // ParserPlugin is an interface for plugins that are able to parse
 // arbitrary data formats.
 type ParserPlugin interface {
@@ -37,3 +73,27 @@ type ParserFuncPlugin interface {
 	// GetParser returns a new parser.
 	SetParserFunc(fn ParserFunc)
 }
+
+// FieldParserPlugin is an interface for plugins that are able to use
+// dedicated parsers for individual fields.
+type FieldParserPlugin interface {
+	// SetFieldParser sets the parser used for the given field
+	SetFieldParser(field string, parser Parser)
+}
+
+// FallbackParserPlugin is an interface for plugins that are able to try
+// further parsers if parsing fails.
+type FallbackParserPlugin interface {
+	// AddFallbackParser appends a parser to the list of parsers tried in
+	// order if the previous parsers fail
+	AddFallbackParser(parser Parser)
+}
+
+// ParserInitErrorHandler is an interface for plugins that are able to
+// continue operating if one of their parsers failed to initialize.
+type ParserInitErrorHandler interface {
+	// HandleParserInitError is called with the initialization error after
+	// the plugin's configuration is applied. Returning an error aborts
+	// loading the configuration, returning nil continues without the parser.
+	HandleParserInitError(err error) error
+}
@@ -35,6 +35,7 @@ type Execd struct {
 	acc          telegraf.Accumulator
 	parser       telegraf.Parser
 	outputReader func(io.Reader)
+	truncate     time.Duration
 }
 
 func (*Execd) SampleConfig() string {
@@ -47,8 +48,9 @@ func (e *Execd) SetParser(parser telegraf.Parser) {
 
 	unwrapped, ok := parser.(*models.RunningParser)
 	if ok {
-		if _, ok := unwrapped.Parser.(*influx.Parser); ok {
+		if p, ok := unwrapped.Parser.(*influx.Parser); ok {
 			e.outputReader = e.cmdReadOutStream
+			e.truncate = time.Duration(p.TimestampTruncate)
 		}
 	}
 }
@@ -110,6 +112,7 @@ func (e *Execd) cmdReadOut(out io.Reader) {
 
 func (e *Execd) cmdReadOutStream(out io.Reader) {
 	parser := influx.NewStreamParser(out)
+	parser.SetTimestampTruncate(e.truncate)
 
 	for {
 		metric, err := parser.Next()
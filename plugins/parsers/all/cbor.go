@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.cbor
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/cbor" // register plugin
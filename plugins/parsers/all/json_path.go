@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.json_path
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/json_path" // register plugin
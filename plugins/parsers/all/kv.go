@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.kv
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/kv" // register plugin
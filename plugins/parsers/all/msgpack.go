@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.msgpack
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/msgpack" // register plugin
@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.protobuf
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/protobuf" // register plugin
@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.regex
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/regex" // register plugin
@@ -0,0 +1,5 @@
+//go:build !custom || parsers || parsers.split
+
+package all
+
+import _ "github.com/influxdata/telegraf/plugins/parsers/split" // register plugin
@@ -1,10 +1,12 @@
 package avro
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/jeremywohl/flatten/v2"
@@ -23,11 +25,15 @@ import (
 // If Schema is set, we assume the input will be Avro binary format, without
 // an attached schema or schema fingerprint
 
+// If Format is "ocf", we assume the input will be an Avro Object Container
+// File with the schema embedded in the file header
+
 type Parser struct {
 	MetricName       string            `toml:"metric_name"`
 	SchemaRegistry   string            `toml:"avro_schema_registry"`
 	CaCertPath       string            `toml:"avro_schema_registry_cert"`
 	Schema           string            `toml:"avro_schema"`
+	SchemaFile       string            `toml:"avro_schema_file"`
 	Format           string            `toml:"avro_format"`
 	Measurement      string            `toml:"avro_measurement"`
 	MeasurementField string            `toml:"avro_measurement_field"`
@@ -46,7 +52,7 @@ func (p *Parser) Init() error {
 	switch p.Format {
 	case "":
 		p.Format = "binary"
-	case "binary", "json":
+	case "binary", "json", "ocf":
 		// Do nothing as those are valid settings
 	default:
 		return fmt.Errorf("unknown 'avro_format' %q", p.Format)
@@ -60,7 +66,23 @@ func (p *Parser) Init() error {
 		return fmt.Errorf("unknown avro_union_mode %q", p.Format)
 	}
 
-	if (p.Schema == "" && p.SchemaRegistry == "") || (p.Schema != "" && p.SchemaRegistry != "") {
+	if p.SchemaFile != "" {
+		if p.Schema != "" {
+			return errors.New("only one of 'schema' or 'schema_file' can be specified")
+		}
+		buf, err := os.ReadFile(p.SchemaFile)
+		if err != nil {
+			return fmt.Errorf("reading schema file %q failed: %w", p.SchemaFile, err)
+		}
+		p.Schema = string(buf)
+	}
+
+	if p.Format == "ocf" {
+		// Object container files contain their schema
+		if p.SchemaRegistry != "" {
+			return errors.New("'schema_registry' cannot be used with the 'ocf' format")
+		}
+	} else if (p.Schema == "" && p.SchemaRegistry == "") || (p.Schema != "" && p.SchemaRegistry != "") {
 		return errors.New("exactly one of 'schema_registry' or 'schema' must be specified")
 	}
 	switch p.TimestampFormat {
@@ -83,6 +105,10 @@ func (p *Parser) Init() error {
 }
 
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if p.Format == "ocf" {
+		return p.parseOCF(buf)
+	}
+
 	var schema string
 	var codec *goavro.Codec
 	var err error
@@ -144,6 +170,38 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 	return []telegraf.Metric{m}, nil
 }
 
+// parseOCF creates a metric for each record of the given object container
+// file using the schema embedded in the file.
+func (p *Parser) parseOCF(buf []byte) ([]telegraf.Metric, error) {
+	reader, err := goavro.NewOCFReader(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("reading object container file failed: %w", err)
+	}
+	schema := reader.Codec().Schema()
+
+	metrics := make([]telegraf.Metric, 0)
+	for reader.Scan() {
+		native, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading record failed: %w", err)
+		}
+		record, ok := native.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("native is of unsupported type %T", native)
+		}
+		m, err := p.createMetric(record, schema)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	if err := reader.Err(); err != nil {
+		return nil, fmt.Errorf("reading object container file failed: %w", err)
+	}
+
+	return metrics, nil
+}
+
 func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 	metrics, err := p.Parse([]byte(line))
 	if err != nil {
@@ -0,0 +1,140 @@
+package binary
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Defaults for CRC-16/CCITT-FALSE
+const (
+	defaultCRC16Polynomial = 0x1021
+	defaultCRC16Init       = 0xFFFF
+)
+
+func (e *Entry) checkChecksum() error {
+	e.Assignment = "checksum"
+	if e.Name == "" {
+		e.Name = e.Type
+	}
+
+	var size uint64
+	switch e.Type {
+	case "crc16":
+		size = 16
+		e.crc16Poly = defaultCRC16Polynomial
+		if e.Polynomial != "" {
+			v, err := parseHexUint(e.Polynomial, 16)
+			if err != nil {
+				return fmt.Errorf("invalid polynomial for %q: %w", e.Name, err)
+			}
+			e.crc16Poly = uint16(v)
+		}
+		e.crc16Init = defaultCRC16Init
+		if e.ChecksumInit != "" {
+			v, err := parseHexUint(e.ChecksumInit, 16)
+			if err != nil {
+				return fmt.Errorf("invalid checksum init for %q: %w", e.Name, err)
+			}
+			e.crc16Init = uint16(v)
+		}
+	case "crc32":
+		size = 32
+		if e.ChecksumInit != "" {
+			return fmt.Errorf("'checksum_init' not supported for %q", e.Name)
+		}
+		poly := uint32(crc32.IEEE)
+		if e.Polynomial != "" {
+			v, err := parseHexUint(e.Polynomial, 32)
+			if err != nil {
+				return fmt.Errorf("invalid polynomial for %q: %w", e.Name, err)
+			}
+			// The polynomial is given in normal notation like for crc16
+			// but the crc32 package expects the reversed notation
+			poly = bits.Reverse32(uint32(v))
+		}
+		e.crc32Table = crc32.MakeTable(poly)
+	default:
+		return fmt.Errorf("unknown checksum type %q", e.Type)
+	}
+
+	if e.Bits == 0 {
+		e.Bits = size
+	}
+	if e.Bits != size {
+		return fmt.Errorf("checksum %q requires %d bits", e.Name, size)
+	}
+
+	switch len(e.ChecksumRange) {
+	case 0:
+	case 2:
+		if e.ChecksumRange[0] >= e.ChecksumRange[1] {
+			return fmt.Errorf("invalid checksum range for %q", e.Name)
+		}
+	default:
+		return fmt.Errorf("checksum range for %q requires start and end", e.Name)
+	}
+
+	return nil
+}
+
+// verifyChecksum computes the checksum over the configured range of the
+// input and compares it to the value extracted at the given bit offset.
+func (e *Entry) verifyChecksum(in, data []byte, offset uint64, order binary.ByteOrder) error {
+	start, end := uint64(0), offset/8
+	if len(e.ChecksumRange) == 2 {
+		start, end = e.ChecksumRange[0], e.ChecksumRange[1]
+	} else if offset%8 != 0 {
+		return fmt.Errorf("checksum %q not byte-aligned", e.Name)
+	}
+	if end > uint64(len(in)) {
+		return fmt.Errorf("checksum range of %q exceeds data length %d", e.Name, len(in))
+	}
+
+	switch e.Type {
+	case "crc16":
+		raw, err := convertNumericType(data, "uint16", order)
+		if err != nil {
+			return err
+		}
+		expected := raw.(uint16)
+		if actual := crc16(in[start:end], e.crc16Poly, e.crc16Init); actual != expected {
+			return fmt.Errorf("checksum mismatch for %q: computed 0x%04x, got 0x%04x", e.Name, actual, expected)
+		}
+	case "crc32":
+		raw, err := convertNumericType(data, "uint32", order)
+		if err != nil {
+			return err
+		}
+		expected := raw.(uint32)
+		if actual := crc32.Checksum(in[start:end], e.crc32Table); actual != expected {
+			return fmt.Errorf("checksum mismatch for %q: computed 0x%08x, got 0x%08x", e.Name, actual, expected)
+		}
+	default:
+		return errors.New("unknown checksum type")
+	}
+
+	return nil
+}
+
+func crc16(data []byte, poly, crc uint16) uint16 {
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = (crc << 1) ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func parseHexUint(s string, size int) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(strings.ToLower(s), "0x"), 16, size)
+}
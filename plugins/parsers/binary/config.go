@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +32,9 @@ type Config struct {
 	MetricName string  `toml:"metric_name"`
 	Filter     *Filter `toml:"filter"`
 	Entries    []Entry `toml:"entries"`
+	EmitRaw    bool    `toml:"emit_raw"`
+
+	references map[string]bool
 }
 
 func (c *Config) preprocess(defaultName string) error {
@@ -69,6 +73,8 @@ func (c *Config) preprocess(defaultName string) error {
 	// Preprocess entries part
 	var hasField, hasMeasurement bool
 	defined := make(map[string]bool)
+	integers := make(map[string]bool)
+	c.references = make(map[string]bool)
 	for i, e := range c.Entries {
 		if err := e.check(); err != nil {
 			return fmt.Errorf("entry %q (%d): %w", e.Name, i, err)
@@ -76,7 +82,27 @@ func (c *Config) preprocess(defaultName string) error {
 		// Store the normalized entry
 		c.Entries[i] = e
 
-		if e.Omit {
+		// Length and count references must point to a previous integer entry
+		if e.LengthRef != "" {
+			if !integers[e.LengthRef] {
+				return fmt.Errorf("entry %q (%d): length reference %q is not a preceding integer entry", e.Name, i, e.LengthRef)
+			}
+			c.references[e.LengthRef] = true
+		}
+		if e.CountRef != "" {
+			if !integers[e.CountRef] {
+				return fmt.Errorf("entry %q (%d): count reference %q is not a preceding integer entry", e.Name, i, e.CountRef)
+			}
+			c.references[e.CountRef] = true
+		}
+		switch e.Type {
+		case "uint8", "int8", "uint16", "int16", "uint32", "int32", "uint64", "int64":
+			if e.Name != "" {
+				integers[e.Name] = true
+			}
+		}
+
+		if e.Omit || e.Assignment == "checksum" {
 			continue
 		}
 
@@ -88,6 +114,11 @@ func (c *Config) preprocess(defaultName string) error {
 		defined[key] = true
 		hasMeasurement = hasMeasurement || e.Assignment == "measurement"
 		hasField = hasField || e.Assignment == "field"
+		if e.Assignment == "repeat" {
+			for _, r := range e.Entries {
+				hasField = hasField || r.Assignment == "field"
+			}
+		}
 	}
 
 	if !hasMeasurement && c.MetricName == "" {
@@ -96,7 +127,10 @@ func (c *Config) preprocess(defaultName string) error {
 		}
 		c.MetricName = defaultName
 	}
-	if !hasField {
+	if c.EmitRaw && defined["field_raw_hex"] {
+		return errors.New("field \"raw_hex\" conflicts with 'emit_raw'")
+	}
+	if !hasField && !c.EmitRaw {
 		return errors.New("no field defined")
 	}
 
@@ -137,47 +171,131 @@ func (c *Config) matches(in []byte) bool {
 	return true
 }
 
-func (c *Config) collect(in []byte, order binary.ByteOrder, defaultTime time.Time) (telegraf.Metric, error) {
+func (c *Config) collect(in []byte, order binary.ByteOrder, defaultTime time.Time) ([]telegraf.Metric, error) {
 	t := defaultTime
 	name := c.MetricName
 	tags := make(map[string]string)
 	fields := make(map[string]interface{})
 
+	type record struct {
+		tags   map[string]string
+		fields map[string]interface{}
+	}
+	var records []record
+
 	var offset uint64
+	values := make(map[string]uint64, len(c.references))
 	for _, e := range c.Entries {
-		data, n, err := e.extract(in, offset)
+		var err error
+		offset, err = e.position(offset)
 		if err != nil {
 			return nil, err
 		}
+
+		// Handle repeated groups of entries
+		if e.Assignment == "repeat" {
+			count, found := values[e.CountRef]
+			if e.CountRef == "" {
+				count, found = e.Count, true
+			}
+			if !found {
+				return nil, fmt.Errorf("count reference %q not found for %q", e.CountRef, e.Name)
+			}
+			for i := uint64(0); i < count; i++ {
+				idx := strconv.FormatUint(i, 10)
+				rtags := tags
+				rfields := fields
+				if e.Repeat == "metrics" {
+					rtags = map[string]string{e.Name: idx}
+					rfields = make(map[string]interface{}, len(e.Entries))
+				}
+				for _, r := range e.Entries {
+					offset, err = r.position(offset)
+					if err != nil {
+						return nil, err
+					}
+					data, n, err := r.extract(in, offset, values)
+					if err != nil {
+						return nil, err
+					}
+					offset += n
+
+					key := r.Name
+					if e.Repeat != "metrics" {
+						key += "_" + idx
+					}
+					if err := r.assign(key, data, r.byteOrder(order), rtags, rfields); err != nil {
+						return nil, err
+					}
+				}
+				if e.Repeat == "metrics" {
+					records = append(records, record{tags: rtags, fields: rfields})
+				}
+			}
+			continue
+		}
+
+		data, n, err := e.extract(in, offset, values)
+		if err != nil {
+			return nil, err
+		}
+		entryOrder := e.byteOrder(order)
+		if e.Assignment == "checksum" {
+			if err := e.verifyChecksum(in, data, offset, entryOrder); err != nil {
+				return nil, err
+			}
+		}
 		offset += n
 
-		switch e.Assignment {
-		case "measurement":
-			name = convertStringType(data)
-		case "field":
-			v, err := e.convertType(data, order)
+		// Remember values used as length or count of subsequent entries
+		if c.references[e.Name] {
+			raw, err := convertNumericType(data, e.Type, entryOrder)
 			if err != nil {
-				return nil, fmt.Errorf("field %q failed: %w", e.Name, err)
+				return nil, fmt.Errorf("reference %q failed: %w", e.Name, err)
 			}
-			fields[e.Name] = v
-		case "tag":
-			raw, err := e.convertType(data, order)
+			v, err := internal.ToUint64(raw)
 			if err != nil {
-				return nil, fmt.Errorf("tag %q failed: %w", e.Name, err)
+				return nil, fmt.Errorf("reference %q failed: %w", e.Name, err)
 			}
-			v, err := internal.ToString(raw)
-			if err != nil {
-				return nil, fmt.Errorf("tag %q failed: %w", e.Name, err)
+			values[e.Name] = v
+		}
+
+		switch e.Assignment {
+		case "measurement":
+			name = convertStringType(data)
+		case "field", "tag":
+			if err := e.assign(e.Name, data, entryOrder, tags, fields); err != nil {
+				return nil, err
 			}
-			tags[e.Name] = v
 		case "time":
 			var err error
-			t, err = e.convertTimeType(data, order)
+			t, err = e.convertTimeType(data, entryOrder)
 			if err != nil {
 				return nil, fmt.Errorf("time failed: %w", err)
 			}
 		}
 	}
 
-	return metric.New(name, tags, fields, t), nil
+	if c.EmitRaw {
+		fields["raw_hex"] = hex.EncodeToString(in)
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(records)+1)
+	if len(fields) > 0 || len(records) == 0 {
+		metrics = append(metrics, metric.New(name, tags, fields, t))
+	}
+
+	// Create a metric per repetition containing the common tags
+	for _, r := range records {
+		rtags := make(map[string]string, len(tags)+len(r.tags))
+		for k, v := range tags {
+			rtags[k] = v
+		}
+		for k, v := range r.tags {
+			rtags[k] = v
+		}
+		metrics = append(metrics, metric.New(name, rtags, r.fields, t))
+	}
+
+	return metrics, nil
 }
@@ -5,24 +5,44 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"math"
 	"strings"
 	"time"
 
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/parsers"
 )
 
 type Entry struct {
-	Name       string `toml:"name"`
-	Type       string `toml:"type"`
-	Bits       uint64 `toml:"bits"`
-	Omit       bool   `toml:"omit"`
-	Terminator string `toml:"terminator"`
-	Timezone   string `toml:"timezone"`
-	Assignment string `toml:"assignment"`
+	Name         string  `toml:"name"`
+	Type         string  `toml:"type"`
+	Bits         uint64  `toml:"bits"`
+	Omit         bool    `toml:"omit"`
+	Terminator   string  `toml:"terminator"`
+	Timezone     string  `toml:"timezone"`
+	Assignment   string  `toml:"assignment"`
+	LengthRef    string  `toml:"length_ref"`
+	Endianness   string  `toml:"endianness"`
+	DurationUnit string  `toml:"duration_unit"`
+	Offset       *uint64 `toml:"offset"`
+	Align        uint64  `toml:"align"`
+
+	Polynomial    string   `toml:"polynomial"`
+	ChecksumInit  string   `toml:"checksum_init"`
+	ChecksumRange []uint64 `toml:"checksum_range"`
+
+	Count    uint64  `toml:"count"`
+	CountRef string  `toml:"count_ref"`
+	Repeat   string  `toml:"repeat"`
+	Entries  []Entry `toml:"entries"`
 
 	termination []byte
 	location    *time.Location
+	converter   binary.ByteOrder
+	crc16Poly   uint16
+	crc16Init   uint16
+	crc32Table  *crc32.Table
 }
 
 func (e *Entry) check() error {
@@ -33,8 +53,41 @@ func (e *Entry) check() error {
 		e.Type = strings.ToLower(e.Type)
 	}
 
+	// Check for an entry-specific endianness
+	switch e.Endianness {
+	case "":
+	case "le":
+		e.converter = binary.LittleEndian
+	case "be":
+		e.converter = binary.BigEndian
+	case "host":
+		e.converter = internal.HostEndianness
+	default:
+		return fmt.Errorf("unknown endianness %q", e.Endianness)
+	}
+
+	if e.Offset != nil && e.Align != 0 {
+		return errors.New("cannot use 'offset' and 'align' together")
+	}
+
+	// Handle checksum entries
+	if e.Type == "crc16" || e.Type == "crc32" {
+		return e.checkChecksum()
+	}
+
+	// Handle repeated groups of entries
+	if e.Type == "repeat" {
+		return e.checkRepeat()
+	}
+
 	// Handle omitted fields
 	if e.Omit {
+		if e.LengthRef != "" {
+			if e.Type != "string" || e.Bits != 0 {
+				return errors.New("'length_ref' requires type 'string' without 'bits'")
+			}
+			return nil
+		}
 		if e.Bits == 0 && e.Type == "" {
 			return errors.New("neither type nor bits given")
 		}
@@ -108,7 +161,7 @@ func (e *Entry) check() error {
 	switch e.Type {
 	case "uint8", "int8", "uint16", "int16", "uint32", "int32", "uint64", "int64":
 		fallthrough
-	case "float32", "float64":
+	case "float16", "float32", "float64":
 		bits, err := bitsForType(e.Type)
 		if err != nil {
 			return err
@@ -123,7 +176,15 @@ func (e *Entry) check() error {
 		if e.Bits == 0 {
 			e.Bits = 1
 		}
-	case "string":
+	case "string", "duration":
+		// Check length reference, the length is determined at parse time
+		if e.LengthRef != "" {
+			if e.Bits != 0 || e.Terminator != "" {
+				return fmt.Errorf("cannot use 'length_ref' together with 'bits' or terminator for %q", e.Name)
+			}
+			break
+		}
+
 		// Check termination
 		switch e.Terminator {
 		case "", "fixed":
@@ -151,6 +212,12 @@ func (e *Entry) check() error {
 		if e.Bits%8 != 0 {
 			return fmt.Errorf("non-byte length for string field %q", e.Name)
 		}
+
+		if e.Type == "duration" {
+			if err := parsers.CheckDurationUnit(e.DurationUnit); err != nil {
+				return fmt.Errorf("%w for %q", err, e.Name)
+			}
+		}
 	case "":
 		if defaultType == "" {
 			return fmt.Errorf("no type for %q", e.Name)
@@ -162,16 +229,121 @@ func (e *Entry) check() error {
 		}
 	}
 
+	if e.LengthRef != "" && e.Type != "string" {
+		return fmt.Errorf("'length_ref' requires type 'string' for %q", e.Name)
+	}
+
 	return nil
 }
 
-func (e *Entry) extract(in []byte, offset uint64) ([]byte, uint64, error) {
+func (e *Entry) checkRepeat() error {
+	e.Assignment = "repeat"
+
+	if e.Name == "" {
+		return errors.New("missing name")
+	}
+	if e.Count != 0 && e.CountRef != "" {
+		return fmt.Errorf("cannot use 'count' and 'count_ref' together for %q", e.Name)
+	}
+	if e.Count == 0 && e.CountRef == "" {
+		return fmt.Errorf("require 'count' or 'count_ref' for %q", e.Name)
+	}
+	switch e.Repeat {
+	case "":
+		e.Repeat = "fields"
+	case "fields", "metrics":
+	default:
+		return fmt.Errorf("unknown repeat mode %q for %q", e.Repeat, e.Name)
+	}
+	if len(e.Entries) == 0 {
+		return fmt.Errorf("no entries to repeat for %q", e.Name)
+	}
+
+	for i, r := range e.Entries {
+		if err := r.check(); err != nil {
+			return fmt.Errorf("repeated entry %q (%d): %w", r.Name, i, err)
+		}
+		switch r.Assignment {
+		case "field", "tag":
+		default:
+			if !r.Omit {
+				return fmt.Errorf("repeated entry %q (%d): assignment %q not supported", r.Name, i, r.Assignment)
+			}
+		}
+		if r.LengthRef != "" {
+			return fmt.Errorf("repeated entry %q (%d): 'length_ref' not supported", r.Name, i)
+		}
+		if r.Offset != nil {
+			return fmt.Errorf("repeated entry %q (%d): 'offset' not supported", r.Name, i)
+		}
+		e.Entries[i] = r
+	}
+
+	return nil
+}
+
+// assign converts the data and adds the result as field or tag with the
+// given key to the respective map.
+func (e *Entry) assign(key string, data []byte, order binary.ByteOrder, tags map[string]string, fields map[string]interface{}) error {
+	switch e.Assignment {
+	case "field":
+		v, err := e.convertType(data, order)
+		if err != nil {
+			return fmt.Errorf("field %q failed: %w", key, err)
+		}
+		fields[key] = v
+	case "tag":
+		raw, err := e.convertType(data, order)
+		if err != nil {
+			return fmt.Errorf("tag %q failed: %w", key, err)
+		}
+		v, err := internal.ToString(raw)
+		if err != nil {
+			return fmt.Errorf("tag %q failed: %w", key, err)
+		}
+		tags[key] = v
+	}
+	return nil
+}
+
+// position returns the bit offset to start reading the entry at, i.e. the
+// absolute byte offset or the next byte boundary given by the alignment
+// of the entry. The position cannot move backwards.
+func (e *Entry) position(offset uint64) (uint64, error) {
+	if e.Offset != nil {
+		pos := *e.Offset * 8
+		if pos < offset {
+			return 0, fmt.Errorf("offset %d for %q lies before current position @%d", *e.Offset, e.Name, offset)
+		}
+		return pos, nil
+	}
+
+	if e.Align > 1 {
+		boundary := e.Align * 8
+		if rem := offset % boundary; rem != 0 {
+			offset += boundary - rem
+		}
+	}
+	return offset, nil
+}
+
+func (e *Entry) extract(in []byte, offset uint64, lengths map[string]uint64) ([]byte, uint64, error) {
+	if e.LengthRef != "" {
+		length, found := lengths[e.LengthRef]
+		if !found {
+			return nil, 0, fmt.Errorf("length reference %q not found for %q", e.LengthRef, e.Name)
+		}
+		bits := length * 8
+		data, err := extractPart(in, offset, bits)
+		return data, bits, err
+	}
+
 	if e.Bits > 0 {
 		data, err := extractPart(in, offset, e.Bits)
 		return data, e.Bits, err
 	}
 
-	if e.Type != "string" {
+	if e.Type != "string" && e.Type != "duration" {
 		return nil, 0, fmt.Errorf("unexpected entry: %v", e)
 	}
 
@@ -210,14 +382,31 @@ func (e *Entry) extract(in []byte, offset uint64) ([]byte, uint64, error) {
 	return data[:len(data)-len(e.termination)], n, nil
 }
 
+// byteOrder returns the entry-specific byte order if set or the given
+// default order otherwise.
+func (e *Entry) byteOrder(order binary.ByteOrder) binary.ByteOrder {
+	if e.converter != nil {
+		return e.converter
+	}
+	return order
+}
+
 func (e *Entry) convertType(in []byte, order binary.ByteOrder) (interface{}, error) {
 	switch e.Type {
-	case "uint8", "int8", "uint16", "int16", "uint32", "int32", "float32", "uint64", "int64", "float64":
+	case "int8", "int16", "int32", "int64":
+		v, err := convertNumericType(in, e.Type, order)
+		if err != nil {
+			return nil, err
+		}
+		return signExtend(v, e.Bits), nil
+	case "uint8", "uint16", "uint32", "float16", "float32", "uint64", "float64":
 		return convertNumericType(in, e.Type, order)
 	case "bool":
 		return convertBoolType(in), nil
 	case "string":
 		return convertStringType(in), nil
+	case "duration":
+		return parsers.ParseDuration(convertStringType(in), e.DurationUnit)
 	}
 
 	return nil, fmt.Errorf("cannot handle type %q", e.Type)
@@ -290,6 +479,9 @@ func convertNumericType(in []byte, t string, order binary.ByteOrder) (interface{
 	case "int64":
 		v := order.Uint64(buf)
 		return int64(v), nil
+	case "float16":
+		v := order.Uint16(buf)
+		return float16frombits(v), nil
 	case "float32":
 		v := order.Uint32(buf)
 		return math.Float32frombits(v), nil
@@ -300,6 +492,60 @@ func convertNumericType(in []byte, t string, order binary.ByteOrder) (interface{
 	return nil, fmt.Errorf("no numeric type %q", t)
 }
 
+// float16frombits converts the given IEEE 754 half-precision representation
+// to float64 including subnormal numbers, infinities and NaN.
+func float16frombits(v uint16) float64 {
+	exponent := int(v>>10) & 0x1f
+	fraction := float64(v & 0x3ff)
+
+	var f float64
+	switch exponent {
+	case 0:
+		// Zero and subnormal numbers
+		f = math.Ldexp(fraction, -24)
+	case 0x1f:
+		if fraction != 0 {
+			return math.NaN()
+		}
+		f = math.Inf(1)
+	default:
+		f = math.Ldexp(1+fraction/1024, exponent-15)
+	}
+
+	if v&0x8000 != 0 {
+		return math.Copysign(f, -1)
+	}
+	return f
+}
+
+// signExtend interprets the lowest 'bits' of the given signed value as a
+// two's-complement number and extends the sign to the full type width.
+func signExtend(v interface{}, bits uint64) interface{} {
+	switch x := v.(type) {
+	case int8:
+		if bits > 0 && bits < 8 {
+			shift := 8 - bits
+			return (x << shift) >> shift
+		}
+	case int16:
+		if bits > 0 && bits < 16 {
+			shift := 16 - bits
+			return (x << shift) >> shift
+		}
+	case int32:
+		if bits > 0 && bits < 32 {
+			shift := 32 - bits
+			return (x << shift) >> shift
+		}
+	case int64:
+		if bits > 0 && bits < 64 {
+			shift := 64 - bits
+			return (x << shift) >> shift
+		}
+	}
+	return v
+}
+
 func convertBoolType(in []byte) bool {
 	for _, x := range in {
 		if x != 0 {
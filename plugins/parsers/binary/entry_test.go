@@ -1,6 +1,8 @@
 package binary
 
 import (
+	"encoding/binary"
+	"math"
 	"testing"
 	"time"
 
@@ -12,8 +14,16 @@ func TestEntryExtract(t *testing.T) {
 	testdata := []byte{0x01, 0x02, 0x03, 0x04}
 
 	e := &Entry{Type: "uint64"}
-	_, _, err := e.extract(testdata, 0)
-	require.EqualError(t, err, `unexpected entry: &{ uint64 0 false    [] <nil>}`)
+	_, _, err := e.extract(testdata, 0, nil)
+	require.EqualError(t, err, `unexpected entry: &{ uint64 0 false       <nil> 0   [] 0   [] [] <nil> <nil> 0 0 <nil>}`)
+}
+
+func TestEntryExtractLengthRefMissing(t *testing.T) {
+	testdata := []byte{0x01, 0x02, 0x03, 0x04}
+
+	e := &Entry{Name: "name", Type: "string", LengthRef: "length"}
+	_, _, err := e.extract(testdata, 0, map[string]uint64{})
+	require.EqualError(t, err, `length reference "length" not found for "name"`)
 }
 
 func TestEntryConvertType(t *testing.T) {
@@ -24,6 +34,73 @@ func TestEntryConvertType(t *testing.T) {
 	require.EqualError(t, err, `cannot handle type "garbage"`)
 }
 
+func TestEntryConvertTypeSignExtension(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    *Entry
+		data     []byte
+		expected interface{}
+	}{
+		{
+			name:     "12-bit minus one",
+			entry:    &Entry{Type: "int16", Bits: 12},
+			data:     []byte{0x0F, 0xFF},
+			expected: int16(-1),
+		},
+		{
+			name:     "12-bit minimum",
+			entry:    &Entry{Type: "int16", Bits: 12},
+			data:     []byte{0x08, 0x00},
+			expected: int16(-2048),
+		},
+		{
+			name:     "12-bit maximum",
+			entry:    &Entry{Type: "int16", Bits: 12},
+			data:     []byte{0x07, 0xFF},
+			expected: int16(2047),
+		},
+		{
+			name:     "4-bit negative",
+			entry:    &Entry{Type: "int8", Bits: 4},
+			data:     []byte{0x0E},
+			expected: int8(-2),
+		},
+		{
+			name:     "24-bit negative",
+			entry:    &Entry{Type: "int32", Bits: 24},
+			data:     []byte{0xFF, 0xFF, 0xFE},
+			expected: int32(-2),
+		},
+		{
+			name:     "full width",
+			entry:    &Entry{Type: "int16", Bits: 16},
+			data:     []byte{0x0F, 0xFF},
+			expected: int16(4095),
+		},
+		{
+			name:     "unsigned not extended",
+			entry:    &Entry{Type: "uint16", Bits: 12},
+			data:     []byte{0x0F, 0xFF},
+			expected: uint16(4095),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.entry.convertType(tt.data, binary.BigEndian)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestCRC16(t *testing.T) {
+	// Check value of CRC-16/CCITT-FALSE
+	require.Equal(t, uint16(0x29B1), crc16([]byte("123456789"), defaultCRC16Polynomial, defaultCRC16Init))
+	// Check value of CRC-16/XMODEM
+	require.Equal(t, uint16(0x31C3), crc16([]byte("123456789"), 0x1021, 0x0000))
+}
+
 func TestEntryConvertTimeType(t *testing.T) {
 	testdata := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}
 
@@ -41,3 +118,31 @@ func TestConvertNumericType(t *testing.T) {
 	_, err = convertNumericType(testdata, "uint8", internal.HostEndianness)
 	require.EqualError(t, err, `too many bytes 4 vs 1`)
 }
+
+func TestFloat16FromBits(t *testing.T) {
+	tests := []struct {
+		name     string
+		bits     uint16
+		expected float64
+	}{
+		{name: "one", bits: 0x3c00, expected: 1.0},
+		{name: "negative two", bits: 0xc000, expected: -2.0},
+		{name: "fraction", bits: 0x3555, expected: 0.333251953125},
+		{name: "max", bits: 0x7bff, expected: 65504.0},
+		{name: "smallest normal", bits: 0x0400, expected: math.Ldexp(1, -14)},
+		{name: "smallest subnormal", bits: 0x0001, expected: math.Ldexp(1, -24)},
+		{name: "largest subnormal", bits: 0x03ff, expected: math.Ldexp(1023, -24)},
+		{name: "zero", bits: 0x0000, expected: 0.0},
+		{name: "positive infinity", bits: 0x7c00, expected: math.Inf(1)},
+		{name: "negative infinity", bits: 0xfc00, expected: math.Inf(-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, float16frombits(tt.bits))
+		})
+	}
+
+	require.True(t, math.Signbit(float16frombits(0x8000)))
+	require.True(t, math.IsNaN(float16frombits(0x7e00)))
+}
@@ -112,7 +112,7 @@ func (p *Parser) Parse(data []byte) ([]telegraf.Metric, error) {
 		if err != nil {
 			return nil, err
 		}
-		metrics = append(metrics, m)
+		metrics = append(metrics, m...)
 	}
 	if matches == 0 && !p.AllowNoMatch {
 		return nil, errors.New("no matching configuration")
@@ -202,7 +202,7 @@ func bitsForType(t string) (uint64, error) {
 	switch t {
 	case "uint8", "int8":
 		return 8, nil
-	case "uint16", "int16":
+	case "uint16", "int16", "float16":
 		return 16, nil
 	case "uint32", "int32", "float32":
 		return 32, nil
@@ -122,6 +122,75 @@ func TestInitInvalid(t *testing.T) {
 			metric:   "binary",
 			expected: `config 0 invalid: multiple definitions of "measurement"`,
 		},
+		{
+			name: "undefined length reference",
+			config: []Config{{
+				Entries: []Entry{
+					{
+						Name:      "name",
+						Type:      "string",
+						LengthRef: "length",
+					},
+				},
+			}},
+			metric:   "binary",
+			expected: `config 0 invalid: entry "name" (0): length reference "length" is not a preceding integer entry`,
+		},
+		{
+			name: "non-integer length reference",
+			config: []Config{{
+				Entries: []Entry{
+					{
+						Name: "length",
+						Type: "float32",
+					},
+					{
+						Name:      "name",
+						Type:      "string",
+						LengthRef: "length",
+					},
+				},
+			}},
+			metric:   "binary",
+			expected: `config 0 invalid: entry "name" (1): length reference "length" is not a preceding integer entry`,
+		},
+		{
+			name: "length reference with bits",
+			config: []Config{{
+				Entries: []Entry{
+					{
+						Name: "length",
+						Type: "uint16",
+					},
+					{
+						Name:      "name",
+						Type:      "string",
+						Bits:      16,
+						LengthRef: "length",
+					},
+				},
+			}},
+			metric:   "binary",
+			expected: `config 0 invalid: entry "name" (1): cannot use 'length_ref' together with 'bits' or terminator for "name"`,
+		},
+		{
+			name: "length reference for non-string",
+			config: []Config{{
+				Entries: []Entry{
+					{
+						Name: "length",
+						Type: "uint16",
+					},
+					{
+						Name:      "value",
+						Type:      "uint32",
+						LengthRef: "length",
+					},
+				},
+			}},
+			metric:   "binary",
+			expected: `config 0 invalid: entry "value" (1): 'length_ref' requires type 'string' for "value"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -823,6 +892,26 @@ func TestParseInvalid(t *testing.T) {
 			},
 			expected: `terminator not found for "measurement"`,
 		},
+		{
+			name: "length-prefixed string too short",
+			data: []interface{}{
+				uint16(12), // length
+				"short",    // name
+			},
+			entries: []Entry{
+				{
+					Name: "length",
+					Type: "uint16",
+					Omit: true,
+				},
+				{
+					Name:      "name",
+					Type:      "string",
+					LengthRef: "length",
+				},
+			},
+			expected: `out-of-bounds @16 with 96 bits`,
+		},
 		{
 			name: "invalid time",
 			data: []interface{}{
@@ -1372,6 +1461,96 @@ func TestParse(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "length-prefixed strings",
+			data: []interface{}{
+				uint16(0x0102), // address
+				uint16(9),      // length of name
+				"sensor-01",    // name
+				uint8(3),       // length of unit
+				"kPa",          // unit
+				float64(42.5),  // value
+			},
+			entries: []Entry{
+				{
+					Name:       "address",
+					Type:       "uint16",
+					Assignment: "tag",
+				},
+				{
+					Name: "name_length",
+					Type: "uint16",
+					Omit: true,
+				},
+				{
+					Name:       "name",
+					Type:       "string",
+					LengthRef:  "name_length",
+					Assignment: "tag",
+				},
+				{
+					Name: "unit_length",
+					Type: "uint8",
+				},
+				{
+					Name:      "unit",
+					Type:      "string",
+					LengthRef: "unit_length",
+				},
+				{
+					Name: "value",
+					Type: "float64",
+				},
+			},
+			ignoreTime: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{"address": "258", "name": "sensor-01"},
+					map[string]interface{}{
+						"unit_length": uint8(3),
+						"unit":        "kPa",
+						"value":       float64(42.5),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "empty length-prefixed string",
+			data: []interface{}{
+				uint8(0),      // length of name
+				float64(42.5), // value
+			},
+			entries: []Entry{
+				{
+					Name: "name_length",
+					Type: "uint8",
+					Omit: true,
+				},
+				{
+					Name:      "name",
+					Type:      "string",
+					LengthRef: "name_length",
+				},
+				{
+					Name: "value",
+					Type: "float64",
+				},
+			},
+			ignoreTime: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{},
+					map[string]interface{}{
+						"name":  "",
+						"value": float64(42.5),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1403,6 +1582,733 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseMixedEndianness(t *testing.T) {
+	for _, endianness := range []string{"be", "le", "host"} {
+		t.Run(endianness, func(t *testing.T) {
+			parser := &Parser{
+				Endianness: endianness,
+				Configs: []Config{{
+					Entries: []Entry{
+						{
+							Name:       "command",
+							Type:       "uint16",
+							Endianness: "be",
+							Assignment: "tag",
+						},
+						{
+							Name:       "value",
+							Type:       "uint32",
+							Endianness: "le",
+						},
+					},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.NoError(t, parser.Init())
+
+			var data []byte
+			data = binary.BigEndian.AppendUint16(data, 0xAB42)
+			data = binary.LittleEndian.AppendUint32(data, 0x01020304)
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{"command": "43842"},
+					map[string]interface{}{"value": uint32(0x01020304)},
+					time.Unix(0, 0),
+				),
+			}
+
+			metrics, err := parser.Parse(data)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     string
+		expected int64
+	}{
+		{
+			name:     "default unit",
+			expected: 5400000000000,
+		},
+		{
+			name:     "seconds",
+			unit:     "s",
+			expected: 5400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Configs: []Config{{
+					Entries: []Entry{
+						{
+							Name:         "uptime",
+							Type:         "duration",
+							Terminator:   "null",
+							DurationUnit: tt.unit,
+						},
+						{
+							Name: "value",
+							Type: "uint8",
+						},
+					},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.NoError(t, parser.Init())
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{},
+					map[string]interface{}{
+						"uptime": tt.expected,
+						"value":  uint8(42),
+					},
+					time.Unix(0, 0),
+				),
+			}
+
+			metrics, err := parser.Parse(append([]byte("1h30m\x00"), 42))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseSignedBitField(t *testing.T) {
+	parser := &Parser{
+		Endianness: "be",
+		Configs: []Config{{
+			Entries: []Entry{
+				{
+					Name: "reading",
+					Type: "int16",
+					Bits: 12,
+				},
+				{
+					Name: "flag",
+					Type: "bool",
+					Bits: 1,
+				},
+				{
+					Bits: 3,
+					Omit: true,
+				},
+			},
+		}},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binary",
+			map[string]string{},
+			map[string]interface{}{
+				"reading": int16(-1),
+				"flag":    true,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	metrics, err := parser.Parse([]byte{0xFF, 0xF8})
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+}
+
+func TestParseFloat16(t *testing.T) {
+	tests := []struct {
+		name       string
+		endianness string
+		input      []byte
+	}{
+		{
+			name:       "big endian",
+			endianness: "be",
+			input:      []byte{0x3c, 0x00, 0xc1, 0x00},
+		},
+		{
+			name:       "little endian",
+			endianness: "le",
+			input:      []byte{0x00, 0x3c, 0x00, 0xc1},
+		},
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binary",
+			map[string]string{},
+			map[string]interface{}{
+				"scale":       1.0,
+				"temperature": -2.5,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Endianness: tt.endianness,
+				Configs: []Config{{
+					Entries: []Entry{
+						{Name: "scale", Type: "float16"},
+						{Name: "temperature", Type: "float16"},
+					},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.NoError(t, parser.Init())
+
+			metrics, err := parser.Parse(tt.input)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseAlignment(t *testing.T) {
+	offset := uint64(12)
+	parser := &Parser{
+		Endianness: "le",
+		Configs: []Config{{
+			Entries: []Entry{
+				{Name: "flags", Type: "uint8"},
+				{Name: "counter", Type: "uint32", Align: 4},
+				{Name: "status", Type: "uint8"},
+				{Name: "value", Type: "uint16", Offset: &offset},
+			},
+		}},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.NoError(t, parser.Init())
+
+	input := []byte{
+		0x01, 0xff, 0xff, 0xff, // flags and padding
+		0x2a, 0x00, 0x00, 0x00, // counter
+		0x03, 0xff, 0xff, 0xff, // status and padding
+		0x10, 0x27, // value
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"binary",
+			map[string]string{},
+			map[string]interface{}{
+				"flags":   uint8(1),
+				"counter": uint32(42),
+				"status":  uint8(3),
+				"value":   uint16(10000),
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	metrics, err := parser.Parse(input)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+
+	// Offsets must not point before the current position
+	offset = 4
+	_, err = parser.Parse(input)
+	require.EqualError(t, err, `offset 4 for "value" lies before current position @72`)
+}
+
+func TestInitInvalidAlignment(t *testing.T) {
+	offset := uint64(4)
+	parser := &Parser{
+		Configs: []Config{{
+			Entries: []Entry{
+				{Name: "value", Type: "uint32", Align: 4, Offset: &offset},
+			},
+		}},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.ErrorContains(t, parser.Init(), "cannot use 'offset' and 'align' together")
+}
+
+func TestParseDiscriminator(t *testing.T) {
+	parser := &Parser{
+		Endianness: "be",
+		Configs: []Config{
+			{
+				MetricName: "temperature",
+				Entries: []Entry{
+					{Type: "uint8", Omit: true},
+					{Name: "value", Type: "int16"},
+				},
+				Filter: &Filter{
+					Selection: []BinaryPart{{Offset: 0, Bits: 8, Match: "0x01"}},
+				},
+			},
+			{
+				MetricName: "position",
+				Entries: []Entry{
+					{Type: "uint8", Omit: true},
+					{Name: "x", Type: "uint8"},
+					{Name: "y", Type: "uint8"},
+				},
+				Filter: &Filter{
+					Selection: []BinaryPart{{Offset: 0, Bits: 8, Match: "0x02"}},
+				},
+			},
+		},
+		Log: testutil.Logger{Name: "parsers.binary"},
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.Parse([]byte{0x01, 0xff, 0xf6})
+	require.NoError(t, err)
+	expected := []telegraf.Metric{
+		metric.New("temperature", map[string]string{}, map[string]interface{}{"value": int16(-10)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	actual, err = parser.Parse([]byte{0x02, 0x03, 0x04})
+	require.NoError(t, err)
+	expected = []telegraf.Metric{
+		metric.New("position", map[string]string{}, map[string]interface{}{"x": uint8(3), "y": uint8(4)}, time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	_, err = parser.Parse([]byte{0x03, 0x00, 0x00})
+	require.EqualError(t, err, "no matching configuration")
+}
+
+func TestParseChecksum(t *testing.T) {
+	payload := []byte("123456789")
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		checksum []byte
+		expected string
+	}{
+		{
+			name:     "crc16",
+			entry:    Entry{Type: "crc16"},
+			checksum: []byte{0x29, 0xB1},
+		},
+		{
+			name:     "crc16 mismatch",
+			entry:    Entry{Type: "crc16"},
+			checksum: []byte{0x29, 0xB2},
+			expected: `checksum mismatch for "crc16": computed 0x29b1, got 0x29b2`,
+		},
+		{
+			name:     "crc16 xmodem",
+			entry:    Entry{Type: "crc16", ChecksumInit: "0x0000"},
+			checksum: []byte{0x31, 0xC3},
+		},
+		{
+			name:     "crc16 with range",
+			entry:    Entry{Type: "crc16", ChecksumRange: []uint64{2, 9}},
+			checksum: binary.BigEndian.AppendUint16(nil, crc16(payload[2:9], defaultCRC16Polynomial, defaultCRC16Init)),
+		},
+		{
+			name:     "crc32",
+			entry:    Entry{Name: "frame_crc", Type: "crc32"},
+			checksum: []byte{0xCB, 0xF4, 0x39, 0x26},
+		},
+		{
+			name:     "crc32c in normal notation",
+			entry:    Entry{Name: "frame_crc", Type: "crc32", Polynomial: "0x1EDC6F41"},
+			checksum: []byte{0xE3, 0x06, 0x92, 0x83},
+		},
+		{
+			name:     "crc32 mismatch",
+			entry:    Entry{Name: "frame_crc", Type: "crc32"},
+			checksum: []byte{0x00, 0x00, 0x00, 0x00},
+			expected: `checksum mismatch for "frame_crc": computed 0xcbf43926, got 0x00000000`,
+		},
+		{
+			name:     "range out of bounds",
+			entry:    Entry{Type: "crc16", ChecksumRange: []uint64{0, 20}},
+			checksum: []byte{0x29, 0xB1},
+			expected: `checksum range of "crc16" exceeds data length 11`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Endianness: "be",
+				Configs: []Config{{
+					Entries: []Entry{
+						{
+							Name: "payload",
+							Type: "string",
+							Bits: uint64(len(payload) * 8),
+						},
+						tt.entry,
+					},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.NoError(t, parser.Init())
+
+			data := append(append([]byte{}, payload...), tt.checksum...)
+			metrics, err := parser.Parse(data)
+			if tt.expected != "" {
+				require.EqualError(t, err, tt.expected)
+				return
+			}
+			require.NoError(t, err)
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{},
+					map[string]interface{}{"payload": "123456789"},
+					time.Unix(0, 0),
+				),
+			}
+			testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestInitInvalidChecksum(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected string
+	}{
+		{
+			name:     "invalid polynomial",
+			entry:    Entry{Type: "crc16", Polynomial: "0x12345"},
+			expected: `invalid polynomial for "crc16"`,
+		},
+		{
+			name:     "wrong bits",
+			entry:    Entry{Type: "crc32", Bits: 16},
+			expected: `checksum "crc32" requires 32 bits`,
+		},
+		{
+			name:     "init for crc32",
+			entry:    Entry{Type: "crc32", ChecksumInit: "0x0"},
+			expected: `'checksum_init' not supported for "crc32"`,
+		},
+		{
+			name:     "incomplete range",
+			entry:    Entry{Type: "crc16", ChecksumRange: []uint64{1}},
+			expected: `checksum range for "crc16" requires start and end`,
+		},
+		{
+			name:     "empty range",
+			entry:    Entry{Type: "crc16", ChecksumRange: []uint64{2, 2}},
+			expected: `invalid checksum range for "crc16"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Configs: []Config{{
+					Entries: []Entry{dummyEntry, tt.entry},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.ErrorContains(t, parser.Init(), tt.expected)
+		})
+	}
+}
+
+func TestParseEmitRaw(t *testing.T) {
+	parser := &Parser{
+		Endianness: "be",
+		Configs: []Config{
+			{
+				MetricName: "full",
+				EmitRaw:    true,
+				Entries: []Entry{
+					{Name: "address", Type: "uint16", Assignment: "tag"},
+					{Name: "value", Type: "uint8"},
+				},
+			},
+			{
+				MetricName: "raw",
+				EmitRaw:    true,
+			},
+			{
+				MetricName: "plain",
+				Entries: []Entry{
+					{Name: "value", Type: "uint8", Bits: 8},
+				},
+			},
+		},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"full",
+			map[string]string{"address": "258"},
+			map[string]interface{}{
+				"value":   uint8(13),
+				"raw_hex": "01020d",
+			},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"raw",
+			map[string]string{},
+			map[string]interface{}{"raw_hex": "01020d"},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"plain",
+			map[string]string{},
+			map[string]interface{}{"value": uint8(1)},
+			time.Unix(0, 0),
+		),
+	}
+
+	metrics, err := parser.Parse([]byte{0x01, 0x02, 0x0D})
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+}
+
+func TestInitEmitRawConflict(t *testing.T) {
+	parser := &Parser{
+		Configs: []Config{{
+			EmitRaw: true,
+			Entries: []Entry{
+				{Name: "raw_hex", Type: "uint8"},
+			},
+		}},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.EqualError(t, parser.Init(), `config 0 invalid: field "raw_hex" conflicts with 'emit_raw'`)
+}
+
+func TestParseRepeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected []telegraf.Metric
+	}{
+		{
+			name: "indexed fields with count reference",
+			entry: Entry{
+				Name:     "channels",
+				Type:     "repeat",
+				CountRef: "count",
+				Entries:  []Entry{{Name: "temp", Type: "uint8"}},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{"device": "1"},
+					map[string]interface{}{
+						"temp_0": uint8(21),
+						"temp_1": uint8(22),
+						"temp_2": uint8(23),
+						"status": uint8(0xFF),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "indexed fields with fixed count",
+			entry: Entry{
+				Name:    "channels",
+				Type:    "repeat",
+				Count:   3,
+				Entries: []Entry{{Name: "temp", Type: "uint8"}},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{"device": "1"},
+					map[string]interface{}{
+						"temp_0": uint8(21),
+						"temp_1": uint8(22),
+						"temp_2": uint8(23),
+						"status": uint8(0xFF),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "multiple metrics",
+			entry: Entry{
+				Name:     "channel",
+				Type:     "repeat",
+				CountRef: "count",
+				Repeat:   "metrics",
+				Entries:  []Entry{{Name: "temp", Type: "uint8"}},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"binary",
+					map[string]string{"device": "1"},
+					map[string]interface{}{"status": uint8(0xFF)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binary",
+					map[string]string{"device": "1", "channel": "0"},
+					map[string]interface{}{"temp": uint8(21)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binary",
+					map[string]string{"device": "1", "channel": "1"},
+					map[string]interface{}{"temp": uint8(22)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"binary",
+					map[string]string{"device": "1", "channel": "2"},
+					map[string]interface{}{"temp": uint8(23)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Configs: []Config{{
+					Entries: []Entry{
+						{Name: "device", Type: "uint8", Assignment: "tag"},
+						{Name: "count", Type: "uint8", Omit: true},
+						tt.entry,
+						{Name: "status", Type: "uint8"},
+					},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.NoError(t, parser.Init())
+
+			metrics, err := parser.Parse([]byte{0x01, 0x03, 21, 22, 23, 0xFF})
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, metrics, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseRepeatOutOfBounds(t *testing.T) {
+	parser := &Parser{
+		Configs: []Config{{
+			Entries: []Entry{
+				{Name: "count", Type: "uint8", Omit: true},
+				{
+					Name:     "channels",
+					Type:     "repeat",
+					CountRef: "count",
+					Entries:  []Entry{{Name: "temp", Type: "uint8"}},
+				},
+			},
+		}},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse([]byte{0x03, 21, 22})
+	require.EqualError(t, err, "out-of-bounds @24 with 8 bits")
+}
+
+func TestInitInvalidRepeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected string
+	}{
+		{
+			name:     "no count",
+			entry:    Entry{Name: "r", Type: "repeat", Entries: []Entry{dummyEntry}},
+			expected: `require 'count' or 'count_ref' for "r"`,
+		},
+		{
+			name:     "count and count reference",
+			entry:    Entry{Name: "r", Type: "repeat", Count: 2, CountRef: "dummy", Entries: []Entry{dummyEntry}},
+			expected: `cannot use 'count' and 'count_ref' together for "r"`,
+		},
+		{
+			name:     "unknown mode",
+			entry:    Entry{Name: "r", Type: "repeat", Count: 2, Repeat: "garbage", Entries: []Entry{dummyEntry}},
+			expected: `unknown repeat mode "garbage" for "r"`,
+		},
+		{
+			name:     "no entries",
+			entry:    Entry{Name: "r", Type: "repeat", Count: 2},
+			expected: `no entries to repeat for "r"`,
+		},
+		{
+			name: "nested time",
+			entry: Entry{
+				Name:    "r",
+				Type:    "repeat",
+				Count:   2,
+				Entries: []Entry{{Type: "unix", Assignment: "time"}},
+			},
+			expected: `repeated entry "time" (0): assignment "time" not supported`,
+		},
+		{
+			name:     "undefined count reference",
+			entry:    Entry{Name: "r", Type: "repeat", CountRef: "missing", Entries: []Entry{dummyEntry}},
+			expected: `count reference "missing" is not a preceding integer entry`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Configs: []Config{{
+					Entries: []Entry{dummyEntry, tt.entry},
+				}},
+				Log:        testutil.Logger{Name: "parsers.binary"},
+				metricName: "binary",
+			}
+			require.ErrorContains(t, parser.Init(), tt.expected)
+		})
+	}
+}
+
+func TestInitInvalidEntryEndianness(t *testing.T) {
+	parser := &Parser{
+		Configs: []Config{{
+			Entries: []Entry{
+				{
+					Name:       "value",
+					Type:       "uint32",
+					Endianness: "garbage",
+				},
+			},
+		}},
+		Log:        testutil.Logger{Name: "parsers.binary"},
+		metricName: "binary",
+	}
+	require.EqualError(t, parser.Init(), `config 0 invalid: entry "value" (0): unknown endianness "garbage"`)
+}
+
 func TestCases(t *testing.T) {
 	// Get all directories in testdata
 	folders, err := os.ReadDir("testcases")
@@ -0,0 +1,266 @@
+package cbor
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// ErrWrongType is returned if the document is neither a map nor an array of maps
+var ErrWrongType = errors.New("must be a map or an array of maps")
+
+type Parser struct {
+	MetricName     string   `toml:"metric_name"`
+	TagKeys        []string `toml:"tag_keys"`
+	NameKey        string   `toml:"cbor_name_key"`
+	TimeKey        string   `toml:"cbor_time_key"`
+	TimeFormat     string   `toml:"cbor_time_format"`
+	Timezone       string   `toml:"cbor_timezone"`
+	Strict         bool     `toml:"cbor_strict"`
+	ArrayIndexTag  string   `toml:"cbor_array_index_tag"`
+	HexByteStrings bool     `toml:"cbor_hex_byte_strings"`
+
+	DefaultTags map[string]string `toml:"-"`
+	Log         telegraf.Logger   `toml:"-"`
+
+	location  *time.Location
+	tagFilter filter.Filter
+	decoder   cbor.DecMode
+}
+
+func (p *Parser) Init() error {
+	var err error
+
+	p.tagFilter, err = filter.Compile(p.TagKeys)
+	if err != nil {
+		return fmt.Errorf("compiling tag-key filter failed: %w", err)
+	}
+
+	p.location = time.UTC
+	if p.Timezone != "" {
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		p.location = loc
+	}
+
+	// Decode maps with string keys and convert the standard date/time tags
+	// to time
+	p.decoder, err = cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}{}),
+		TimeTag:        cbor.DecTagOptional,
+	}.DecMode()
+	if err != nil {
+		return fmt.Errorf("creating decoder failed: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	timestamp := time.Now().UTC()
+
+	// The buffer might contain a sequence of CBOR documents
+	metrics := make([]telegraf.Metric, 0)
+	for len(buf) > 0 {
+		var data interface{}
+		remainder, err := p.decoder.UnmarshalFirst(buf, &data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding document failed: %w", err)
+		}
+		buf = remainder
+
+		switch v := data.(type) {
+		case map[string]interface{}:
+			m, err := p.parseObject(v, timestamp)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m)
+		case []interface{}:
+			m, err := p.parseArray(v, timestamp)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m...)
+		case nil:
+		default:
+			return nil, ErrWrongType
+		}
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("can not parse the line: %s, for data format: cbor ", line)
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) parseArray(data []interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
+	results := make([]telegraf.Metric, 0, len(data))
+
+	for i, item := range data {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, ErrWrongType
+		}
+
+		m, err := p.parseObject(obj, timestamp)
+		if err != nil {
+			if p.Strict {
+				return nil, err
+			}
+			p.Log.Debugf("Skipping element %d: %v", i, err)
+			continue
+		}
+		if p.ArrayIndexTag != "" {
+			m.AddTag(p.ArrayIndexTag, strconv.Itoa(i))
+		}
+		results = append(results, m)
+	}
+
+	return results, nil
+}
+
+func (p *Parser) parseObject(data map[string]interface{}, timestamp time.Time) (telegraf.Metric, error) {
+	fields := make(map[string]interface{})
+	if err := p.flatten("", data, fields); err != nil {
+		return nil, err
+	}
+
+	name := p.MetricName
+	if p.NameKey != "" {
+		if v, ok := fields[p.NameKey].(string); ok {
+			name = v
+		}
+	}
+
+	if p.TimeKey != "" {
+		ts, found := fields[p.TimeKey]
+		if !found {
+			return nil, errors.New("time key could not be found")
+		}
+
+		if t, ok := ts.(time.Time); ok {
+			timestamp = t
+		} else {
+			if p.TimeFormat == "" {
+				return nil, errors.New("use of 'cbor_time_key' requires 'cbor_time_format'")
+			}
+
+			var err error
+			timestamp, err = internal.ParseTimestamp(p.TimeFormat, ts, p.location)
+			if err != nil {
+				return nil, err
+			}
+		}
+		delete(fields, p.TimeKey)
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+
+	for k, v := range fields {
+		if p.tagFilter != nil && p.tagFilter.Match(k) {
+			tag, err := internal.ToString(v)
+			if err != nil {
+				return nil, fmt.Errorf("converting tag %q failed: %w", k, err)
+			}
+			tags[k] = tag
+			delete(fields, k)
+			continue
+		}
+		if t, ok := v.(time.Time); ok {
+			fields[k] = t.UnixNano()
+		}
+	}
+
+	return metric.New(name, tags, fields, timestamp), nil
+}
+
+// flatten adds all values of nested maps and arrays to the given fields
+// using underscores to join the keys.
+func (p *Parser) flatten(prefix string, v interface{}, fields map[string]interface{}) error {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, item := range t {
+			key := k
+			if prefix != "" {
+				key = prefix + "_" + k
+			}
+			if err := p.flatten(key, item, fields); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range t {
+			key := strconv.Itoa(i)
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+			if err := p.flatten(key, item, fields); err != nil {
+				return err
+			}
+		}
+	case int64, uint64, float64, string, bool, time.Time:
+		fields[prefix] = t
+	case []byte:
+		switch {
+		case p.HexByteStrings:
+			fields[prefix] = hex.EncodeToString(t)
+		case utf8.Valid(t):
+			fields[prefix] = string(t)
+		default:
+			fields[prefix] = base64.StdEncoding.EncodeToString(t)
+		}
+	case big.Int:
+		// Bignums exceeding the 64-bit range are kept as string
+		fields[prefix] = t.String()
+	case cbor.Tag:
+		return p.flatten(prefix, t.Content, fields)
+	case nil, cbor.SimpleValue:
+	default:
+		return fmt.Errorf("unexpected type %T for %q", v, prefix)
+	}
+	return nil
+}
+
+func init() {
+	parsers.Add("cbor",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{
+				MetricName: defaultMetricName,
+				Strict:     true,
+			}
+		})
+}
@@ -0,0 +1,271 @@
+package cbor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParseGolden(t *testing.T) {
+	// {"host": "a", "value": -3, "temp": 21.5}
+	input := []byte{
+		0xa3,
+		0x64, 'h', 'o', 's', 't', 0x61, 'a',
+		0x65, 'v', 'a', 'l', 'u', 'e', 0x22,
+		0x64, 't', 'e', 'm', 'p', 0xf9, 0x4d, 0x60,
+	}
+
+	parser := &Parser{
+		MetricName: "cbor",
+		TagKeys:    []string{"host"},
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"cbor",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"value": int64(-3),
+				"temp":  21.5,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	actual, err := parser.Parse(input)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    interface{}
+		expected []telegraf.Metric
+	}{
+		{
+			name: "nested map",
+			parser: &Parser{
+				TagKeys: []string{"device_name"},
+			},
+			input: map[string]interface{}{
+				"device": map[string]interface{}{
+					"name":    "sensor-1",
+					"battery": uint8(80),
+					"offset":  int16(-5),
+				},
+				"samples": []interface{}{1.5, float32(2.5)},
+				"state":   "ok",
+				"online":  true,
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"cbor",
+					map[string]string{"device_name": "sensor-1"},
+					map[string]interface{}{
+						"device_battery": uint64(80),
+						"device_offset":  int64(-5),
+						"samples_0":      1.5,
+						"samples_1":      2.5,
+						"state":          "ok",
+						"online":         true,
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "array of objects",
+			parser: &Parser{
+				TagKeys:       []string{"name"},
+				ArrayIndexTag: "index",
+			},
+			input: []interface{}{
+				map[string]interface{}{"name": "a", "value": 1},
+				map[string]interface{}{"name": "b", "value": 2},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"cbor",
+					map[string]string{"name": "a", "index": "0"},
+					map[string]interface{}{"value": uint64(1)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"cbor",
+					map[string]string{"name": "b", "index": "1"},
+					map[string]interface{}{"value": uint64(2)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:   "byte strings",
+			parser: &Parser{},
+			input: map[string]interface{}{
+				"text": []byte("hello"),
+				"raw":  []byte{0xff, 0x00, 0x01},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"cbor",
+					map[string]string{},
+					map[string]interface{}{
+						"text": "hello",
+						"raw":  "/wAB",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "byte strings as hex",
+			parser: &Parser{
+				HexByteStrings: true,
+			},
+			input: map[string]interface{}{
+				"text": []byte("hello"),
+				"raw":  []byte{0xff, 0x00, 0x01},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"cbor",
+					map[string]string{},
+					map[string]interface{}{
+						"text": "68656c6c6f",
+						"raw":  "ff0001",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "cbor"
+			tt.parser.Log = testutil.Logger{}
+			require.NoError(t, tt.parser.Init())
+
+			input, err := cbor.Marshal(tt.input)
+			require.NoError(t, err)
+
+			actual, err := tt.parser.Parse(input)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser *Parser
+		input  map[string]interface{}
+	}{
+		{
+			name:   "time tag",
+			parser: &Parser{TimeKey: "time"},
+			input: map[string]interface{}{
+				"time":  cbor.Tag{Number: 1, Content: 1577923199.5},
+				"value": 42.5,
+			},
+		},
+		{
+			name:   "time format",
+			parser: &Parser{TimeKey: "time", TimeFormat: "unix_ms"},
+			input: map[string]interface{}{
+				"time":  1577923199500,
+				"value": 42.5,
+			},
+		},
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"cbor",
+			map[string]string{},
+			map[string]interface{}{"value": 42.5},
+			time.Unix(1577923199, 500000000),
+		),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "cbor"
+			require.NoError(t, tt.parser.Init())
+
+			input, err := cbor.Marshal(tt.input)
+			require.NoError(t, err)
+
+			actual, err := tt.parser.Parse(input)
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, actual)
+		})
+	}
+}
+
+func TestParseSequence(t *testing.T) {
+	parser := &Parser{MetricName: "cbor"}
+	require.NoError(t, parser.Init())
+
+	var input []byte
+	for _, v := range []int{1, 2} {
+		buf, err := cbor.Marshal(map[string]int{"value": v})
+		require.NoError(t, err)
+		input = append(input, buf...)
+	}
+
+	actual, err := parser.Parse(input)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, map[string]interface{}{"value": uint64(1)}, actual[0].Fields())
+	require.Equal(t, map[string]interface{}{"value": uint64(2)}, actual[1].Fields())
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{
+			name:     "truncated",
+			input:    []byte{0xa1, 0x64, 'h', 'o'},
+			expected: "decoding document failed",
+		},
+		{
+			name:     "scalar document",
+			input:    []byte{0x18, 0x2a},
+			expected: "must be a map or an array of maps",
+		},
+		{
+			name:     "array of scalars",
+			input:    []byte{0x82, 0x01, 0x02},
+			expected: "must be a map or an array of maps",
+		},
+		{
+			name:     "non-string map keys",
+			input:    []byte{0xa1, 0x01, 0x02},
+			expected: "decoding document failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{MetricName: "cbor"}
+			require.NoError(t, parser.Init())
+
+			_, err := parser.Parse(tt.input)
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,7 +17,7 @@ import (
 	_ "time/tzdata" // needed to bundle timezone info into the binary for Windows
 
 	"github.com/influxdata/telegraf"
-	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/internal/choice"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/parsers"
@@ -26,37 +27,62 @@ type TimeFunc func() time.Time
 
 const replacementByte = "\ufffd"
 const commaByte = "\u002C"
+const quotePlaceholder = "\ue000"
 
 type Parser struct {
-	ColumnNames        []string        `toml:"csv_column_names"`
-	ColumnTypes        []string        `toml:"csv_column_types"`
-	Comment            string          `toml:"csv_comment"`
-	Delimiter          string          `toml:"csv_delimiter"`
-	HeaderRowCount     int             `toml:"csv_header_row_count"`
-	MeasurementColumn  string          `toml:"csv_measurement_column"`
-	MetricName         string          `toml:"metric_name"`
-	SkipColumns        int             `toml:"csv_skip_columns"`
-	SkipRows           int             `toml:"csv_skip_rows"`
-	TagColumns         []string        `toml:"csv_tag_columns"`
-	TagOverwrite       bool            `toml:"csv_tag_overwrite"`
-	TimestampColumn    string          `toml:"csv_timestamp_column"`
-	TimestampFormat    string          `toml:"csv_timestamp_format"`
-	Timezone           string          `toml:"csv_timezone"`
-	TrimSpace          bool            `toml:"csv_trim_space"`
-	SkipValues         []string        `toml:"csv_skip_values"`
-	SkipErrors         bool            `toml:"csv_skip_errors"`
-	MetadataRows       int             `toml:"csv_metadata_rows"`
-	MetadataSeparators []string        `toml:"csv_metadata_separators"`
-	MetadataTrimSet    string          `toml:"csv_metadata_trim_set"`
-	ResetMode          string          `toml:"csv_reset_mode"`
-	Log                telegraf.Logger `toml:"-"`
+	ColumnNames        []string           `toml:"csv_column_names"`
+	ColumnTypes        []string           `toml:"csv_column_types"`
+	Comment            string             `toml:"csv_comment"`
+	Delimiter          string             `toml:"csv_delimiter"`
+	HeaderRowCount     int                `toml:"csv_header_row_count"`
+	MeasurementColumn  string             `toml:"csv_measurement_column"`
+	MetricName         string             `toml:"metric_name"`
+	SkipColumns        int                `toml:"csv_skip_columns"`
+	SkipRows           int                `toml:"csv_skip_rows"`
+	TagColumns         []string           `toml:"csv_tag_columns"`
+	TagOverwrite       bool               `toml:"csv_tag_overwrite"`
+	TimestampColumn    string             `toml:"csv_timestamp_column"`
+	TimestampFormat    string             `toml:"csv_timestamp_format"`
+	Timezone           string             `toml:"csv_timezone"`
+	TrimSpace          bool               `toml:"csv_trim_space"`
+	SkipValues         []string           `toml:"csv_skip_values"`
+	SkipErrors         bool               `toml:"csv_skip_errors"`
+	MetadataRows       int                `toml:"csv_metadata_rows"`
+	MetadataSeparators []string           `toml:"csv_metadata_separators"`
+	MetadataTrimSet    string             `toml:"csv_metadata_trim_set"`
+	ResetMode          string             `toml:"csv_reset_mode"`
+	TimestampColumns   []string           `toml:"csv_timestamp_columns"`
+	TimestampFormats   []string           `toml:"csv_timestamp_formats"`
+	Quote              string             `toml:"csv_quote"`
+	LazyQuotes         bool               `toml:"csv_lazy_quotes"`
+	UnpivotColumns     []string           `toml:"csv_unpivot_columns"`
+	UnpivotTag         string             `toml:"csv_unpivot_tag"`
+	UnpivotField       string             `toml:"csv_unpivot_field"`
+	DurationUnit       string             `toml:"csv_duration_unit"`
+	ColumnScale        map[string]float64 `toml:"csv_column_scale"`
+	ColumnOffset       map[string]float64 `toml:"csv_column_offset"`
+	AutoType           bool               `toml:"csv_auto_type"`
+	MultiTable         bool               `toml:"csv_multi_table"`
+	IncludeColumns     []string           `toml:"csv_include_columns"`
+	ExcludeColumns     []string           `toml:"csv_exclude_columns"`
+	NameCase           string             `toml:"measurement_case"`
+	DecimalSeparator   string             `toml:"decimal_separator"`
+	GroupSeparator     string             `toml:"group_separator"`
+	Log                telegraf.Logger    `toml:"-"`
 
 	metadataSeparatorList metadataPattern
 	location              *time.Location
+	measurementColumn     string
+	columnFilter          filter.Filter
+	timestampColumns      []string
+	timestampFormats      []string
 
 	gotColumnNames bool
+	autoTypes      map[string]string
+	tableHeader    []string
 
 	invalidDelimiter bool
+	quoteReplacer    *strings.Replacer
 
 	TimeFunc     func() time.Time
 	DefaultTags  map[string]string
@@ -66,6 +92,8 @@ type Parser struct {
 	remainingSkipRows     int
 	remainingHeaderRows   int
 	remainingMetadataRows int
+
+	skippedRows int
 }
 
 type metadataPattern []string
@@ -151,6 +179,25 @@ func (p *Parser) Init() error {
 		p.invalidDelimiter = !validDelim(runeStr[0])
 	}
 
+	p.quoteReplacer = nil
+	if p.Quote != "" && p.Quote != `"` {
+		runeStr := []rune(p.Quote)
+		if len(runeStr) > 1 {
+			return fmt.Errorf("csv_quote must be a single character, got: %s", p.Quote)
+		}
+		if !validDelim(runeStr[0]) || p.Quote == p.Delimiter || p.Quote == p.Comment {
+			return fmt.Errorf("invalid csv_quote %q", p.Quote)
+		}
+		// Commas are replaced before swapping the quotes when using an
+		// invalid delimiter, so the quote would never be found
+		if p.invalidDelimiter && p.Quote == commaByte {
+			return fmt.Errorf("csv_quote %q cannot be used with csv_delimiter %q", p.Quote, p.Delimiter)
+		}
+		// Values are parsed with the quote character swapped for double
+		// quotes, so we need to swap them back afterwards
+		p.quoteReplacer = strings.NewReplacer(`"`, p.Quote, quotePlaceholder, `"`)
+	}
+
 	if p.Comment != "" {
 		runeStr := []rune(p.Comment)
 		if len(runeStr) > 1 {
@@ -160,7 +207,51 @@ func (p *Parser) Init() error {
 
 	p.gotInitialColumnNames = len(p.ColumnNames) > 0
 	if len(p.ColumnNames) > 0 && len(p.ColumnTypes) > 0 && len(p.ColumnNames) != len(p.ColumnTypes) {
-		return errors.New("csv_column_names field count doesn't match with csv_column_types")
+		// Types of the remaining columns are inferred with automatic typing
+		if !p.AutoType || len(p.ColumnTypes) > len(p.ColumnNames) {
+			return errors.New("csv_column_names field count doesn't match with csv_column_types")
+		}
+	}
+
+	if len(p.IncludeColumns) > 0 || len(p.ExcludeColumns) > 0 {
+		f, err := filter.NewIncludeExcludeFilter(p.IncludeColumns, p.ExcludeColumns)
+		if err != nil {
+			return fmt.Errorf("creating column filter failed: %w", err)
+		}
+		p.columnFilter = f
+	}
+
+	if err := parsers.CheckMeasurementCase(p.NameCase); err != nil {
+		return err
+	}
+
+	if err := parsers.CheckNumberSeparators(p.DecimalSeparator, p.GroupSeparator); err != nil {
+		return err
+	}
+
+	if err := parsers.CheckDurationUnit(p.DurationUnit); err != nil {
+		return fmt.Errorf("invalid csv_duration_unit: %w", err)
+	}
+
+	if p.gotInitialColumnNames {
+		if err := p.resolveMeasurementColumn(); err != nil {
+			return err
+		}
+	}
+
+	if p.TimestampColumn != "" && len(p.TimestampColumns) > 0 {
+		return errors.New("cannot use csv_timestamp_column and csv_timestamp_columns together")
+	}
+	p.timestampColumns = p.TimestampColumns
+	if p.TimestampColumn != "" {
+		p.timestampColumns = []string{p.TimestampColumn}
+	}
+	if p.TimestampFormat != "" && len(p.TimestampFormats) > 0 {
+		return errors.New("cannot use csv_timestamp_format and csv_timestamp_formats together")
+	}
+	p.timestampFormats = p.TimestampFormats
+	if p.TimestampFormat != "" {
+		p.timestampFormats = []string{p.TimestampFormat}
 	}
 
 	if err := p.initializeMetadataSeparators(); err != nil {
@@ -179,6 +270,15 @@ func (p *Parser) Init() error {
 		p.location = loc
 	}
 
+	if len(p.UnpivotColumns) > 0 {
+		if p.UnpivotTag == "" {
+			p.UnpivotTag = "column"
+		}
+		if p.UnpivotField == "" {
+			p.UnpivotField = "value"
+		}
+	}
+
 	if p.ResetMode == "" {
 		p.ResetMode = "none"
 	}
@@ -190,7 +290,29 @@ func (p *Parser) Init() error {
 	return nil
 }
 
-func (p *Parser) SetTimeFunc(fn TimeFunc) {
+// resolveMeasurementColumn determines the name of the measurement column
+// given either by name or by its zero-based index in the column names.
+func (p *Parser) resolveMeasurementColumn() error {
+	p.measurementColumn = ""
+	if p.MeasurementColumn == "" {
+		return nil
+	}
+
+	for _, name := range p.ColumnNames {
+		if name == p.MeasurementColumn {
+			p.measurementColumn = name
+			return nil
+		}
+	}
+	if idx, err := strconv.Atoi(p.MeasurementColumn); err == nil && idx >= 0 && idx < len(p.ColumnNames) {
+		p.measurementColumn = p.ColumnNames[idx]
+		return nil
+	}
+
+	return fmt.Errorf("measurement column %q not found in columns", p.MeasurementColumn)
+}
+
+func (p *Parser) SetTimeFunc(fn func() time.Time) {
 	p.TimeFunc = fn
 }
 
@@ -209,10 +331,36 @@ func (p *Parser) compile(r io.Reader) *csv.Reader {
 		csvReader.Comment, _ = utf8.DecodeRuneInString(p.Comment)
 	}
 	csvReader.TrimLeadingSpace = p.TrimSpace
+	csvReader.LazyQuotes = p.LazyQuotes
 
 	return csvReader
 }
 
+// replaceQuotes swaps the custom quote character with double quotes as
+// the CSV reader only supports the latter. Existing double quotes are
+// replaced by a placeholder to be restored after parsing. Data already
+// containing the placeholder is rejected as it could not be restored.
+func (p *Parser) replaceQuotes(buf []byte) ([]byte, error) {
+	if p.quoteReplacer == nil {
+		return buf, nil
+	}
+	if bytes.Contains(buf, []byte(quotePlaceholder)) {
+		return nil, fmt.Errorf("data contains the reserved character %U which is not supported with csv_quote", []rune(quotePlaceholder)[0])
+	}
+	buf = bytes.ReplaceAll(buf, []byte(`"`), []byte(quotePlaceholder))
+	return bytes.ReplaceAll(buf, []byte(p.Quote), []byte(`"`)), nil
+}
+
+// restoreQuotes reverts the quote replacement for the parsed values.
+func (p *Parser) restoreQuotes(record []string) {
+	if p.quoteReplacer == nil {
+		return
+	}
+	for i, v := range record {
+		record[i] = p.quoteReplacer.Replace(v)
+	}
+}
+
 // Taken from upstream Golang code see
 // https://github.com/golang/go/blob/release-branch.go1.19/src/encoding/csv/reader.go#L95
 func validDelim(r rune) bool {
@@ -230,6 +378,10 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 		buf = bytes.Replace(buf, []byte(commaByte), []byte(replacementByte), -1)
 		buf = bytes.Replace(buf, []byte(p.Delimiter), []byte(commaByte), -1)
 	}
+	buf, err := p.replaceQuotes(buf)
+	if err != nil {
+		return nil, err
+	}
 	r := bytes.NewReader(buf)
 	metrics, err := parseCSV(p, r)
 	if err != nil && errors.Is(err, io.EOF) {
@@ -249,7 +401,11 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 			return nil, parsers.ErrEOF
 		}
 	}
-	r := bytes.NewReader([]byte(line))
+	buf, err := p.replaceQuotes([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(buf)
 	metrics, err := parseCSV(p, r)
 	if err != nil {
 		if errors.Is(err, io.EOF) {
@@ -267,6 +423,7 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 }
 
 func parseCSV(p *Parser, r io.Reader) ([]telegraf.Metric, error) {
+	p.skippedRows = 0
 	lineReader := bufio.NewReader(r)
 	// skip first rows
 	for p.remainingSkipRows > 0 {
@@ -283,6 +440,9 @@ func parseCSV(p *Parser, r io.Reader) ([]telegraf.Metric, error) {
 			return nil, err
 		}
 		p.remainingMetadataRows--
+		if p.quoteReplacer != nil {
+			line = p.quoteReplacer.Replace(line)
+		}
 		m := p.parseMetadataRow(line)
 		for k, v := range m {
 			p.metadataTags[k] = v
@@ -299,50 +459,199 @@ func parseCSV(p *Parser, r io.Reader) ([]telegraf.Metric, error) {
 		if err != nil {
 			return nil, err
 		}
+		p.restoreQuotes(header)
+		if p.remainingHeaderRows == p.HeaderRowCount {
+			// Remember the first header row to detect subsequent tables
+			p.tableHeader = header
+		}
 		p.remainingHeaderRows--
-		if p.gotColumnNames {
-			// Ignore header lines if columns are named
-			continue
+		p.addHeaderRow(header)
+	}
+	if err := p.completeHeader(); err != nil {
+		return nil, err
+	}
+
+	metrics := make([]telegraf.Metric, 0)
+	records := make([][]string, 0)
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
 		}
-		//concatenate header names
-		for i, h := range header {
-			name := h
-			if p.TrimSpace {
-				name = strings.Trim(name, " ")
+		if err != nil {
+			// Skip malformed rows but keep reading the remaining data
+			var perr *csv.ParseError
+			if p.SkipErrors && errors.As(err, &perr) {
+				p.Log.Debugf("Skipping malformed row: %v", err)
+				p.skippedRows++
+				continue
 			}
-			if len(p.ColumnNames) <= i {
-				p.ColumnNames = append(p.ColumnNames, name)
-			} else {
-				p.ColumnNames[i] = p.ColumnNames[i] + name
+			return nil, err
+		}
+		p.restoreQuotes(record)
+
+		if p.MultiTable {
+			// A repetition of the first header row starts a new table so
+			// parse the records of the current table with its columns
+			// before reading the new header
+			if p.remainingHeaderRows == 0 && p.tableHeader != nil && slices.Equal(record, p.tableHeader) {
+				m, err := p.parseRecords(records)
+				metrics = append(metrics, m...)
+				if err != nil {
+					return metrics, err
+				}
+				records = records[:0]
+
+				p.gotColumnNames = p.gotInitialColumnNames
+				if !p.gotInitialColumnNames {
+					p.ColumnNames = nil
+				}
+				p.remainingHeaderRows = p.HeaderRowCount
+			}
+			if p.remainingHeaderRows > 0 {
+				p.remainingHeaderRows--
+				p.addHeaderRow(record)
+				if p.remainingHeaderRows == 0 {
+					if err := p.completeHeader(); err != nil {
+						return metrics, err
+					}
+				}
+				continue
+			}
+		}
+
+		// Rows not matching the number of columns of the header or the
+		// configured column names are considered malformed
+		if p.SkipErrors {
+			if expected := p.SkipColumns + len(p.ColumnNames); len(record) != expected {
+				p.Log.Debugf("Skipping malformed row: expected %d columns but got %d", expected, len(record))
+				p.skippedRows++
+				continue
 			}
 		}
+
+		// Inferring the column types requires all records of the table,
+		// otherwise convert the records as they are read
+		if p.AutoType {
+			records = append(records, record)
+			continue
+		}
+		m, err := p.convertRecord(record)
+		metrics = append(metrics, m...)
+		if err != nil {
+			return metrics, err
+		}
+	}
+
+	m, err := p.parseRecords(records)
+	return append(metrics, m...), err
+}
+
+// addHeaderRow concatenates the names in the given header row to the column
+// names unless the columns are named already.
+func (p *Parser) addHeaderRow(header []string) {
+	if p.gotColumnNames {
+		// Ignore header lines if columns are named
+		return
+	}
+	for i, h := range header {
+		name := h
+		if p.TrimSpace {
+			name = strings.Trim(name, " ")
+		}
+		if len(p.ColumnNames) <= i {
+			p.ColumnNames = append(p.ColumnNames, name)
+		} else {
+			p.ColumnNames[i] = p.ColumnNames[i] + name
+		}
 	}
-	if !p.gotColumnNames {
-		// skip first rows
-		p.ColumnNames = p.ColumnNames[p.SkipColumns:]
-		p.gotColumnNames = true
+}
+
+// completeHeader finalizes the column names after all header rows are read.
+func (p *Parser) completeHeader() error {
+	if p.gotColumnNames {
+		return nil
 	}
+	// skip first rows
+	p.ColumnNames = p.ColumnNames[p.SkipColumns:]
+	p.gotColumnNames = true
 
-	table, err := csvReader.ReadAll()
-	if err != nil {
-		return nil, err
+	return p.resolveMeasurementColumn()
+}
+
+// parseRecords converts the given records of a table to metrics using the
+// current column names.
+func (p *Parser) parseRecords(records [][]string) ([]telegraf.Metric, error) {
+	if p.AutoType {
+		p.autoTypes = p.inferTypes(records)
 	}
 
-	metrics := make([]telegraf.Metric, 0)
-	for _, record := range table {
-		m, err := p.parseRecord(record)
+	metrics := make([]telegraf.Metric, 0, len(records))
+	for _, record := range records {
+		m, err := p.convertRecord(record)
+		metrics = append(metrics, m...)
 		if err != nil {
-			if p.SkipErrors {
-				p.Log.Debugf("Parsing error: %v", err)
-				continue
-			}
 			return metrics, err
 		}
-		metrics = append(metrics, m)
 	}
 	return metrics, nil
 }
 
+// convertRecord converts the given record to metrics using the current
+// column names. Records failing to convert are skipped if configured.
+func (p *Parser) convertRecord(record []string) ([]telegraf.Metric, error) {
+	m, err := p.parseRecord(record)
+	if err != nil {
+		if p.SkipErrors {
+			p.Log.Debugf("Parsing error: %v", err)
+			p.skippedRows++
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(p.UnpivotColumns) > 0 {
+		return p.unpivot(m), nil
+	}
+	return []telegraf.Metric{m}, nil
+}
+
+// unpivot reshapes the given metric into one metric per value column. The
+// name of the column is added as tag and the value as field. All remaining
+// fields are treated as identifiers and added as tags to every metric.
+func (p *Parser) unpivot(m telegraf.Metric) []telegraf.Metric {
+	tags := m.Tags()
+	values := make(map[string]interface{}, len(p.UnpivotColumns))
+	for _, field := range m.FieldList() {
+		if choice.Contains(field.Key, p.UnpivotColumns) {
+			values[field.Key] = field.Value
+			continue
+		}
+		tags[field.Key] = fmt.Sprintf("%v", field.Value)
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(values))
+	for _, column := range p.UnpivotColumns {
+		v, found := values[column]
+		if !found {
+			continue
+		}
+		mtags := make(map[string]string, len(tags)+1)
+		for k, tv := range tags {
+			mtags[k] = tv
+		}
+		mtags[p.UnpivotTag] = column
+		metrics = append(metrics, metric.New(m.Name(), mtags, map[string]interface{}{p.UnpivotField: v}, m.Time()))
+	}
+	return metrics
+}
+
+// SkippedRows returns the number of rows skipped due to errors or a wrong
+// number of columns during the last call to Parse or ParseLine. Rows are only
+// skipped if `SkipErrors` is set.
+func (p *Parser) SkippedRows() int {
+	return p.skippedRows
+}
+
 func (p *Parser) parseRecord(record []string) (telegraf.Metric, error) {
 	recordFields := make(map[string]interface{})
 	tags := make(map[string]string)
@@ -363,6 +672,9 @@ func (p *Parser) parseRecord(record []string) (telegraf.Metric, error) {
 	record = record[p.SkipColumns:]
 outer:
 	for i, fieldName := range p.ColumnNames {
+		if !p.keepColumn(fieldName) {
+			continue
+		}
 		if i < len(record) {
 			value := record[i]
 			if p.TrimSpace {
@@ -383,14 +695,19 @@ outer:
 				}
 			}
 
-			// If the field name is the timestamp column, then keep field name as is.
-			if fieldName == p.TimestampColumn {
+			// If the field name is a timestamp column, then keep field name as is.
+			if choice.Contains(fieldName, p.timestampColumns) {
 				recordFields[fieldName] = value
 				continue
 			}
 
 			// Try explicit conversion only when column types is defined.
-			if len(p.ColumnTypes) > 0 {
+			// With automatic typing, missing or "auto" types are inferred.
+			explicit := len(p.ColumnTypes) > 0
+			if p.AutoType {
+				explicit = i < len(p.ColumnTypes) && p.ColumnTypes[i] != "" && p.ColumnTypes[i] != "auto"
+			}
+			if explicit {
 				// Throw error if current column count exceeds defined types.
 				if i >= len(p.ColumnTypes) {
 					return nil, errors.New("column type: column count exceeded")
@@ -401,12 +718,12 @@ outer:
 
 				switch p.ColumnTypes[i] {
 				case "int":
-					val, err = strconv.ParseInt(value, 10, 64)
+					val, err = strconv.ParseInt(p.number(value), 10, 64)
 					if err != nil {
 						return nil, fmt.Errorf("column type: parse int error %w", err)
 					}
 				case "float":
-					val, err = strconv.ParseFloat(value, 64)
+					val, err = strconv.ParseFloat(p.number(value), 64)
 					if err != nil {
 						return nil, fmt.Errorf("column type: parse float error %w", err)
 					}
@@ -415,19 +732,34 @@ outer:
 					if err != nil {
 						return nil, fmt.Errorf("column type: parse bool error %w", err)
 					}
+				case "duration":
+					val, err = parsers.ParseDuration(value, p.DurationUnit)
+					if err != nil {
+						return nil, fmt.Errorf("column type: parse duration error %w", err)
+					}
 				default:
 					val = value
 				}
 
+				if t := p.ColumnTypes[i]; t == "int" || t == "float" {
+					val = p.calibrate(fieldName, val)
+				}
 				recordFields[fieldName] = val
 				continue
 			}
 
+			if p.AutoType {
+				if value != "" {
+					recordFields[fieldName] = p.convertAuto(fieldName, value)
+				}
+				continue
+			}
+
 			// attempt type conversions
-			if iValue, err := strconv.ParseInt(value, 10, 64); err == nil {
-				recordFields[fieldName] = iValue
-			} else if fValue, err := strconv.ParseFloat(value, 64); err == nil {
-				recordFields[fieldName] = fValue
+			if iValue, err := strconv.ParseInt(p.number(value), 10, 64); err == nil {
+				recordFields[fieldName] = p.calibrate(fieldName, iValue)
+			} else if fValue, err := strconv.ParseFloat(p.number(value), 64); err == nil {
+				recordFields[fieldName] = p.calibrate(fieldName, fValue)
 			} else if bValue, err := strconv.ParseBool(value); err == nil {
 				recordFields[fieldName] = bValue
 			} else {
@@ -450,50 +782,176 @@ outer:
 
 	// will default to plugin name
 	measurementName := p.MetricName
-	if p.MeasurementColumn != "" {
-		if recordFields[p.MeasurementColumn] != nil && recordFields[p.MeasurementColumn] != "" {
-			measurementName = fmt.Sprintf("%v", recordFields[p.MeasurementColumn])
+	if p.measurementColumn != "" {
+		if recordFields[p.measurementColumn] != nil && recordFields[p.measurementColumn] != "" {
+			measurementName = parsers.NormalizeMeasurement(fmt.Sprintf("%v", recordFields[p.measurementColumn]), p.NameCase)
 		}
 	}
 
-	metricTime, err := parseTimestamp(p.TimeFunc, recordFields, p.TimestampColumn, p.TimestampFormat, p.location)
+	metricTime, err := parseTimestamp(p.TimeFunc, recordFields, p.timestampColumns, p.timestampFormats, p.location)
 	if err != nil {
 		return nil, err
 	}
 
-	// Exclude `TimestampColumn` and `MeasurementColumn`
-	delete(recordFields, p.TimestampColumn)
-	delete(recordFields, p.MeasurementColumn)
+	// Exclude the timestamp columns and `MeasurementColumn`
+	for _, column := range p.timestampColumns {
+		delete(recordFields, column)
+	}
+	delete(recordFields, p.measurementColumn)
 
 	m := metric.New(measurementName, tags, recordFields, metricTime)
 
 	return m, nil
 }
 
+// keepColumn returns true if the column with the given name passes the column
+// filter. Tag, timestamp and measurement columns are always kept.
+func (p *Parser) keepColumn(name string) bool {
+	if p.columnFilter == nil || p.columnFilter.Match(name) {
+		return true
+	}
+	return name == p.measurementColumn ||
+		choice.Contains(name, p.TagColumns) ||
+		choice.Contains(name, p.timestampColumns)
+}
+
+// inferTypes determines the type of each column from the values of all given
+// records for automatic typing. Empty values are ignored. Columns containing
+// values of different types are treated as strings except for integers mixed
+// with floats resulting in floats.
+func (p *Parser) inferTypes(records [][]string) map[string]string {
+	types := make(map[string]string, len(p.ColumnNames))
+	for _, record := range records {
+		record = record[min(p.SkipColumns, len(record)):]
+	columns:
+		for i, fieldName := range p.ColumnNames {
+			if i >= len(record) {
+				break
+			}
+			value := record[i]
+			if p.TrimSpace {
+				value = strings.Trim(value, " ")
+			}
+			if value == "" {
+				continue
+			}
+			for _, s := range p.SkipValues {
+				if value == s {
+					continue columns
+				}
+			}
+
+			var current string
+			if _, err := strconv.ParseInt(p.number(value), 10, 64); err == nil {
+				current = "int"
+			} else if _, err := strconv.ParseFloat(p.number(value), 64); err == nil {
+				current = "float"
+			} else if _, err := strconv.ParseBool(value); err == nil {
+				current = "bool"
+			} else {
+				current = "string"
+			}
+
+			switch previous := types[fieldName]; {
+			case previous == "" || previous == current:
+				types[fieldName] = current
+			case (previous == "int" || previous == "float") && (current == "int" || current == "float"):
+				types[fieldName] = "float"
+			default:
+				types[fieldName] = "string"
+			}
+		}
+	}
+	return types
+}
+
+// convertAuto converts the value according to the type inferred for the
+// column, keeping the value as string if the conversion fails.
+func (p *Parser) convertAuto(column, value string) interface{} {
+	switch p.autoTypes[column] {
+	case "int":
+		if v, err := strconv.ParseInt(p.number(value), 10, 64); err == nil {
+			return p.calibrate(column, v)
+		}
+	case "float":
+		if v, err := strconv.ParseFloat(p.number(value), 64); err == nil {
+			return p.calibrate(column, v)
+		}
+	case "bool":
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// number removes the group separators and replaces the decimal separator of
+// numeric values if configured.
+func (p *Parser) number(value string) string {
+	return parsers.NormalizeNumber(value, p.DecimalSeparator, p.GroupSeparator)
+}
+
+// calibrate applies the scale and offset configured for the column to the
+// given numeric value resulting in a float. Values of columns without
+// scale or offset are returned unchanged.
+func (p *Parser) calibrate(column string, value interface{}) interface{} {
+	scale, hasScale := p.ColumnScale[column]
+	offset, hasOffset := p.ColumnOffset[column]
+	if !hasScale && !hasOffset {
+		return value
+	}
+	if !hasScale {
+		scale = 1
+	}
+
+	switch v := value.(type) {
+	case int64:
+		return float64(v)*scale + offset
+	case float64:
+		return v*scale + offset
+	}
+	return value
+}
+
 // ParseTimestamp return a timestamp, if there is no timestamp on the csv it
 // will be the current timestamp, else it will try to parse the time according
-// to the format.
+// to the formats. Multiple timestamp columns are joined using a space and the
+// formats are tried in the given order.
 func parseTimestamp(timeFunc func() time.Time, recordFields map[string]interface{},
-	timestampColumn, timestampFormat string, timezone *time.Location,
+	timestampColumns, timestampFormats []string, timezone *time.Location,
 ) (time.Time, error) {
-	if timestampColumn != "" {
-		if recordFields[timestampColumn] == nil {
-			return time.Time{}, fmt.Errorf("timestamp column: %v could not be found", timestampColumn)
-		}
-
-		switch timestampFormat {
-		case "":
-			return time.Time{}, errors.New("timestamp format must be specified")
-		default:
-			metricTime, err := internal.ParseTimestamp(timestampFormat, recordFields[timestampColumn], timezone)
-			if err != nil {
-				return time.Time{}, err
-			}
-			return metricTime, err
+	if len(timestampColumns) == 0 {
+		return timeFunc(), nil
+	}
+
+	parts := make([]string, 0, len(timestampColumns))
+	for _, column := range timestampColumns {
+		if recordFields[column] == nil {
+			return time.Time{}, fmt.Errorf("timestamp column: %v could not be found", column)
 		}
+		parts = append(parts, fmt.Sprintf("%v", recordFields[column]))
+	}
+	var value interface{} = strings.Join(parts, " ")
+	if len(timestampColumns) == 1 {
+		value = recordFields[timestampColumns[0]]
 	}
 
-	return timeFunc(), nil
+	if len(timestampFormats) == 0 {
+		return time.Time{}, errors.New("timestamp format must be specified")
+	}
+
+	var err error
+	for _, format := range timestampFormats {
+		var metricTime time.Time
+		metricTime, err = parsers.ParseTimestamp(format, value, timezone)
+		if err == nil {
+			return metricTime, nil
+		}
+	}
+	if len(timestampFormats) > 1 {
+		return time.Time{}, fmt.Errorf("timestamp %q does not match any format: %w", value, err)
+	}
+	return time.Time{}, err
 }
 
 // SetDefaultTags set the DefaultTags
@@ -87,6 +87,54 @@ func TestHeaderOverride(t *testing.T) {
 	require.Equal(t, expectedFields, m.Fields())
 }
 
+func TestMeasurementColumnIndex(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount:    1,
+		MeasurementColumn: "2",
+		MetricName:        "csv",
+		TimeFunc:          DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `first,second,event
+3.4,70,login
+3.5,71,`
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	require.Equal(t, "login", metrics[0].Name())
+	require.Equal(t, map[string]interface{}{"first": 3.4, "second": int64(70)}, metrics[0].Fields())
+
+	// Fall back to the default name for empty columns
+	require.Equal(t, "csv", metrics[1].Name())
+	require.Equal(t, map[string]interface{}{"first": 3.5, "second": int64(71)}, metrics[1].Fields())
+}
+
+func TestMeasurementColumnInvalid(t *testing.T) {
+	p := &Parser{
+		ColumnNames:       []string{"first", "second", "third"},
+		MeasurementColumn: "fourth",
+		TimeFunc:          DefaultTime,
+	}
+	require.ErrorContains(t, p.Init(), `measurement column "fourth" not found`)
+
+	p = &Parser{
+		ColumnNames:       []string{"first", "second", "third"},
+		MeasurementColumn: "3",
+		TimeFunc:          DefaultTime,
+	}
+	require.ErrorContains(t, p.Init(), `measurement column "3" not found`)
+
+	p = &Parser{
+		HeaderRowCount:    1,
+		MeasurementColumn: "fourth",
+		TimeFunc:          DefaultTime,
+	}
+	require.NoError(t, p.Init())
+	_, err := p.Parse([]byte("first,second,third\n1,2,3"))
+	require.ErrorContains(t, err, `measurement column "fourth" not found`)
+}
+
 func TestTimestamp(t *testing.T) {
 	p := &Parser{
 		HeaderRowCount:    1,
@@ -147,6 +195,81 @@ func TestTimestampError(t *testing.T) {
 	require.Equal(t, errors.New("timestamp format must be specified"), err)
 }
 
+func TestTimestampMultipleColumnsAndFormats(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount:   1,
+		TimestampColumns: []string{"date", "time"},
+		TimestampFormats: []string{"2006-01-02 15:04:05", "02/01/2006 15:04"},
+		TimeFunc:         DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `date,time,value
+2009-05-23,16:05:06,1
+23/05/2009,16:05,2`
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	// First format succeeds
+	require.Equal(t, time.Date(2009, 5, 23, 16, 5, 6, 0, time.UTC), metrics[0].Time())
+	require.Equal(t, map[string]interface{}{"value": int64(1)}, metrics[0].Fields())
+
+	// First format fails, second format succeeds
+	require.Equal(t, time.Date(2009, 5, 23, 16, 5, 0, 0, time.UTC), metrics[1].Time())
+	require.Equal(t, map[string]interface{}{"value": int64(2)}, metrics[1].Fields())
+}
+
+func TestTimestampMultipleFormatsError(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount:   1,
+		TimestampColumns: []string{"date", "time"},
+		TimestampFormats: []string{"2006-01-02 15:04:05", "02/01/2006 15:04"},
+		TimeFunc:         DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `date,time,value
+May 23 2009,16:05,1`
+	_, err := p.Parse([]byte(testCSV))
+	require.ErrorContains(t, err, `timestamp "May 23 2009 16:05" does not match any format`)
+
+	// Rows with failing timestamps are dropped when skipping errors
+	p = &Parser{
+		HeaderRowCount:   1,
+		TimestampColumns: []string{"date", "time"},
+		TimestampFormats: []string{"2006-01-02 15:04:05", "02/01/2006 15:04"},
+		SkipErrors:       true,
+		TimeFunc:         DefaultTime,
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, p.Init())
+
+	testCSV = `date,time,value
+May 23 2009,16:05,1
+2009-05-23,16:05:06,2`
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, map[string]interface{}{"value": int64(2)}, metrics[0].Fields())
+}
+
+func TestTimestampColumnsConflict(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount:   1,
+		TimestampColumn:  "date",
+		TimestampColumns: []string{"date", "time"},
+	}
+	require.ErrorContains(t, p.Init(), "cannot use csv_timestamp_column and csv_timestamp_columns together")
+
+	p = &Parser{
+		HeaderRowCount:   1,
+		TimestampFormat:  "unix",
+		TimestampFormats: []string{"unix", "unix_ms"},
+	}
+	require.ErrorContains(t, p.Init(), "cannot use csv_timestamp_format and csv_timestamp_formats together")
+}
+
 func TestTimestampUnixFormat(t *testing.T) {
 	p := &Parser{
 		HeaderRowCount:    1,
@@ -281,6 +404,353 @@ func TestValueConversion(t *testing.T) {
 	require.Equal(t, expectedMetric.Fields(), returnedMetric.Fields())
 }
 
+func TestDurationColumnType(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     string
+		expected int64
+	}{
+		{
+			name:     "default unit",
+			expected: 5400000000000,
+		},
+		{
+			name:     "seconds",
+			unit:     "s",
+			expected: 5400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				ColumnNames:  []string{"host", "uptime"},
+				ColumnTypes:  []string{"string", "duration"},
+				TagColumns:   []string{"host"},
+				DurationUnit: tt.unit,
+				MetricName:   "test_value",
+				TimeFunc:     DefaultTime,
+			}
+			require.NoError(t, p.Init())
+
+			metrics, err := p.Parse([]byte("a,1h30m"))
+			require.NoError(t, err)
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"test_value",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"uptime": tt.expected},
+					DefaultTime(),
+				),
+			}
+			testutil.RequireMetricsEqual(t, expected, metrics)
+		})
+	}
+}
+
+func TestDurationColumnTypeInvalid(t *testing.T) {
+	p := &Parser{
+		ColumnNames:  []string{"uptime"},
+		ColumnTypes:  []string{"duration"},
+		DurationUnit: "days",
+		MetricName:   "test_value",
+	}
+	require.ErrorContains(t, p.Init(), `invalid duration unit "days"`)
+
+	p.DurationUnit = ""
+	require.NoError(t, p.Init())
+	_, err := p.Parse([]byte("90"))
+	require.ErrorContains(t, err, "column type: parse duration error")
+}
+
+func TestColumnCalibration(t *testing.T) {
+	tests := []struct {
+		name        string
+		columnTypes []string
+	}{
+		{
+			name:        "explicit types",
+			columnTypes: []string{"string", "int", "float", "int"},
+		},
+		{
+			name: "auto types",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				ColumnNames:  []string{"name", "voltage", "temperature", "count"},
+				ColumnTypes:  tt.columnTypes,
+				ColumnScale:  map[string]float64{"voltage": 0.1, "name": 2},
+				ColumnOffset: map[string]float64{"temperature": -273.15},
+				MetricName:   "test_value",
+				TimeFunc:     DefaultTime,
+			}
+			require.NoError(t, p.Init())
+
+			metrics, err := p.Parse([]byte("sensor,123,300.5,7"))
+			require.NoError(t, err)
+			require.Len(t, metrics, 1)
+
+			fields := metrics[0].Fields()
+			require.Equal(t, "sensor", fields["name"])
+			require.InDelta(t, 12.3, fields["voltage"], 1e-9)
+			require.InDelta(t, 27.35, fields["temperature"], 1e-9)
+			require.Equal(t, int64(7), fields["count"])
+		})
+	}
+}
+
+func TestAutoType(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount: 1,
+		ColumnTypes:    []string{"", "auto", "auto", "auto", "auto", "string"},
+		AutoType:       true,
+		MetricName:     "test_value",
+		TimeFunc:       DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `count,temperature,online,name,mixed,serial
+3,21.5,true,sensor,1,0042
+7,22,false,,abc,0043`
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test_value",
+			map[string]string{},
+			map[string]interface{}{
+				"count":       int64(3),
+				"temperature": 21.5,
+				"online":      true,
+				"name":        "sensor",
+				"mixed":       "1",
+				"serial":      "0042",
+			},
+			DefaultTime(),
+		),
+		metric.New(
+			"test_value",
+			map[string]string{},
+			map[string]interface{}{
+				"count":       int64(7),
+				"temperature": 22.0,
+				"online":      false,
+				"mixed":       "abc",
+				"serial":      "0043",
+			},
+			DefaultTime(),
+		),
+	}
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestParseRowByRow(t *testing.T) {
+	p := &Parser{
+		ColumnNames: []string{"name", "value"},
+		ColumnTypes: []string{"string", "int"},
+		MetricName:  "test_value",
+		TimeFunc:    DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	// Records are converted as they are read, so the conversion error is
+	// reported before reading the malformed last row
+	testCSV := "a,1\nb,x\nc,\"3"
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test_value",
+			map[string]string{},
+			map[string]interface{}{"name": "a", "value": int64(1)},
+			DefaultTime(),
+		),
+	}
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.ErrorContains(t, err, `parsing "x"`)
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestMultiTable(t *testing.T) {
+	testCSV := `name,value
+a,1
+b,2
+name,value
+c,3`
+
+	tests := []struct {
+		name       string
+		multiTable bool
+		expected   []telegraf.Metric
+	}{
+		{
+			name:       "stacked tables",
+			multiTable: true,
+			expected: []telegraf.Metric{
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "a", "value": int64(1)}, DefaultTime()),
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "b", "value": int64(2)}, DefaultTime()),
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "c", "value": int64(3)}, DefaultTime()),
+			},
+		},
+		{
+			name: "repeated header as data",
+			expected: []telegraf.Metric{
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "a", "value": int64(1)}, DefaultTime()),
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "b", "value": int64(2)}, DefaultTime()),
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "name", "value": "value"}, DefaultTime()),
+				metric.New("test", map[string]string{}, map[string]interface{}{"name": "c", "value": int64(3)}, DefaultTime()),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				HeaderRowCount: 1,
+				MultiTable:     tt.multiTable,
+				MetricName:     "test",
+				TimeFunc:       DefaultTime,
+			}
+			require.NoError(t, p.Init())
+
+			metrics, err := p.Parse([]byte(testCSV))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, metrics)
+		})
+	}
+}
+
+func TestMultiTableHeaderRows(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount: 2,
+		MultiTable:     true,
+		MetricName:     "test",
+		TimeFunc:       DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `sensor,temp
+_id,_c
+a,21
+sensor,temp
+_id,_f
+b,70`
+
+	expected := []telegraf.Metric{
+		metric.New("test", map[string]string{}, map[string]interface{}{"sensor_id": "a", "temp_c": int64(21)}, DefaultTime()),
+		metric.New("test", map[string]string{}, map[string]interface{}{"sensor_id": "b", "temp_f": int64(70)}, DefaultTime()),
+	}
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestColumnFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "include",
+			include:  []string{"temp", "hum*"},
+			expected: map[string]interface{}{"temp": 21.5, "humidity": int64(45)},
+		},
+		{
+			name:     "exclude",
+			exclude:  []string{"temp", "hum*"},
+			expected: map[string]interface{}{"pressure": 1013.25, "status": "ok"},
+		},
+		{
+			name:     "include and exclude",
+			include:  []string{"*"},
+			exclude:  []string{"status"},
+			expected: map[string]interface{}{"temp": 21.5, "humidity": int64(45), "pressure": 1013.25},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				HeaderRowCount:  1,
+				IncludeColumns:  tt.include,
+				ExcludeColumns:  tt.exclude,
+				TagColumns:      []string{"sensor"},
+				TimestampColumn: "time",
+				TimestampFormat: "unix",
+				MetricName:      "test",
+				TimeFunc:        DefaultTime,
+			}
+			require.NoError(t, p.Init())
+
+			testCSV := `time,sensor,temp,humidity,pressure,status
+1577923199,a,21.5,45,1013.25,ok`
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{"sensor": "a"},
+					tt.expected,
+					time.Unix(1577923199, 0),
+				),
+			}
+
+			metrics, err := p.Parse([]byte(testCSV))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, metrics)
+		})
+	}
+}
+
+func TestNumberSeparators(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount:   1,
+		Delimiter:        ";",
+		ColumnTypes:      []string{"int", "float", "auto"},
+		DecimalSeparator: ",",
+		GroupSeparator:   ".",
+		AutoType:         true,
+		MetricName:       "test",
+		TimeFunc:         DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `count;price;weight
+1.024;1.234,56;0,5`
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"count":  int64(1024),
+				"price":  1234.56,
+				"weight": 0.5,
+			},
+			DefaultTime(),
+		),
+	}
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics)
+
+	p = &Parser{
+		HeaderRowCount:   1,
+		DecimalSeparator: ",",
+		GroupSeparator:   ",",
+	}
+	require.ErrorContains(t, p.Init(), "decimal and group separator must differ")
+}
+
 func TestSkipComment(t *testing.T) {
 	p := &Parser{
 		HeaderRowCount: 0,
@@ -845,6 +1315,289 @@ corrupted_line
 	require.Equal(t, expectedFields1, metrics[1].Fields())
 }
 
+func TestSkipMalformedRows(t *testing.T) {
+	testCSV := `name,a,b
+row1,1,2
+row2,3,4
+row"3,5,6
+row4,7,8
+row5,9,10`
+
+	p := &Parser{
+		HeaderRowCount: 1,
+		TimeFunc:       DefaultTime,
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, p.Init())
+	_, err := p.Parse([]byte(testCSV))
+	require.ErrorContains(t, err, "bare \" in non-quoted-field")
+
+	p = &Parser{
+		HeaderRowCount: 1,
+		SkipErrors:     true,
+		TimeFunc:       DefaultTime,
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, p.Init())
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	require.Len(t, metrics, 4)
+	require.Equal(t, 1, p.SkippedRows())
+
+	var names []interface{}
+	for _, m := range metrics {
+		names = append(names, m.Fields()["name"])
+	}
+	require.Equal(t, []interface{}{"row1", "row2", "row4", "row5"}, names)
+}
+
+func TestQuotedFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		quote     string
+		delimiter string
+		lazy      bool
+		input     string
+		expected  map[string]interface{}
+	}{
+		{
+			name:  "delimiter in double quotes",
+			input: "name,value\n\"Doe, John\",42",
+			expected: map[string]interface{}{
+				"name":  "Doe, John",
+				"value": int64(42),
+			},
+		},
+		{
+			name:  "delimiter in single quotes",
+			quote: "'",
+			input: "name,value\n'Doe, John',42",
+			expected: map[string]interface{}{
+				"name":  "Doe, John",
+				"value": int64(42),
+			},
+		},
+		{
+			name:  "escaped single quotes",
+			quote: "'",
+			input: "name,value\n'it''s \"quoted\", really',42",
+			expected: map[string]interface{}{
+				"name":  `it's "quoted", really`,
+				"value": int64(42),
+			},
+		},
+		{
+			name:      "single quotes with custom delimiter",
+			quote:     "'",
+			delimiter: ";",
+			input:     "name;value\n'Doe; \"John\"';42",
+			expected: map[string]interface{}{
+				"name":  `Doe; "John"`,
+				"value": int64(42),
+			},
+		},
+		{
+			name:      "comma quotes with custom delimiter",
+			quote:     ",",
+			delimiter: ";",
+			input:     "name;value\n,Doe; John,;42",
+			expected: map[string]interface{}{
+				"name":  "Doe; John",
+				"value": int64(42),
+			},
+		},
+		{
+			name:  "lazy quotes",
+			lazy:  true,
+			input: "name,value\n5\" disk,42",
+			expected: map[string]interface{}{
+				"name":  `5" disk`,
+				"value": int64(42),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				HeaderRowCount: 1,
+				Quote:          tt.quote,
+				Delimiter:      tt.delimiter,
+				LazyQuotes:     tt.lazy,
+				TimeFunc:       DefaultTime,
+			}
+			require.NoError(t, p.Init())
+
+			metrics, err := p.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			require.Len(t, metrics, 1)
+			require.Equal(t, tt.expected, metrics[0].Fields())
+		})
+	}
+}
+
+func TestQuotedFieldsParseLine(t *testing.T) {
+	p := &Parser{
+		ColumnNames: []string{"name", "value"},
+		Quote:       "'",
+		TimeFunc:    DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	m, err := p.ParseLine("'Doe, John',42")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "Doe, John", "value": int64(42)}, m.Fields())
+}
+
+func TestInvalidQuote(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount: 1,
+		Quote:          "''",
+	}
+	require.ErrorContains(t, p.Init(), "csv_quote must be a single character")
+
+	p = &Parser{
+		HeaderRowCount: 1,
+		Delimiter:      ";",
+		Quote:          ";",
+	}
+	require.ErrorContains(t, p.Init(), `invalid csv_quote ";"`)
+
+	p = &Parser{
+		HeaderRowCount: 1,
+		Delimiter:      "\n",
+		Quote:          ",",
+	}
+	require.ErrorContains(t, p.Init(), `csv_quote "," cannot be used with csv_delimiter "\n"`)
+}
+
+func TestQuotePlaceholderInData(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount: 1,
+		Quote:          "'",
+		TimeFunc:       DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	_, err := p.Parse([]byte("name,value\n'Doe\ue000',42"))
+	require.ErrorContains(t, err, "reserved character U+E000")
+
+	_, err = p.ParseLine("'Doe\ue000',42")
+	require.ErrorContains(t, err, "reserved character U+E000")
+}
+
+func TestUnpivot(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount:  1,
+		MetricName:      "csv",
+		TagColumns:      []string{"site"},
+		UnpivotColumns:  []string{"temperature", "humidity", "pressure"},
+		TimestampColumn: "time",
+		TimestampFormat: "unix",
+		TimeFunc:        DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `site,sensor,time,temperature,humidity,pressure
+berlin,1,1658774489,21.5,45,1013.2`
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"csv",
+			map[string]string{"site": "berlin", "sensor": "1", "column": "temperature"},
+			map[string]interface{}{"value": 21.5},
+			time.Unix(1658774489, 0),
+		),
+		metric.New(
+			"csv",
+			map[string]string{"site": "berlin", "sensor": "1", "column": "humidity"},
+			map[string]interface{}{"value": int64(45)},
+			time.Unix(1658774489, 0),
+		),
+		metric.New(
+			"csv",
+			map[string]string{"site": "berlin", "sensor": "1", "column": "pressure"},
+			map[string]interface{}{"value": 1013.2},
+			time.Unix(1658774489, 0),
+		),
+	}
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestUnpivotCustomNames(t *testing.T) {
+	p := &Parser{
+		HeaderRowCount: 1,
+		MetricName:     "csv",
+		UnpivotColumns: []string{"a", "b"},
+		UnpivotTag:     "channel",
+		UnpivotField:   "reading",
+		SkipValues:     []string{""},
+		TimeFunc:       DefaultTime,
+	}
+	require.NoError(t, p.Init())
+
+	testCSV := `id,a,b
+x,1,`
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"csv",
+			map[string]string{"id": "x", "channel": "a"},
+			map[string]interface{}{"reading": int64(1)},
+			DefaultTime(),
+		),
+	}
+
+	metrics, err := p.Parse([]byte(testCSV))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestSkipRowsWithWrongColumnCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      int
+		columnNames []string
+		data        string
+	}{
+		{
+			name:   "header",
+			header: 1,
+			data:   "name,a,b\nrow1,1,2\nrow2,3\nrow3,4,5,6\nrow4,7,8\n",
+		},
+		{
+			name:        "column names",
+			columnNames: []string{"name", "a", "b"},
+			data:        "row1,1,2\nrow2,3\nrow3,4,5,6\nrow4,7,8\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				HeaderRowCount: tt.header,
+				ColumnNames:    tt.columnNames,
+				SkipErrors:     true,
+				TimeFunc:       DefaultTime,
+				Log:            testutil.Logger{},
+			}
+			require.NoError(t, p.Init())
+
+			// The short and the long row must be skipped and counted
+			metrics, err := p.Parse([]byte(tt.data))
+			require.NoError(t, err)
+			require.Len(t, metrics, 2)
+			require.Equal(t, 2, p.SkippedRows())
+			require.Equal(t, "row1", metrics[0].Fields()["name"])
+			require.Equal(t, "row4", metrics[1].Fields()["name"])
+		})
+	}
+}
+
 func TestParseMetadataSeparators(t *testing.T) {
 	p := &Parser{
 		ColumnNames:        []string{"a", "b"},
@@ -0,0 +1,43 @@
+package parsers
+
+import (
+	"fmt"
+	"time"
+)
+
+// durationUnits contains the supported units for converting durations
+var durationUnits = map[string]time.Duration{
+	"":   time.Nanosecond,
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// CheckDurationUnit returns an error if the given unit is not supported by
+// ParseDuration.
+func CheckDurationUnit(unit string) error {
+	if _, found := durationUnits[unit]; !found {
+		return fmt.Errorf("invalid duration unit %q", unit)
+	}
+	return nil
+}
+
+// ParseDuration converts a duration string like "1h30m" or "250ms" to an
+// integer in the given unit, truncating fractions of the unit. Supported
+// units are "ns", "us", "ms", "s", "m" and "h" with an empty unit denoting
+// nanoseconds.
+func ParseDuration(value, unit string) (int64, error) {
+	scale, found := durationUnits[unit]
+	if !found {
+		return 0, fmt.Errorf("invalid duration unit %q", unit)
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return int64(d / scale), nil
+}
@@ -0,0 +1,63 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		unit     string
+		expected int64
+	}{
+		{
+			name:     "nanoseconds",
+			value:    "1h30m",
+			expected: 5400000000000,
+		},
+		{
+			name:     "explicit nanoseconds",
+			value:    "250ms",
+			unit:     "ns",
+			expected: 250000000,
+		},
+		{
+			name:     "milliseconds",
+			value:    "1h30m",
+			unit:     "ms",
+			expected: 5400000,
+		},
+		{
+			name:     "seconds truncated",
+			value:    "1m30.9s",
+			unit:     "s",
+			expected: 90,
+		},
+		{
+			name:     "negative",
+			value:    "-1.5h",
+			unit:     "m",
+			expected: -90,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseDuration(tt.value, tt.unit)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	_, err := ParseDuration("1h30m", "days")
+	require.EqualError(t, err, `invalid duration unit "days"`)
+	require.EqualError(t, CheckDurationUnit("days"), `invalid duration unit "days"`)
+
+	_, err = ParseDuration("90", "s")
+	require.ErrorContains(t, err, "missing unit in duration")
+}
@@ -3,8 +3,10 @@ package form_urlencoded
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -19,6 +21,20 @@ type Parser struct {
 	MetricName  string            `toml:"-"`
 	TagKeys     []string          `toml:"form_urlencoded_tag_keys"`
 	DefaultTags map[string]string `toml:"-"`
+
+	RepeatedKeys string `toml:"form_urlencoded_repeated_keys"`
+}
+
+// Init validates the parser configuration
+func (p *Parser) Init() error {
+	switch p.RepeatedKeys {
+	case "":
+		p.RepeatedKeys = "first"
+	case "first", "last", "array":
+	default:
+		return fmt.Errorf("invalid repeated keys setting %q", p.RepeatedKeys)
+	}
+	return nil
 }
 
 // Parse converts a slice of bytes in "application/x-www-form-urlencoded" format into metrics
@@ -73,7 +89,14 @@ func (p Parser) extractTags(values url.Values) map[string]string {
 			continue
 		}
 
-		tags[key] = value[0]
+		switch p.RepeatedKeys {
+		case "last":
+			tags[key] = value[len(value)-1]
+		case "array":
+			tags[key] = strings.Join(value, ",")
+		default:
+			tags[key] = value[0]
+		}
 		delete(values, key)
 	}
 
@@ -88,6 +111,20 @@ func (p Parser) parseFields(values url.Values) map[string]interface{} {
 			continue
 		}
 
+		switch p.RepeatedKeys {
+		case "last":
+			value = value[len(value)-1:]
+		case "array":
+			if len(value) > 1 {
+				for i, v := range value {
+					if field, err := strconv.ParseFloat(v, 64); err == nil {
+						fields[key+"_"+strconv.Itoa(i)] = field
+					}
+				}
+				continue
+			}
+		}
+
 		field, err := strconv.ParseFloat(value[0], 64)
 		if err != nil {
 			continue
@@ -175,6 +175,66 @@ func TestParseInvalidFormDataEmptyString(t *testing.T) {
 	require.Empty(t, metrics)
 }
 
+func TestParseRepeatedKeys(t *testing.T) {
+	input := "host=a%20b&host=c&cpu=5&cpu=7.5&cpu=idle&mem=42"
+
+	tests := []struct {
+		name         string
+		repeatedKeys string
+		tags         map[string]string
+		fields       map[string]interface{}
+	}{
+		{
+			name: "default",
+			tags: map[string]string{"host": "a b"},
+			fields: map[string]interface{}{
+				"cpu": float64(5),
+				"mem": float64(42),
+			},
+		},
+		{
+			name:         "last",
+			repeatedKeys: "last",
+			tags:         map[string]string{"host": "c"},
+			fields: map[string]interface{}{
+				"mem": float64(42),
+			},
+		},
+		{
+			name:         "array",
+			repeatedKeys: "array",
+			tags:         map[string]string{"host": "a b,c"},
+			fields: map[string]interface{}{
+				"cpu_0": float64(5),
+				"cpu_1": float64(7.5),
+				"mem":   float64(42),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := Parser{
+				MetricName:   "form_urlencoded_test",
+				TagKeys:      []string{"host"},
+				RepeatedKeys: tt.repeatedKeys,
+			}
+			require.NoError(t, parser.Init())
+
+			metrics, err := parser.Parse([]byte(input))
+			require.NoError(t, err)
+			require.Len(t, metrics, 1)
+			require.Equal(t, tt.tags, metrics[0].Tags())
+			require.Equal(t, tt.fields, metrics[0].Fields())
+		})
+	}
+}
+
+func TestInitInvalidRepeatedKeys(t *testing.T) {
+	parser := Parser{RepeatedKeys: "all"}
+	require.EqualError(t, parser.Init(), `invalid repeated keys setting "all"`)
+}
+
 const benchmarkData = `tags_host=myhost&tags_platform=python&tags_sdkver=3.11.5&value=5`
 
 func TestBenchmarkData(t *testing.T) {
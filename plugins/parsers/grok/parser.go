@@ -3,17 +3,23 @@ package grok
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vjeantet/grok"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/parsers"
@@ -65,8 +71,23 @@ var (
 	modifierRe = regexp.MustCompile(`%{\w+:(\w+):(ts-".+"|t?s?-?\w+)}`)
 	// matches a plain pattern name. ie, %{NUMBER}
 	patternOnlyRe = regexp.MustCompile(`%{(\w+)}`)
+
+	// grokCache contains the grok instances shared by all parsers with an
+	// identical set of patterns, keyed by the hash of the patterns. Grok
+	// instances are safe for concurrent use and cache the compiled
+	// expressions, so sharing avoids compiling the patterns per parser.
+	// The keys are ordered from the least to the most recently used one.
+	grokCache     = make(map[[sha256.Size]byte]*grok.Grok)
+	grokCacheKeys [][sha256.Size]byte
+	grokCacheLock sync.Mutex
 )
 
+// grokCacheSize is the maximum number of pattern sets in the cache. Parsers
+// keep using their instance after it was evicted, so the limit only bounds
+// the memory kept for pattern sets no longer used, e.g. after reloading the
+// configuration.
+const grokCacheSize = 32
+
 // Parser is the primary struct to handle and grok-patterns defined in the config toml
 type Parser struct {
 	Patterns []string `toml:"grok_patterns"`
@@ -95,6 +116,49 @@ type Parser struct {
 	// UniqueTimestamp when set to "disable", timestamp will not incremented if there is a duplicate.
 	UniqueTimestamp string `toml:"grok_unique_timestamp"`
 
+	// PatternMeasurements is an optional list of measurement names used
+	// when the pattern at the same index in Patterns matches.
+	PatternMeasurements []string `toml:"grok_pattern_measurements"`
+
+	// PatternTimezones is an optional list of timezones used for the
+	// timestamps when the pattern at the same index in Patterns matches.
+	// Empty entries fall back to Timezone.
+	PatternTimezones []string `toml:"grok_pattern_timezones"`
+
+	// CaptureRemainder is the name of the field storing the unmatched suffix
+	// of a line, or the whole line if no pattern matches.
+	// Default: "" which disables capturing
+	CaptureRemainder string `toml:"grok_capture_remainder"`
+
+	// TypeOverrides is a map of capture name -> modifier overriding the
+	// modifier specified in the pattern.
+	//   ie, {"resp_bytes": "float", "auth": "drop"}
+	TypeOverrides map[string]string `toml:"grok_type_overrides"`
+
+	// DurationUnit is the unit of integer fields created by the "duration"
+	// modifier, one of "ns", "us", "ms", "s", "m" or "h".
+	// Default: "ns"
+	DurationUnit string `toml:"grok_duration_unit"`
+
+	// EmitMatchedPattern adds a "grok_pattern" tag containing the name of
+	// the matching pattern as given in PatternNames or its index in Patterns.
+	EmitMatchedPattern bool `toml:"grok_emit_matched_pattern"`
+
+	// PatternNames is an optional list of names used for the
+	// "grok_pattern" tag when the pattern at the same index in Patterns
+	// matches. Empty entries fall back to the index.
+	PatternNames []string `toml:"grok_pattern_names"`
+
+	// MultilineStart is a regular expression matching the first line of a
+	// record. All following lines not matching the expression are joined to
+	// the record before applying the patterns.
+	MultilineStart string `toml:"grok_multiline_start"`
+	// MultilineTimeout is the time after which a pending record is flushed
+	// on the next call for the same source or by Flush even if no new record
+	// was started. The time is measured from the first line of the record.
+	// Default: 0 which flushes pending records at the end of each call
+	MultilineTimeout config.Duration `toml:"grok_multiline_timeout"`
+
 	// typeMap is a map of patterns -> capture name -> modifier,
 	//   ie, {
 	//          "%{TESTLOG}":
@@ -119,6 +183,21 @@ type Parser struct {
 	//          "RESPONSE_CODE": "%{NUMBER:rc:tag}"
 	//       }
 	patternsMap map[string]string
+	// customPatterns are the CustomPatterns as configured before compiling
+	// adds the default and internal patterns.
+	customPatterns *string
+	// patternMeasurements is a map of named patterns -> measurement name
+	// as specified in PatternMeasurements.
+	patternMeasurements map[string]string
+	// patternLocations is a map of named patterns -> location as specified
+	// in PatternTimezones.
+	patternLocations map[string]*time.Location
+	// patternIDs is a map of named patterns -> name or index of the pattern
+	// as used for the "grok_pattern" tag.
+	patternIDs map[string]string
+	// remainderPatterns is a map of named patterns -> pattern additionally
+	// capturing the remainder of the line into CaptureRemainder.
+	remainderPatterns map[string]string
 	// foundTsLayouts is a slice of timestamp patterns that have been found
 	// in the log lines. This slice gets updated if the user uses the generic
 	// 'ts' modifier for timestamps. This slice is checked first for matches,
@@ -127,8 +206,15 @@ type Parser struct {
 	foundTsLayouts []string
 
 	timeFunc func() time.Time
-	g        *grok.Grok
-	tsModder *tsModder
+	// defaultTime is the function providing the time of metrics without a
+	// timestamp, it defaults to time.Now.
+	defaultTime func() time.Time
+	g           *grok.Grok
+	tsModder    *tsModder
+
+	multilineStart *regexp.Regexp
+	pending        map[string]*pendingRecord
+	pendingLock    sync.Mutex
 }
 
 // Compile is a bound method to Parser which will process the options for our parser
@@ -138,18 +224,42 @@ func (p *Parser) Compile() error {
 	p.patternsMap = make(map[string]string)
 	p.tsModder = &tsModder{}
 	var err error
-	p.g, err = grok.NewWithConfig(&grok.Config{NamedCapturesOnly: true})
-	if err != nil {
-		return err
+
+	// Start from the configured custom patterns so compiling again does not
+	// add the default and internal patterns multiple times
+	if p.customPatterns == nil {
+		custom := p.CustomPatterns
+		p.customPatterns = &custom
 	}
+	p.CustomPatterns = *p.customPatterns
 
 	if p.UniqueTimestamp == "" {
 		p.UniqueTimestamp = "auto"
 	}
 
+	if err := parsers.CheckDurationUnit(p.DurationUnit); err != nil {
+		return err
+	}
+
+	if len(p.PatternMeasurements) > 0 && len(p.PatternMeasurements) != len(p.Patterns) {
+		return fmt.Errorf("number of pattern measurements (%d) does not match number of patterns (%d)",
+			len(p.PatternMeasurements), len(p.Patterns))
+	}
+	if len(p.PatternTimezones) > 0 && len(p.PatternTimezones) != len(p.Patterns) {
+		return fmt.Errorf("number of pattern timezones (%d) does not match number of patterns (%d)",
+			len(p.PatternTimezones), len(p.Patterns))
+	}
+	if len(p.PatternNames) > 0 && len(p.PatternNames) != len(p.Patterns) {
+		return fmt.Errorf("number of pattern names (%d) does not match number of patterns (%d)",
+			len(p.PatternNames), len(p.Patterns))
+	}
+
 	// Give Patterns fake names so that they can be treated as named
 	// "custom patterns"
 	p.NamedPatterns = make([]string, 0, len(p.Patterns))
+	p.patternMeasurements = make(map[string]string)
+	p.patternLocations = make(map[string]*time.Location)
+	p.patternIDs = make(map[string]string)
 	for i, pattern := range p.Patterns {
 		pattern = strings.TrimSpace(pattern)
 		if pattern == "" {
@@ -158,12 +268,52 @@ func (p *Parser) Compile() error {
 		name := fmt.Sprintf("GROK_INTERNAL_PATTERN_%d", i)
 		p.CustomPatterns += "\n" + name + " " + pattern + "\n"
 		p.NamedPatterns = append(p.NamedPatterns, "%{"+name+"}")
+		p.patternIDs["%{"+name+"}"] = strconv.Itoa(i)
+		if len(p.PatternNames) > 0 && p.PatternNames[i] != "" {
+			p.patternIDs["%{"+name+"}"] = p.PatternNames[i]
+		}
+		if len(p.PatternMeasurements) > 0 && p.PatternMeasurements[i] != "" {
+			p.patternMeasurements["%{"+name+"}"] = p.PatternMeasurements[i]
+		}
+		if len(p.PatternTimezones) > 0 && p.PatternTimezones[i] != "" {
+			loc, err := time.LoadLocation(p.PatternTimezones[i])
+			if err != nil {
+				return fmt.Errorf("invalid timezone %q for pattern %q: %w", p.PatternTimezones[i], pattern, err)
+			}
+			p.patternLocations["%{"+name+"}"] = loc
+		}
 	}
 
 	if len(p.NamedPatterns) == 0 {
 		return errors.New("pattern required")
 	}
 
+	for name, modifier := range p.TypeOverrides {
+		switch modifier {
+		case Int, Float, Duration, Tag, String, Drop:
+		default:
+			return fmt.Errorf("invalid type override %q for %q", modifier, name)
+		}
+	}
+
+	if p.MultilineStart != "" {
+		if p.Multiline {
+			return errors.New("grok_multiline and grok_multiline_start cannot be used together")
+		}
+		p.multilineStart, err = regexp.Compile(p.MultilineStart)
+		if err != nil {
+			return fmt.Errorf("compiling multiline start expression failed: %w", err)
+		}
+	}
+
+	p.remainderPatterns = make(map[string]string)
+	if p.CaptureRemainder != "" {
+		p.CustomPatterns += "\nGROK_INTERNAL_REMAINDER (?s:.*)\n"
+		for _, pattern := range p.NamedPatterns {
+			p.remainderPatterns[pattern] = pattern + "%{GROK_INTERNAL_REMAINDER:" + p.CaptureRemainder + "}"
+		}
+	}
+
 	// Combine user-supplied CustomPatterns with DEFAULT_PATTERNS and parse
 	// them together as the same type of pattern.
 	p.CustomPatterns = DefaultPatterns + p.CustomPatterns
@@ -204,7 +354,11 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 	// the matching pattern string
 	var patternName string
 	for _, pattern := range p.NamedPatterns {
-		if values, err = p.g.Parse(pattern, line); err != nil {
+		expr := pattern
+		if remainder, ok := p.remainderPatterns[pattern]; ok {
+			expr = remainder
+		}
+		if values, err = p.g.Parse(expr, line); err != nil {
 			return nil, err
 		}
 		if len(values) != 0 {
@@ -215,7 +369,10 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 
 	if len(values) == 0 {
 		p.Log.Debugf("Grok no match found for or no data extracted from: %q", line)
-		return nil, nil
+		if p.CaptureRemainder == "" || line == "" {
+			return nil, nil
+		}
+		values = map[string]string{p.CaptureRemainder: line}
 	}
 
 	fields := make(map[string]interface{})
@@ -226,7 +383,26 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 		tags[k] = v
 	}
 
+	// use the measurement name associated with the matching pattern
+	measurement := p.Measurement
+	if name, ok := p.patternMeasurements[patternName]; ok {
+		measurement = name
+	}
+
+	if p.EmitMatchedPattern && patternName != "" {
+		tags["grok_pattern"] = p.patternIDs[patternName]
+	}
+
+	// use the timezone associated with the matching pattern
+	loc := p.loc
+	if l, ok := p.patternLocations[patternName]; ok {
+		loc = l
+	}
+
 	timestamp := time.Now()
+	if p.defaultTime != nil {
+		timestamp = p.defaultTime()
+	}
 	for k, v := range values {
 		if k == "" || v == "" {
 			continue
@@ -246,6 +422,10 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 				}
 			}
 		}
+		// apply user-defined overrides of the modifier
+		if override, ok := p.TypeOverrides[k]; ok {
+			t = override
+		}
 		// if we didn't find a type OR timestamp modifier, assume string
 		if t == "" {
 			t = String
@@ -254,6 +434,7 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 		switch t {
 		case Measurement:
 			p.Measurement = v
+			measurement = v
 		case Int:
 			iv, err := strconv.ParseInt(v, 0, 64)
 			if err != nil {
@@ -269,11 +450,11 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 				fields[k] = fv
 			}
 		case Duration:
-			d, err := time.ParseDuration(v)
+			d, err := parsers.ParseDuration(v, p.DurationUnit)
 			if err != nil {
 				p.Log.Errorf("Error parsing %s to duration: %s", v, err)
 			} else {
-				fields[k] = int64(d)
+				fields[k] = d
 			}
 		case Tag:
 			tags[k] = v
@@ -319,7 +500,7 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 				timestamp = time.Unix(0, iv)
 			}
 		case SyslogTimestamp:
-			ts, err := internal.ParseTimestamp(time.Stamp, v, p.loc)
+			ts, err := internal.ParseTimestamp(time.Stamp, v, loc)
 			if err == nil {
 				if ts.Year() == 0 {
 					ts = ts.AddDate(timestamp.Year(), 0, 0)
@@ -332,7 +513,7 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 			var foundTs bool
 			// first try timestamp layouts that we've already found
 			for _, layout := range p.foundTsLayouts {
-				ts, err := internal.ParseTimestamp(layout, v, p.loc)
+				ts, err := internal.ParseTimestamp(layout, v, loc)
 				if err == nil {
 					timestamp = ts
 					foundTs = true
@@ -343,7 +524,7 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 			// layouts.
 			if !foundTs {
 				for _, layout := range timeLayouts {
-					ts, err := internal.ParseTimestamp(layout, v, p.loc)
+					ts, err := internal.ParseTimestamp(layout, v, loc)
 					if err == nil {
 						timestamp = ts
 						foundTs = true
@@ -362,7 +543,7 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 		// goodbye!
 		default:
 			v = strings.ReplaceAll(v, ",", ".")
-			ts, err := internal.ParseTimestamp(t, v, p.loc)
+			ts, err := parsers.ParseTimestamp(t, v, loc)
 			if err == nil {
 				if ts.Year() == 0 {
 					ts = ts.AddDate(timestamp.Year(), 0, 0)
@@ -375,13 +556,17 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 	}
 
 	if p.UniqueTimestamp != "auto" {
-		return metric.New(p.Measurement, tags, fields, timestamp), nil
+		return metric.New(measurement, tags, fields, timestamp), nil
 	}
 
-	return metric.New(p.Measurement, tags, fields, p.tsModder.tsMod(timestamp)), nil
+	return metric.New(measurement, tags, fields, p.tsModder.tsMod(timestamp)), nil
 }
 
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if p.multilineStart != nil {
+		return p.parseMultilineRecords("", buf)
+	}
+
 	metrics := make([]telegraf.Metric, 0)
 
 	if p.Multiline {
@@ -412,10 +597,178 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 	return metrics, nil
 }
 
+// ParseSource parses the given data like Parse but keeps pending multiline
+// records separately for each source, e.g. a file or a series, so records
+// are only continued by data of the same source.
+func (p *Parser) ParseSource(source string, buf []byte) ([]telegraf.Metric, error) {
+	if p.multilineStart != nil {
+		return p.parseMultilineRecords(source, buf)
+	}
+	return p.Parse(buf)
+}
+
+// pendingRecord contains the lines of a multiline record kept across calls
+// and the time the record was started.
+type pendingRecord struct {
+	lines []string
+	since time.Time
+}
+
+// parseMultilineRecords joins continuation lines to the record started by
+// the last line matching the multiline start expression. The last record is
+// kept pending for the given source across calls if a timeout is set as
+// following calls might contain further continuation lines. Records failing
+// to parse are logged and skipped unless all records fail.
+func (p *Parser) parseMultilineRecords(source string, buf []byte) ([]telegraf.Metric, error) {
+	p.pendingLock.Lock()
+	defer p.pendingLock.Unlock()
+
+	now := p.timeFunc()
+	timeout := time.Duration(p.MultilineTimeout)
+
+	records := make([]string, 0)
+	pending := p.pending[source]
+	if pending != nil && now.Sub(pending.since) >= timeout {
+		records = append(records, strings.Join(pending.lines, "\n"))
+		pending = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if pending != nil && p.multilineStart.MatchString(line) {
+			records = append(records, strings.Join(pending.lines, "\n"))
+			pending = nil
+		}
+		if pending == nil {
+			pending = &pendingRecord{since: now}
+		}
+		pending.lines = append(pending.lines, line)
+	}
+
+	if pending != nil && timeout == 0 {
+		records = append(records, strings.Join(pending.lines, "\n"))
+		pending = nil
+	}
+
+	if pending != nil {
+		if p.pending == nil {
+			p.pending = make(map[string]*pendingRecord)
+		}
+		p.pending[source] = pending
+	} else {
+		delete(p.pending, source)
+	}
+
+	metrics, errs := p.parseRecords(records)
+
+	// Only fail if none of the records could be parsed
+	if len(errs) > 0 && len(errs) == len(records) {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		p.Log.Errorf("Skipping record: %v", err)
+	}
+
+	return metrics, nil
+}
+
+// Flush parses the pending multiline records of all sources which exceeded
+// the timeout, or all pending records if forced, and returns the resulting
+// metrics per source. Records failing to parse are logged and skipped.
+func (p *Parser) Flush(force bool) map[string][]telegraf.Metric {
+	p.pendingLock.Lock()
+	defer p.pendingLock.Unlock()
+
+	now := p.timeFunc()
+	timeout := time.Duration(p.MultilineTimeout)
+
+	result := make(map[string][]telegraf.Metric)
+	for source, pending := range p.pending {
+		if !force && now.Sub(pending.since) < timeout {
+			continue
+		}
+		delete(p.pending, source)
+
+		metrics, errs := p.parseRecords([]string{strings.Join(pending.lines, "\n")})
+		for _, err := range errs {
+			p.Log.Errorf("Skipping record: %v", err)
+		}
+		if len(metrics) > 0 {
+			result[source] = metrics
+		}
+	}
+
+	return result
+}
+
+// parseRecords parses the given multiline records and returns the resulting
+// metrics along with the errors of the records failing to parse.
+func (p *Parser) parseRecords(records []string) ([]telegraf.Metric, []error) {
+	metrics := make([]telegraf.Metric, 0, len(records))
+	var errs []error
+	for _, record := range records {
+		m, err := p.ParseLine(record)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, errs
+}
+
 func (p *Parser) SetDefaultTags(tags map[string]string) {
 	p.DefaultTags = tags
 }
 
+// SetTimeFunc sets the function used to determine the time of metrics
+// without a timestamp.
+func (p *Parser) SetTimeFunc(fn func() time.Time) {
+	p.defaultTime = fn
+}
+
+// Clone returns a new parser with the same configuration. The parser keeps
+// state like the found timestamp layouts or pending multiline records, so
+// concurrent users must use their own instance.
+func (p *Parser) Clone() (telegraf.Parser, error) {
+	// Use the custom patterns as configured as the ones of the parser
+	// already contain the default and internal patterns
+	custom := p.CustomPatterns
+	if p.customPatterns != nil {
+		custom = *p.customPatterns
+	}
+
+	clone := &Parser{
+		Patterns:            slices.Clone(p.Patterns),
+		CustomPatterns:      custom,
+		CustomPatternFiles:  slices.Clone(p.CustomPatternFiles),
+		Multiline:           p.Multiline,
+		Measurement:         p.Measurement,
+		DefaultTags:         maps.Clone(p.DefaultTags),
+		Log:                 p.Log,
+		Timezone:            p.Timezone,
+		UniqueTimestamp:     p.UniqueTimestamp,
+		PatternMeasurements: slices.Clone(p.PatternMeasurements),
+		PatternTimezones:    slices.Clone(p.PatternTimezones),
+		CaptureRemainder:    p.CaptureRemainder,
+		EmitMatchedPattern:  p.EmitMatchedPattern,
+		PatternNames:        slices.Clone(p.PatternNames),
+		TypeOverrides:       maps.Clone(p.TypeOverrides),
+		DurationUnit:        p.DurationUnit,
+		MultilineStart:      p.MultilineStart,
+		MultilineTimeout:    p.MultilineTimeout,
+		timeFunc:            p.timeFunc,
+		defaultTime:         p.defaultTime,
+	}
+	if err := clone.Init(); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
 func (p *Parser) addCustomPatterns(scanner *bufio.Scanner) {
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -454,7 +807,55 @@ func (p *Parser) compileCustomPatterns() error {
 		}
 	}
 
-	return p.g.AddPatternsFromMap(p.patternsMap)
+	p.g, err = sharedGrok(p.patternsMap)
+	return err
+}
+
+// sharedGrok returns the grok instance for the given patterns from the cache
+// or creates and caches a new instance if no parser used the patterns yet.
+func sharedGrok(patterns map[string]string) (*grok.Grok, error) {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write([]byte(patterns[name]))
+		h.Write([]byte{0})
+	}
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+
+	grokCacheLock.Lock()
+	defer grokCacheLock.Unlock()
+
+	if g, found := grokCache[key]; found {
+		i := slices.Index(grokCacheKeys, key)
+		grokCacheKeys = append(slices.Delete(grokCacheKeys, i, i+1), key)
+		return g, nil
+	}
+
+	g, err := grok.NewWithConfig(&grok.Config{NamedCapturesOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	// Return the instance on errors without caching it to keep the parser
+	// usable for the valid patterns
+	if err := g.AddPatternsFromMap(patterns); err != nil {
+		return g, err
+	}
+	// Evict the least recently used pattern sets
+	for len(grokCacheKeys) >= grokCacheSize {
+		delete(grokCache, grokCacheKeys[0])
+		grokCacheKeys = slices.Delete(grokCacheKeys, 0, 1)
+	}
+	grokCache[key] = g
+	grokCacheKeys = append(grokCacheKeys, key)
+	return g, nil
 }
 
 // parseTypedCaptures parses the capture modifiers, and then deletes the
@@ -1,14 +1,17 @@
 package grok
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
 )
@@ -232,6 +235,195 @@ func TestBuiltinCombinedLogFormat(t *testing.T) {
 	require.Equal(t, map[string]string{"verb": "GET", "resp_code": "200"}, m.Tags())
 }
 
+func TestEmitMatchedPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		line     string
+		expected string
+	}{
+		{
+			name:     "index of first pattern",
+			line:     `app: ERROR something failed`,
+			expected: "0",
+		},
+		{
+			name:     "index of second pattern",
+			line:     `127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla"`,
+			expected: "1",
+		},
+		{
+			name:     "name of second pattern",
+			names:    []string{"app", "access"},
+			line:     `127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla"`,
+			expected: "access",
+		},
+		{
+			name:     "index for empty name",
+			names:    []string{"", "access"},
+			line:     `app: ERROR something failed`,
+			expected: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				Patterns:           []string{`^app: %{LOGLEVEL:level:tag} %{GREEDYDATA:message}$`, "%{COMBINED_LOG_FORMAT}"},
+				EmitMatchedPattern: true,
+				PatternNames:       tt.names,
+			}
+			require.NoError(t, p.Compile())
+
+			m, err := p.ParseLine(tt.line)
+			require.NoError(t, err)
+			require.NotNil(t, m)
+
+			tag, found := m.GetTag("grok_pattern")
+			require.True(t, found)
+			require.Equal(t, tt.expected, tag)
+		})
+	}
+}
+
+func TestEmitMatchedPatternDisabled(t *testing.T) {
+	p := &Parser{
+		Patterns:     []string{"%{COMBINED_LOG_FORMAT}"},
+		PatternNames: []string{"access"},
+	}
+	require.NoError(t, p.Compile())
+
+	m, err := p.ParseLine(`127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla"`)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	require.Equal(t, map[string]string{"verb": "GET", "resp_code": "200"}, m.Tags())
+}
+
+func TestPatternNamesMismatch(t *testing.T) {
+	p := &Parser{
+		Patterns:     []string{"%{COMBINED_LOG_FORMAT}"},
+		PatternNames: []string{"a", "b"},
+	}
+	require.EqualError(t, p.Compile(), "number of pattern names (2) does not match number of patterns (1)")
+}
+
+func TestTypeOverrides(t *testing.T) {
+	p := &Parser{
+		Patterns: []string{"%{COMBINED_LOG_FORMAT}"},
+		TypeOverrides: map[string]string{
+			"resp_bytes": "float",
+			"auth":       "drop",
+			"agent":      "tag",
+			"resp_code":  "int",
+		},
+	}
+	require.NoError(t, p.Compile())
+
+	m, err := p.ParseLine(`127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla"`)
+	require.NotNil(t, m)
+	require.NoError(t, err)
+	require.Equal(t,
+		map[string]interface{}{
+			"resp_bytes":   float64(2326),
+			"resp_code":    int64(200),
+			"client_ip":    "127.0.0.1",
+			"http_version": float64(1.0),
+			"ident":        "user-identifier",
+			"request":      "/apache_pb.gif",
+			"referrer":     "-",
+		},
+		m.Fields())
+	require.Equal(t, map[string]string{"verb": "GET", "agent": "Mozilla"}, m.Tags())
+}
+
+func TestTypeOverridesInvalid(t *testing.T) {
+	p := &Parser{
+		Patterns:      []string{"%{COMBINED_LOG_FORMAT}"},
+		TypeOverrides: map[string]string{"resp_bytes": "uint"},
+	}
+	require.ErrorContains(t, p.Compile(), `invalid type override "uint" for "resp_bytes"`)
+}
+
+func TestCaptureRemainder(t *testing.T) {
+	p := &Parser{
+		Measurement:      "access_log",
+		Patterns:         []string{"%{COMBINED_LOG_FORMAT}"},
+		CaptureRemainder: "_unparsed",
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, p.Compile())
+
+	tests := []struct {
+		name     string
+		line     string
+		expected map[string]interface{}
+	}{
+		{
+			name: "full match",
+			line: `127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla"`,
+			expected: map[string]interface{}{
+				"resp_bytes":   int64(2326),
+				"auth":         "frank",
+				"client_ip":    "127.0.0.1",
+				"http_version": float64(1.0),
+				"ident":        "user-identifier",
+				"request":      "/apache_pb.gif",
+				"referrer":     "-",
+				"agent":        "Mozilla",
+			},
+		},
+		{
+			name: "trailing text",
+			line: `127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla" rt=0.042 upstream=backend-1`,
+			expected: map[string]interface{}{
+				"resp_bytes":   int64(2326),
+				"auth":         "frank",
+				"client_ip":    "127.0.0.1",
+				"http_version": float64(1.0),
+				"ident":        "user-identifier",
+				"request":      "/apache_pb.gif",
+				"referrer":     "-",
+				"agent":        "Mozilla",
+				"_unparsed":    " rt=0.042 upstream=backend-1",
+			},
+		},
+		{
+			name: "no match",
+			line: `this is not an access log line`,
+			expected: map[string]interface{}{
+				"_unparsed": "this is not an access log line",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := p.ParseLine(tt.line)
+			require.NoError(t, err)
+			require.NotNil(t, m)
+			require.Equal(t, "access_log", m.Name())
+			require.Equal(t, tt.expected, m.Fields())
+		})
+	}
+}
+
+func TestCaptureRemainderDisabled(t *testing.T) {
+	p := &Parser{
+		Patterns: []string{"%{COMBINED_LOG_FORMAT}"},
+		Log:      testutil.Logger{},
+	}
+	require.NoError(t, p.Compile())
+
+	m, err := p.ParseLine(`127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla" rt=0.042`)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	require.NotContains(t, m.Fields(), "_unparsed")
+
+	m, err = p.ParseLine(`this is not an access log line`)
+	require.NoError(t, err)
+	require.Nil(t, m)
+}
+
 func TestCompileStringAndParse(t *testing.T) {
 	p := &Parser{
 		Patterns: []string{"%{TEST_LOG_A}"},
@@ -257,6 +449,45 @@ func TestCompileStringAndParse(t *testing.T) {
 	require.Equal(t, map[string]string{"response_code": "200"}, metricA.Tags())
 }
 
+func TestDurationUnit(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     string
+		expected int64
+	}{
+		{
+			name:     "default",
+			expected: 5400000000000,
+		},
+		{
+			name:     "seconds",
+			unit:     "s",
+			expected: 5400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Parser{
+				Patterns:     []string{"uptime=%{NOTSPACE:uptime:duration}"},
+				DurationUnit: tt.unit,
+				Log:          testutil.Logger{},
+			}
+			require.NoError(t, p.Compile())
+
+			m, err := p.ParseLine("uptime=1h30m")
+			require.NoError(t, err)
+			require.Equal(t, map[string]interface{}{"uptime": tt.expected}, m.Fields())
+		})
+	}
+
+	p := &Parser{
+		Patterns:     []string{"uptime=%{NOTSPACE:uptime:duration}"},
+		DurationUnit: "days",
+	}
+	require.EqualError(t, p.Compile(), `invalid duration unit "days"`)
+}
+
 func TestCompileErrorsOnInvalidPattern(t *testing.T) {
 	p := &Parser{
 		Patterns: []string{"%{TEST_LOG_A}", "%{TEST_LOG_B}"},
@@ -544,6 +775,52 @@ func TestCompileFileAndParse(t *testing.T) {
 		metricB.Time().Nanosecond())
 }
 
+func TestPatternMeasurements(t *testing.T) {
+	p := &Parser{
+		Measurement:         "default",
+		Patterns:            []string{"%{TEST_LOG_A}", "%{TEST_LOG_B}", "%{WORD:word} %{NUMBER:value:int}"},
+		PatternMeasurements: []string{"log_a", "log_b", ""},
+		CustomPatternFiles:  []string{"./testdata/test-patterns"},
+	}
+	require.NoError(t, p.Compile())
+
+	metricA, err := p.ParseLine(`[04/Jun/2016:12:41:45 +0100] 1.25 200 192.168.1.1 5.432µs 101`)
+	require.NoError(t, err)
+	require.NotNil(t, metricA)
+	require.Equal(t, "log_a", metricA.Name())
+
+	metricB, err := p.ParseLine(`[04/06/2016--12:41:46] 1.25 mystring dropme nomodifier`)
+	require.NoError(t, err)
+	require.NotNil(t, metricB)
+	require.Equal(t, "log_b", metricB.Name())
+
+	metricC, err := p.ParseLine(`foo 42`)
+	require.NoError(t, err)
+	require.NotNil(t, metricC)
+	require.Equal(t, "default", metricC.Name())
+}
+
+func TestPatternMeasurementsModifierPrecedence(t *testing.T) {
+	p := &Parser{
+		Patterns:            []string{"%{WORD:name:measurement} %{NUMBER:value:int}"},
+		PatternMeasurements: []string{"static"},
+	}
+	require.NoError(t, p.Compile())
+
+	m, err := p.ParseLine(`dynamic 42`)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	require.Equal(t, "dynamic", m.Name())
+}
+
+func TestPatternMeasurementsMismatch(t *testing.T) {
+	p := &Parser{
+		Patterns:            []string{"%{TEST_LOG_A}", "%{TEST_LOG_B}"},
+		PatternMeasurements: []string{"log_a"},
+	}
+	require.ErrorContains(t, p.Compile(), "number of pattern measurements (1) does not match number of patterns (2)")
+}
+
 func TestCompileNoModifiersAndParse(t *testing.T) {
 	p := &Parser{
 		Patterns: []string{"%{TEST_LOG_C}"},
@@ -988,6 +1265,73 @@ func TestTimezoneLocalCompileFileAndParse(t *testing.T) {
 	require.Equal(t, time.Date(2016, time.June, 4, 12, 41, 46, 0, time.Local).UnixNano(), metricB.Time().UnixNano())
 }
 
+func TestPatternTimezones(t *testing.T) {
+	p := &Parser{
+		Patterns: []string{
+			`east %{TIMESTAMP_ISO8601:ts:ts-"2006-01-02 15:04:05"} %{NUMBER:value:int}`,
+			`west %{TIMESTAMP_ISO8601:ts:ts-"2006-01-02 15:04:05"} %{NUMBER:value:int}`,
+			`default %{TIMESTAMP_ISO8601:ts:ts-"2006-01-02 15:04:05"} %{NUMBER:value:int}`,
+		},
+		PatternTimezones: []string{"America/New_York", "America/Los_Angeles", ""},
+		Timezone:         "Europe/Berlin",
+		UniqueTimestamp:  "disable",
+	}
+	require.NoError(t, p.Compile())
+
+	tests := []struct {
+		line     string
+		expected time.Time
+	}{
+		{
+			line:     "east 2016-01-15 12:00:00 1",
+			expected: time.Date(2016, time.January, 15, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			line:     "east 2016-07-15 12:00:00 1",
+			expected: time.Date(2016, time.July, 15, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			line:     "east 2016-03-13 01:30:00 1",
+			expected: time.Date(2016, time.March, 13, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			line:     "east 2016-03-13 03:30:00 1",
+			expected: time.Date(2016, time.March, 13, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			line:     "west 2016-01-15 12:00:00 1",
+			expected: time.Date(2016, time.January, 15, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			line:     "default 2016-01-15 12:00:00 1",
+			expected: time.Date(2016, time.January, 15, 11, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			m, err := p.ParseLine(tt.line)
+			require.NoError(t, err)
+			require.NotNil(t, m)
+			require.Equal(t, tt.expected, m.Time().UTC())
+		})
+	}
+}
+
+func TestPatternTimezonesInvalid(t *testing.T) {
+	p := &Parser{
+		Patterns:         []string{"%{TEST_LOG_A}", "%{TEST_LOG_B}"},
+		PatternTimezones: []string{"", "Mars/Olympus_Mons"},
+	}
+	require.ErrorContains(t, p.Compile(), `invalid timezone "Mars/Olympus_Mons" for pattern "%{TEST_LOG_B}"`)
+
+	p = &Parser{
+		Patterns:         []string{"%{TEST_LOG_A}", "%{TEST_LOG_B}"},
+		PatternTimezones: []string{"UTC"},
+	}
+	require.ErrorContains(t, p.Compile(), "number of pattern timezones (1) does not match number of patterns (2)")
+}
+
 func TestNewlineInPatterns(t *testing.T) {
 	p := &Parser{
 		Patterns: []string{`
@@ -1169,6 +1513,226 @@ func TestTrimRegression(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestMultilineStart(t *testing.T) {
+	input := `2022-12-01T12:41:45Z ERROR java.lang.IllegalStateException: boom
+	at com.example.Foo.bar(Foo.java:42)
+	at com.example.Main.main(Main.java:7)
+2022-12-01T12:41:46Z INFO started
+2022-12-01T12:41:47Z ERROR java.lang.NullPointerException
+	at com.example.Foo.baz(Foo.java:13)`
+
+	p := &Parser{
+		Measurement:    "java",
+		Patterns:       []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart: `^\d{4}-\d{2}-\d{2}T`,
+	}
+	require.NoError(t, p.Compile())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"java",
+			map[string]string{"level": "ERROR"},
+			map[string]interface{}{
+				"message": "java.lang.IllegalStateException: boom\n\tat com.example.Foo.bar(Foo.java:42)\n\tat com.example.Main.main(Main.java:7)",
+			},
+			time.Date(2022, time.December, 1, 12, 41, 45, 0, time.UTC),
+		),
+		metric.New(
+			"java",
+			map[string]string{"level": "INFO"},
+			map[string]interface{}{"message": "started"},
+			time.Date(2022, time.December, 1, 12, 41, 46, 0, time.UTC),
+		),
+		metric.New(
+			"java",
+			map[string]string{"level": "ERROR"},
+			map[string]interface{}{
+				"message": "java.lang.NullPointerException\n\tat com.example.Foo.baz(Foo.java:13)",
+			},
+			time.Date(2022, time.December, 1, 12, 41, 47, 0, time.UTC),
+		),
+	}
+
+	actual, err := p.Parse([]byte(input))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestMultilineStartAcrossCalls(t *testing.T) {
+	now := time.Date(2022, time.December, 1, 12, 0, 0, 0, time.UTC)
+	p := &Parser{
+		Measurement:      "java",
+		Patterns:         []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart:   `^\d{4}-\d{2}-\d{2}T`,
+		MultilineTimeout: config.Duration(5 * time.Second),
+		timeFunc:         func() time.Time { return now },
+	}
+	require.NoError(t, p.Compile())
+
+	// The record is kept pending as continuation lines might follow
+	actual, err := p.Parse([]byte("2022-12-01T12:41:45Z ERROR java.lang.IllegalStateException: boom"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	actual, err = p.Parse([]byte("\tat com.example.Foo.bar(Foo.java:42)"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	// A new record flushes the pending one
+	actual, err = p.Parse([]byte("2022-12-01T12:41:46Z INFO started"))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "java.lang.IllegalStateException: boom\n\tat com.example.Foo.bar(Foo.java:42)", actual[0].Fields()["message"])
+
+	// The timeout flushes the pending record on the next call
+	now = now.Add(10 * time.Second)
+	actual, err = p.Parse(nil)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "started", actual[0].Fields()["message"])
+}
+
+func TestMultilineStartTimeoutFromFirstLine(t *testing.T) {
+	now := time.Date(2022, time.December, 1, 12, 0, 0, 0, time.UTC)
+	p := &Parser{
+		Measurement:      "java",
+		Patterns:         []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart:   `^\d{4}-\d{2}-\d{2}T`,
+		MultilineTimeout: config.Duration(5 * time.Second),
+		timeFunc:         func() time.Time { return now },
+	}
+	require.NoError(t, p.Compile())
+
+	actual, err := p.Parse([]byte("2022-12-01T12:41:45Z ERROR boom"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	// Continuation lines do not extend the timeout of the record
+	now = now.Add(3 * time.Second)
+	actual, err = p.Parse([]byte("\tat com.example.Foo.bar(Foo.java:42)"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	now = now.Add(3 * time.Second)
+	actual, err = p.Parse([]byte("\tat com.example.Foo.baz(Foo.java:43)"))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "boom\n\tat com.example.Foo.bar(Foo.java:42)", actual[0].Fields()["message"])
+}
+
+func TestMultilineStartSources(t *testing.T) {
+	p := &Parser{
+		Measurement:      "java",
+		Patterns:         []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart:   `^\d{4}-\d{2}-\d{2}T`,
+		MultilineTimeout: config.Duration(time.Hour),
+	}
+	require.NoError(t, p.Compile())
+
+	actual, err := p.ParseSource("a", []byte("2022-12-01T12:41:45Z ERROR boom"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	actual, err = p.ParseSource("b", []byte("2022-12-01T12:41:46Z INFO started"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	// Continuation lines are only joined to the record of the same source
+	actual, err = p.ParseSource("b", []byte("\tcontinued\n2022-12-01T12:41:47Z INFO done"))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "started\n\tcontinued", actual[0].Fields()["message"])
+
+	actual, err = p.ParseSource("a", []byte("2022-12-01T12:41:48Z INFO next"))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "boom", actual[0].Fields()["message"])
+}
+
+func TestMultilineStartFlush(t *testing.T) {
+	now := time.Date(2022, time.December, 1, 12, 0, 0, 0, time.UTC)
+	p := &Parser{
+		Measurement:      "java",
+		Patterns:         []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart:   `^\d{4}-\d{2}-\d{2}T`,
+		MultilineTimeout: config.Duration(5 * time.Second),
+		timeFunc:         func() time.Time { return now },
+	}
+	require.NoError(t, p.Compile())
+
+	actual, err := p.ParseSource("a", []byte("2022-12-01T12:41:45Z ERROR boom"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	now = now.Add(3 * time.Second)
+	actual, err = p.ParseSource("b", []byte("2022-12-01T12:41:46Z INFO started"))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	// Records are only flushed once their timeout expired
+	require.Empty(t, p.Flush(false))
+
+	now = now.Add(3 * time.Second)
+	flushed := p.Flush(false)
+	require.Len(t, flushed, 1)
+	require.Len(t, flushed["a"], 1)
+	require.Equal(t, "boom", flushed["a"][0].Fields()["message"])
+
+	// Forcing the flush returns all remaining records
+	flushed = p.Flush(true)
+	require.Len(t, flushed, 1)
+	require.Len(t, flushed["b"], 1)
+	require.Equal(t, "started", flushed["b"][0].Fields()["message"])
+	require.Empty(t, p.Flush(true))
+}
+
+func TestMultilineStartConcurrent(t *testing.T) {
+	p := &Parser{
+		Measurement:    "java",
+		Patterns:       []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart: `^\d{4}-\d{2}-\d{2}T`,
+	}
+	require.NoError(t, p.Compile())
+
+	input := []byte("2022-12-01T12:41:45Z ERROR boom\n\tat Foo.bar(Foo.java:42)\n2022-12-01T12:41:46Z INFO started")
+
+	var wg sync.WaitGroup
+	results := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			metrics, err := p.Parse(input)
+			if err != nil {
+				results <- -1
+				return
+			}
+			results <- len(metrics)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	for n := range results {
+		require.Equal(t, 2, n)
+	}
+}
+
+func TestMultilineStartInvalid(t *testing.T) {
+	p := &Parser{
+		Patterns:       []string{"%{COMBINED_LOG_FORMAT}"},
+		MultilineStart: `^(`,
+	}
+	require.ErrorContains(t, p.Compile(), "compiling multiline start expression failed")
+
+	p = &Parser{
+		Patterns:       []string{"%{COMBINED_LOG_FORMAT}"},
+		Multiline:      true,
+		MultilineStart: `^\d`,
+	}
+	require.ErrorContains(t, p.Compile(), "cannot be used together")
+}
+
 func TestMultilineNilMetric(t *testing.T) {
 	buf, err := os.ReadFile("./testdata/test_multiline.log")
 	require.NoError(t, err)
@@ -1189,6 +1753,121 @@ const benchmarkData = `benchmark 5 1653643421 source=myhost tags_platform=python
 benchmark 4 1653643422 source=myhost tags_platform=python tags_sdkver=3.11.4
 `
 
+func TestClone(t *testing.T) {
+	parser := &Parser{
+		Measurement:      "logs",
+		Patterns:         []string{"%{WORD:level:tag} %{GREEDYDATA:message}"},
+		MultilineStart:   `^\w+ `,
+		MultilineTimeout: config.Duration(time.Hour),
+	}
+	require.NoError(t, parser.Init())
+
+	// Keep a pending record in the original parser
+	_, err := parser.Parse([]byte("ERROR first"))
+	require.NoError(t, err)
+
+	clone, err := parser.Clone()
+	require.NoError(t, err)
+	require.NotSame(t, parser, clone)
+
+	// The clone must not see the pending record of the original
+	actual, err := clone.Parse([]byte("INFO second\nWARN third"))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"logs",
+			map[string]string{"level": "INFO"},
+			map[string]interface{}{"message": "second"},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestCloneConfig(t *testing.T) {
+	parser := &Parser{
+		Measurement:      "logs",
+		Patterns:         []string{"%{LEVEL:level:tag} %{WORD:message}"},
+		CustomPatterns:   "LEVEL (INFO|WARN)",
+		CaptureRemainder: "rest",
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	clone, err := parser.Clone()
+	require.NoError(t, err)
+	cloned, ok := clone.(*Parser)
+	require.True(t, ok)
+
+	// Default and internal patterns must only be added once
+	require.Equal(t, parser.CustomPatterns, cloned.CustomPatterns)
+	require.Equal(t, parser.NamedPatterns, cloned.NamedPatterns)
+
+	// The configuration must not share memory with the original
+	cloned.Patterns[0] = "%{WORD:message}"
+	require.Equal(t, "%{LEVEL:level:tag} %{WORD:message}", parser.Patterns[0])
+
+	// Compiling again must not add the patterns again either
+	custom := parser.CustomPatterns
+	require.NoError(t, parser.Compile())
+	require.Equal(t, custom, parser.CustomPatterns)
+}
+
+func TestSharedPatterns(t *testing.T) {
+	p1 := &Parser{
+		Patterns:           []string{"%{TEST_LOG_A}"},
+		CustomPatternFiles: []string{"./testdata/test-patterns"},
+	}
+	require.NoError(t, p1.Init())
+
+	p2 := &Parser{
+		Patterns:           []string{"%{TEST_LOG_A}"},
+		CustomPatternFiles: []string{"./testdata/test-patterns"},
+	}
+	require.NoError(t, p2.Init())
+	require.Same(t, p1.g, p2.g)
+
+	p3 := &Parser{
+		Patterns:           []string{"%{TEST_LOG_B}"},
+		CustomPatternFiles: []string{"./testdata/test-patterns"},
+	}
+	require.NoError(t, p3.Init())
+	require.NotSame(t, p1.g, p3.g)
+
+	// Parsers sharing the patterns must still produce correct results
+	m1, err := p1.ParseLine(`[04/Jun/2016:12:41:45 +0100] 1.25 200 192.168.1.1 5.432µs 101`)
+	require.NoError(t, err)
+	m2, err := p2.ParseLine(`[04/Jun/2016:12:41:45 +0100] 1.25 200 192.168.1.1 5.432µs 101`)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{m1}, []telegraf.Metric{m2})
+}
+
+func TestSharedPatternsEviction(t *testing.T) {
+	p := &Parser{Patterns: []string{"%{WORD:first}"}}
+	require.NoError(t, p.Init())
+
+	// Fill the cache with other pattern sets to evict the first one
+	for i := range grokCacheSize {
+		other := &Parser{Patterns: []string{fmt.Sprintf("%%{WORD:other_%d}", i)}}
+		require.NoError(t, other.Init())
+	}
+
+	grokCacheLock.Lock()
+	require.LessOrEqual(t, len(grokCache), grokCacheSize)
+	require.Len(t, grokCacheKeys, len(grokCache))
+	grokCacheLock.Unlock()
+
+	// The evicted parser must keep working and new parsers get a new instance
+	actual, err := p.ParseLine("hello")
+	require.NoError(t, err)
+	require.Equal(t, "hello", actual.Fields()["first"])
+
+	again := &Parser{Patterns: []string{"%{WORD:first}"}}
+	require.NoError(t, again.Init())
+	require.NotSame(t, p.g, again.g)
+}
+
 func TestBenchmarkData(t *testing.T) {
 	plugin := &Parser{
 		//nolint:lll // conditionally long lines allowed
@@ -1239,3 +1918,14 @@ func BenchmarkParsing(b *testing.B) {
 		_, _ = plugin.Parse([]byte(benchmarkData))
 	}
 }
+
+func BenchmarkInit(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		plugin := &Parser{
+			Patterns:           []string{"%{TEST_LOG_A}", "%{COMBINED_LOG_FORMAT}"},
+			CustomPatternFiles: []string{"./testdata/test-patterns"},
+		}
+		require.NoError(b, plugin.Init())
+		_, _ = plugin.ParseLine(`127.0.0.1 user-identifier frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "-" "Mozilla"`)
+	}
+}
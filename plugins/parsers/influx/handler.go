@@ -3,6 +3,7 @@ package influx
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 type MetricHandler struct {
 	timePrecision time.Duration
 	timeFunc      TimeFunc
+	maxFields     int
+	maxTags       int
 	metric        telegraf.Metric
 }
 
@@ -38,6 +41,25 @@ func (h *MetricHandler) SetTimeFunc(f TimeFunc) {
 	h.timeFunc = f
 }
 
+// SetMaxFields sets the maximum number of fields per metric, zero means
+// unlimited.
+func (h *MetricHandler) SetMaxFields(n int) {
+	h.maxFields = n
+}
+
+// SetMaxTags sets the maximum number of tags per metric, zero means
+// unlimited.
+func (h *MetricHandler) SetMaxTags(n int) {
+	h.maxTags = n
+}
+
+func (h *MetricHandler) checkFieldLimit(key string) error {
+	if h.maxFields > 0 && len(h.metric.FieldList()) >= h.maxFields && !h.metric.HasField(key) {
+		return fmt.Errorf("number of fields exceeds limit of %d", h.maxFields)
+	}
+	return nil
+}
+
 func (h *MetricHandler) Metric() telegraf.Metric {
 	if h.metric.Time().IsZero() {
 		h.metric.SetTime(h.timeFunc().Truncate(h.timePrecision))
@@ -54,12 +76,18 @@ func (h *MetricHandler) SetMeasurement(name []byte) error {
 func (h *MetricHandler) AddTag(key []byte, value []byte) error {
 	tk := unescape(key)
 	tv := unescape(value)
+	if h.maxTags > 0 && len(h.metric.TagList()) >= h.maxTags && !h.metric.HasTag(tk) {
+		return fmt.Errorf("number of tags exceeds limit of %d", h.maxTags)
+	}
 	h.metric.AddTag(tk, tv)
 	return nil
 }
 
 func (h *MetricHandler) AddInt(key []byte, value []byte) error {
 	fk := unescape(key)
+	if err := h.checkFieldLimit(fk); err != nil {
+		return err
+	}
 	fv, err := parseIntBytes(bytes.TrimSuffix(value, []byte("i")), 10, 64)
 	if err != nil {
 		var numErr *strconv.NumError
@@ -74,6 +102,9 @@ func (h *MetricHandler) AddInt(key []byte, value []byte) error {
 
 func (h *MetricHandler) AddUint(key []byte, value []byte) error {
 	fk := unescape(key)
+	if err := h.checkFieldLimit(fk); err != nil {
+		return err
+	}
 	fv, err := parseUintBytes(bytes.TrimSuffix(value, []byte("u")), 10, 64)
 	if err != nil {
 		var numErr *strconv.NumError
@@ -88,6 +119,9 @@ func (h *MetricHandler) AddUint(key []byte, value []byte) error {
 
 func (h *MetricHandler) AddFloat(key []byte, value []byte) error {
 	fk := unescape(key)
+	if err := h.checkFieldLimit(fk); err != nil {
+		return err
+	}
 	fv, err := parseFloatBytes(value, 64)
 	if err != nil {
 		var numErr *strconv.NumError
@@ -102,6 +136,9 @@ func (h *MetricHandler) AddFloat(key []byte, value []byte) error {
 
 func (h *MetricHandler) AddString(key []byte, value []byte) error {
 	fk := unescape(key)
+	if err := h.checkFieldLimit(fk); err != nil {
+		return err
+	}
 	fv := stringFieldUnescape(value)
 	h.metric.AddField(fk, fv)
 	return nil
@@ -109,6 +146,9 @@ func (h *MetricHandler) AddString(key []byte, value []byte) error {
 
 func (h *MetricHandler) AddBool(key []byte, value []byte) error {
 	fk := unescape(key)
+	if err := h.checkFieldLimit(fk); err != nil {
+		return err
+	}
 	fv, err := parseBoolBytes(value)
 	if err != nil {
 		return errors.New("unparsable bool")
@@ -24,7 +24,7 @@ var (
 	ErrEOF      = errors.New("EOF")
 )
 
-type TimeFunc func() time.Time
+type TimeFunc = func() time.Time
 
 // nthIndexAny finds the nth index of some unicode code point in a string or returns -1
 func nthIndexAny(s, chars string, n int) int {
@@ -105,6 +105,9 @@ func convertToParseError(input []byte, rawErr error) error {
 // parsers.Parser interface.
 type Parser struct {
 	InfluxTimestampPrecision config.Duration   `toml:"influx_timestamp_precision"`
+	MaxFields                int               `toml:"influx_max_fields"`
+	MaxTags                  int               `toml:"influx_max_tags"`
+	TimestampTruncate        config.Duration   `toml:"influx_timestamp_truncate"`
 	DefaultTags              map[string]string `toml:"-"`
 	// If set to "series" a series machine will be initialized, defaults to regular machine
 	Type string `toml:"-"`
@@ -127,6 +130,12 @@ func (p *Parser) Parse(input []byte) ([]telegraf.Metric, error) {
 		if err != nil {
 			return nil, convertToParseError(input, err)
 		}
+		if err := p.checkLimits(m); err != nil {
+			return nil, err
+		}
+		if p.TimestampTruncate > 0 {
+			m.SetTime(m.Time().Truncate(time.Duration(p.TimestampTruncate)))
+		}
 		metrics = append(metrics, m)
 	}
 
@@ -134,6 +143,16 @@ func (p *Parser) Parse(input []byte) ([]telegraf.Metric, error) {
 	return metrics, nil
 }
 
+func (p *Parser) checkLimits(m telegraf.Metric) error {
+	if p.MaxFields > 0 && len(m.FieldList()) > p.MaxFields {
+		return fmt.Errorf("metric parse error: number of fields exceeds limit of %d for %q", p.MaxFields, m.Name())
+	}
+	if p.MaxTags > 0 && len(m.TagList()) > p.MaxTags {
+		return fmt.Errorf("metric parse error: number of tags exceeds limit of %d for %q", p.MaxTags, m.Name())
+	}
+	return nil
+}
+
 func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 	metrics, err := p.Parse([]byte(line))
 	if err != nil {
@@ -192,6 +211,9 @@ func (p *Parser) Init() error {
 	if err := p.SetTimePrecision(time.Duration(p.InfluxTimestampPrecision)); err != nil {
 		return err
 	}
+	if p.TimestampTruncate < 0 {
+		return fmt.Errorf("invalid timestamp truncation: %s", time.Duration(p.TimestampTruncate))
+	}
 
 	p.defaultTime = time.Now
 	p.allowPartial = p.Type == "series"
@@ -213,6 +235,7 @@ type StreamParser struct {
 	decoder     *lineprotocol.Decoder
 	defaultTime TimeFunc
 	precision   lineprotocol.Precision
+	truncate    time.Duration
 	lastError   error
 }
 
@@ -250,6 +273,12 @@ func (sp *StreamParser) SetTimePrecision(u time.Duration) error {
 	return nil
 }
 
+// SetTimestampTruncate rounds the timestamps of parsed metrics down to the
+// given duration, see the influx_timestamp_truncate option of the Parser.
+func (sp *StreamParser) SetTimestampTruncate(d time.Duration) {
+	sp.truncate = d
+}
+
 // Next parses the next item from the stream.  You can repeat calls to this
 // function if it returns ParseError to get the next metric or error.
 func (sp *StreamParser) Next() (telegraf.Metric, error) {
@@ -266,6 +295,9 @@ func (sp *StreamParser) Next() (telegraf.Metric, error) {
 	if err != nil {
 		return nil, convertToParseError([]byte{}, err)
 	}
+	if sp.truncate > 0 {
+		m.SetTime(m.Time().Truncate(sp.truncate))
+	}
 
 	return m, nil
 }
@@ -874,6 +874,71 @@ func TestParserInvalidTimestampPrecision(t *testing.T) {
 	}
 }
 
+func TestParserMaxFields(t *testing.T) {
+	parser := Parser{MaxFields: 2}
+	require.NoError(t, parser.Init())
+
+	metrics, err := parser.Parse([]byte("cpu,host=a value=1,other=2 0\n"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Len(t, metrics[0].FieldList(), 2)
+
+	_, err = parser.Parse([]byte("cpu value=1,other=2,third=3 0\n"))
+	require.ErrorContains(t, err, "number of fields exceeds limit of 2")
+}
+
+func TestParserMaxTags(t *testing.T) {
+	parser := Parser{MaxTags: 1}
+	require.NoError(t, parser.Init())
+
+	metrics, err := parser.Parse([]byte("cpu,host=a value=1 0\n"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+
+	_, err = parser.Parse([]byte("cpu,host=a,region=b value=1 0\n"))
+	require.ErrorContains(t, err, "number of tags exceeds limit of 1")
+}
+
+func TestParserMaxTagsIgnoresDefaultTags(t *testing.T) {
+	parser := Parser{
+		MaxTags:     1,
+		DefaultTags: map[string]string{"region": "b"},
+	}
+	require.NoError(t, parser.Init())
+
+	metrics, err := parser.Parse([]byte("cpu,host=a value=1 0\n"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Len(t, metrics[0].TagList(), 2)
+}
+
+func TestParserTimestampTruncate(t *testing.T) {
+	parser := Parser{TimestampTruncate: config.Duration(time.Second)}
+	require.NoError(t, parser.Init())
+
+	metrics, err := parser.Parse([]byte("cpu value=1 1530654676316265790\n"))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	require.Equal(t, time.Unix(1530654676, 0), metrics[0].Time())
+}
+
+func TestStreamParserTimestampTruncate(t *testing.T) {
+	parser := NewStreamParser(strings.NewReader("cpu value=1 1530654676316265790\n"))
+	parser.SetTimestampTruncate(time.Second)
+
+	m, err := parser.Next()
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1530654676, 0), m.Time())
+
+	_, err = parser.Next()
+	require.ErrorIs(t, err, ErrEOF)
+}
+
+func TestParserInvalidTimestampTruncate(t *testing.T) {
+	parser := Parser{TimestampTruncate: config.Duration(-time.Second)}
+	require.ErrorContains(t, parser.Init(), "invalid timestamp truncation")
+}
+
 func TestParserErrorString(t *testing.T) {
 	var ptests = []struct {
 		name      string
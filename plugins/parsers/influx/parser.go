@@ -21,7 +21,7 @@ var (
 	ErrNoMetric = errors.New("no metric in line")
 )
 
-type TimeFunc func() time.Time
+type TimeFunc = func() time.Time
 
 // ParseError indicates a error in the parsing of the text.
 type ParseError struct {
@@ -62,6 +62,9 @@ func (e *ParseError) Error() string {
 // parsers.Parser interface.
 type Parser struct {
 	InfluxTimestampPrecision config.Duration   `toml:"influx_timestamp_precision"`
+	MaxFields                int               `toml:"influx_max_fields"`
+	MaxTags                  int               `toml:"influx_max_tags"`
+	TimestampTruncate        config.Duration   `toml:"influx_timestamp_truncate"`
 	DefaultTags              map[string]string `toml:"-"`
 	// If set to "series" a series machine will be initialized, defaults to regular machine
 	Type string `toml:"-"`
@@ -106,6 +109,9 @@ func (p *Parser) Parse(input []byte) ([]telegraf.Metric, error) {
 		if metric == nil {
 			continue
 		}
+		if p.TimestampTruncate > 0 {
+			metric.SetTime(metric.Time().Truncate(time.Duration(p.TimestampTruncate)))
+		}
 
 		metrics = append(metrics, metric)
 	}
@@ -151,6 +157,8 @@ func (p *Parser) applyDefaultTagsSingle(metric telegraf.Metric) {
 
 func (p *Parser) Init() error {
 	p.handler = NewMetricHandler()
+	p.handler.SetMaxFields(p.MaxFields)
+	p.handler.SetMaxTags(p.MaxTags)
 	if p.Type == "series" {
 		p.machine = NewSeriesMachine(p.handler)
 	} else {
@@ -165,6 +173,9 @@ func (p *Parser) Init() error {
 	default:
 		return fmt.Errorf("invalid time precision: %d", p.InfluxTimestampPrecision)
 	}
+	if p.TimestampTruncate < 0 {
+		return fmt.Errorf("invalid timestamp truncation: %s", time.Duration(p.TimestampTruncate))
+	}
 
 	return nil
 }
@@ -180,8 +191,9 @@ func init() {
 // StreamParser is an InfluxDB Line Protocol parser.  It is not safe for
 // concurrent use in multiple goroutines.
 type StreamParser struct {
-	machine *streamMachine
-	handler *MetricHandler
+	machine  *streamMachine
+	handler  *MetricHandler
+	truncate time.Duration
 }
 
 func NewStreamParser(r io.Reader) *StreamParser {
@@ -203,6 +215,12 @@ func (sp *StreamParser) SetTimePrecision(u time.Duration) {
 	sp.handler.SetTimePrecision(u)
 }
 
+// SetTimestampTruncate rounds the timestamps of parsed metrics down to the
+// given duration, see the influx_timestamp_truncate option of the Parser.
+func (sp *StreamParser) SetTimestampTruncate(d time.Duration) {
+	sp.truncate = d
+}
+
 // Next parses the next item from the stream.  You can repeat calls to this
 // function if it returns ParseError to get the next metric or error.
 func (sp *StreamParser) Next() (telegraf.Metric, error) {
@@ -227,7 +245,11 @@ func (sp *StreamParser) Next() (telegraf.Metric, error) {
 		}
 	}
 
-	return sp.handler.Metric(), nil
+	m := sp.handler.Metric()
+	if m != nil && sp.truncate > 0 {
+		m.SetTime(m.Time().Truncate(sp.truncate))
+	}
+	return m, nil
 }
 
 // Position returns the current byte offset into the data.
@@ -1,12 +1,17 @@
 package json
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 )
 
 type JSONFlattener struct {
 	Fields map[string]interface{}
+
+	// MaxDepth limits the number of flattened levels, deeper levels are
+	// stored as compact JSON string. Zero means unlimited.
+	MaxDepth int
 }
 
 // FlattenJSON flattens nested maps/interfaces into a fields map (ignoring bools and string)
@@ -31,6 +36,32 @@ func (f *JSONFlattener) FullFlattenJSON(
 		f.Fields = make(map[string]interface{})
 	}
 
+	return f.flatten(fieldname, v, convertString, convertBool, 0)
+}
+
+func (f *JSONFlattener) flatten(
+	fieldname string,
+	v interface{},
+	convertString bool,
+	convertBool bool,
+	depth int,
+) error {
+	// Store everything below the maximum depth as JSON string
+	if f.MaxDepth > 0 && depth >= f.MaxDepth {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			if !convertString {
+				return nil
+			}
+			buf, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			f.Fields[fieldname] = string(buf)
+			return nil
+		}
+	}
+
 	switch t := v.(type) {
 	case map[string]interface{}:
 		for k, v := range t {
@@ -39,7 +70,7 @@ func (f *JSONFlattener) FullFlattenJSON(
 				fieldkey = fieldname + "_" + fieldkey
 			}
 
-			err := f.FullFlattenJSON(fieldkey, v, convertString, convertBool)
+			err := f.flatten(fieldkey, v, convertString, convertBool, depth+1)
 			if err != nil {
 				return err
 			}
@@ -50,12 +81,12 @@ func (f *JSONFlattener) FullFlattenJSON(
 			if fieldname != "" {
 				fieldkey = fieldname + "_" + fieldkey
 			}
-			err := f.FullFlattenJSON(fieldkey, v, convertString, convertBool)
+			err := f.flatten(fieldkey, v, convertString, convertBool, depth+1)
 			if err != nil {
 				return err
 			}
 		}
-	case float64:
+	case float64, int64, json.Number:
 		f.Fields[fieldname] = t
 	case string:
 		if !convertString {
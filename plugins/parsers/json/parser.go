@@ -1,18 +1,23 @@
 package json
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"sort"
 	"strconv"
 	"time"
 
+	"github.com/PaesslerAG/gval"
 	"github.com/tidwall/gjson"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/filter"
-	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/parsers"
 )
@@ -23,15 +28,28 @@ var (
 )
 
 type Parser struct {
-	MetricName   string   `toml:"metric_name"`
-	TagKeys      []string `toml:"tag_keys"`
-	NameKey      string   `toml:"json_name_key"`
-	StringFields []string `toml:"json_string_fields"`
-	Query        string   `toml:"json_query"`
-	TimeKey      string   `toml:"json_time_key"`
-	TimeFormat   string   `toml:"json_time_format"`
-	Timezone     string   `toml:"json_timezone"`
-	Strict       bool     `toml:"json_strict"`
+	MetricName       string   `toml:"metric_name"`
+	TagKeys          []string `toml:"tag_keys"`
+	NameKey          string   `toml:"json_name_key"`
+	StringFields     []string `toml:"json_string_fields"`
+	Query            string   `toml:"json_query"`
+	TimeKey          string   `toml:"json_time_key"`
+	TimeFormat       string   `toml:"json_time_format"`
+	Timezone         string   `toml:"json_timezone"`
+	Strict           bool     `toml:"json_strict"`
+	Condition        string   `toml:"json_condition"`
+	FlattenDepth     int      `toml:"json_flatten_depth"`
+	ArrayIndexTag    string   `toml:"json_array_index_tag"`
+	NumberHandling   string   `toml:"json_number_handling"`
+	LineDelimited    bool     `toml:"json_line_delimited"`
+	KeyTag           string   `toml:"json_key_tag"`
+	KeyValueField    string   `toml:"json_key_value_field"`
+	MeasurementKey   string   `toml:"json_measurement_key"`
+	TimeKeys         []string `toml:"json_time_keys"`
+	TimeSelect       string   `toml:"json_time_select"`
+	DuplicateKeys    string   `toml:"json_duplicate_key_handling"`
+	NameCase         string   `toml:"measurement_case"`
+	PreserveIntegers bool     `toml:"json_preserve_integers"`
 
 	DefaultTags map[string]string `toml:"-"`
 	Log         telegraf.Logger   `toml:"-"`
@@ -39,37 +57,131 @@ type Parser struct {
 	location     *time.Location
 	tagFilter    filter.Filter
 	stringFilter filter.Filter
+	condition    gval.Evaluable
+	useNumber    bool
+	timeKeys     []string
+	tokenDecode  bool
+	timeFunc     func() time.Time
 }
 
-func (p *Parser) parseArray(data []interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
+// parseArrayStream decodes the elements of a top-level array one by one and
+// converts them to metrics. This avoids materializing the whole array which
+// keeps memory bounded for large payloads.
+func (p *Parser) parseArrayStream(decoder *json.Decoder, timestamp time.Time) ([]telegraf.Metric, error) {
+	// Consume the opening bracket
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
 	results := make([]telegraf.Metric, 0)
+	for i := 0; decoder.More(); i++ {
+		item, err := p.decode(decoder)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, item := range data {
-		switch v := item.(type) {
-		case map[string]interface{}:
-			metrics, err := p.parseObject(v, timestamp)
-			if err != nil {
-				if p.Strict {
-					return nil, err
-				}
-				continue
-			}
-			results = append(results, metrics...)
-		default:
-			return nil, ErrWrongType
+		metrics, err := p.parseArrayItem(i, item, timestamp)
+		if err != nil {
+			return nil, err
 		}
+		results = append(results, metrics...)
+	}
+
+	// Consume the closing bracket and make sure nothing follows
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after top-level value")
 	}
 
 	return results, nil
 }
 
+// parseArrayItem converts the element with the given index of a top-level
+// array to metrics. Invalid objects are skipped unless in strict mode.
+func (p *Parser) parseArrayItem(i int, item interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
+	v, ok := item.(map[string]interface{})
+	if !ok {
+		return nil, ErrWrongType
+	}
+
+	if err := p.convertNumbers(v); err != nil {
+		if p.Strict {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if p.condition != nil {
+		ok, err := p.condition.EvalBool(context.Background(), v)
+		if err != nil {
+			if p.Strict {
+				return nil, fmt.Errorf("evaluating condition failed: %w", err)
+			}
+			return nil, nil
+		}
+		if !ok {
+			return nil, nil
+		}
+	}
+	metrics, err := p.parseObject(v, timestamp)
+	if err != nil {
+		if p.Strict {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if p.ArrayIndexTag != "" {
+		for _, m := range metrics {
+			m.AddTag(p.ArrayIndexTag, strconv.Itoa(i))
+		}
+	}
+	return metrics, nil
+}
+
 func (p *Parser) parseObject(data map[string]interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
+	if p.KeyTag != "" {
+		return p.parseKeys(data, timestamp)
+	}
+	return p.parseFlattened(data, timestamp)
+}
+
+// parseKeys creates one metric per top-level key of the object with the key
+// stored in the configured tag. Scalar and array values are stored in the
+// configured value field while objects are flattened into the metric.
+func (p *Parser) parseKeys(data map[string]interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	metrics := make([]telegraf.Metric, 0, len(keys))
+	for _, k := range keys {
+		obj, ok := data[k].(map[string]interface{})
+		if !ok {
+			obj = map[string]interface{}{p.KeyValueField: data[k]}
+		}
+
+		m, err := p.parseFlattened(obj, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k, err)
+		}
+		for _, x := range m {
+			x.AddTag(p.KeyTag, k)
+		}
+		metrics = append(metrics, m...)
+	}
+	return metrics, nil
+}
+
+func (p *Parser) parseFlattened(data map[string]interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
 	tags := make(map[string]string)
 	for k, v := range p.DefaultTags {
 		tags[k] = v
 	}
 
-	f := JSONFlattener{}
+	f := JSONFlattener{MaxDepth: p.FlattenDepth}
 	err := f.FullFlattenJSON("", data, true, true)
 	if err != nil {
 		return nil, err
@@ -80,39 +192,77 @@ func (p *Parser) parseObject(data map[string]interface{}, timestamp time.Time) (
 	// checks if json_name_key is set
 	if p.NameKey != "" {
 		if field, ok := f.Fields[p.NameKey].(string); ok {
-			name = field
+			name = parsers.NormalizeMeasurement(field, p.NameCase)
 		}
 	}
 
-	// if time key is specified, set timestamp to it
-	if p.TimeKey != "" {
+	// use the value of json_measurement_key as name and consume the key
+	if p.MeasurementKey != "" {
+		if field, ok := f.Fields[p.MeasurementKey].(string); ok && field != "" {
+			name = parsers.NormalizeMeasurement(field, p.NameCase)
+			delete(f.Fields, p.MeasurementKey)
+		}
+	}
+
+	// if time keys are specified, set timestamp to the selected one
+	if len(p.timeKeys) > 0 {
 		if p.TimeFormat == "" {
 			err := errors.New("use of 'json_time_key' requires 'json_time_format'")
 			return nil, err
 		}
 
-		if f.Fields[p.TimeKey] == nil {
-			err := errors.New("JSON time key could not be found")
+		timestamp, err = p.selectTimestamp(f.Fields)
+		if err != nil {
 			return nil, err
 		}
+	}
+
+	tags, nFields := p.switchFieldToTag(tags, f.Fields)
+	m := metric.New(name, tags, nFields, timestamp)
 
-		timestamp, err = internal.ParseTimestamp(p.TimeFormat, f.Fields[p.TimeKey], p.location)
+	return []telegraf.Metric{m}, nil
+}
+
+// selectTimestamp parses the values of all time keys present in the fields
+// and returns the timestamp chosen according to the time selection. The time
+// keys are removed from the fields.
+func (p *Parser) selectTimestamp(fields map[string]interface{}) (time.Time, error) {
+	var selected time.Time
+	var found bool
+	for _, key := range p.timeKeys {
+		ts := fields[key]
+		if ts == nil {
+			continue
+		}
+		if n, ok := ts.(json.Number); ok {
+			ts = n.String()
+		}
+		timestamp, err := parsers.ParseTimestamp(p.TimeFormat, ts, p.location)
 		if err != nil {
-			return nil, err
+			return time.Time{}, err
 		}
-
-		delete(f.Fields, p.TimeKey)
+		delete(fields, key)
 
 		// if the year is 0, set to current year
 		if timestamp.Year() == 0 {
 			timestamp = timestamp.AddDate(time.Now().Year(), 0, 0)
 		}
-	}
 
-	tags, nFields := p.switchFieldToTag(tags, f.Fields)
-	m := metric.New(name, tags, nFields, timestamp)
+		switch {
+		case !found:
+			selected = timestamp
+		case p.TimeSelect == "min" && timestamp.Before(selected):
+			selected = timestamp
+		case p.TimeSelect == "max" && timestamp.After(selected):
+			selected = timestamp
+		}
+		found = true
+	}
 
-	return []telegraf.Metric{m}, nil
+	if !found {
+		return time.Time{}, errors.New("JSON time key could not be found")
+	}
+	return selected, nil
 }
 
 // will take in field map with strings and bools,
@@ -139,19 +289,28 @@ func (p *Parser) switchFieldToTag(tags map[string]string, fields map[string]inte
 		case float64:
 			tags[name] = strconv.FormatFloat(t, 'f', -1, 64)
 			delete(fields, name)
+		case int64:
+			tags[name] = strconv.FormatInt(t, 10)
+			delete(fields, name)
+		case json.Number:
+			tags[name] = t.String()
+			delete(fields, name)
 		default:
 			p.Log.Errorf("Unrecognized type %T", value)
 		}
 	}
 
-	// remove any additional string/bool values from fields
+	// remove any additional string/bool values from fields and keep numbers
+	// that cannot be represented as float as string
 	for fk := range fields {
-		switch fields[fk].(type) {
+		switch t := fields[fk].(type) {
 		case string, bool:
 			if p.stringFilter != nil && p.stringFilter.Match(fk) {
 				continue
 			}
 			delete(fields, fk)
+		case json.Number:
+			fields[fk] = t.String()
 		}
 	}
 	return tags, fields
@@ -170,6 +329,55 @@ func (p *Parser) Init() error {
 		return fmt.Errorf("compiling tag-key filter failed: %w", err)
 	}
 
+	switch p.NumberHandling {
+	case "":
+		p.NumberHandling = "float"
+	case "float":
+	case "string", "strict":
+		p.useNumber = true
+	default:
+		return fmt.Errorf("invalid number handling %q", p.NumberHandling)
+	}
+	if p.PreserveIntegers {
+		p.useNumber = true
+	}
+
+	if err := parsers.CheckMeasurementCase(p.NameCase); err != nil {
+		return err
+	}
+
+	switch p.DuplicateKeys {
+	case "":
+		p.DuplicateKeys = "last"
+	case "last":
+	case "first", "error":
+		p.tokenDecode = true
+	default:
+		return fmt.Errorf("invalid duplicate key handling %q", p.DuplicateKeys)
+	}
+
+	if p.KeyValueField == "" {
+		p.KeyValueField = "value"
+	}
+
+	if p.FlattenDepth < 0 {
+		return fmt.Errorf("invalid flatten depth %d", p.FlattenDepth)
+	}
+
+	p.timeKeys = make([]string, 0, len(p.TimeKeys)+1)
+	if p.TimeKey != "" {
+		p.timeKeys = append(p.timeKeys, p.TimeKey)
+	}
+	p.timeKeys = append(p.timeKeys, p.TimeKeys...)
+
+	switch p.TimeSelect {
+	case "":
+		p.TimeSelect = "first"
+	case "first", "min", "max":
+	default:
+		return fmt.Errorf("invalid time selection %q", p.TimeSelect)
+	}
+
 	if p.Timezone != "" {
 		loc, err := time.LoadLocation(p.Timezone)
 		if err != nil {
@@ -178,10 +386,205 @@ func (p *Parser) Init() error {
 		p.location = loc
 	}
 
+	if p.Condition != "" {
+		p.condition, err = gval.Full().NewEvaluable(p.Condition)
+		if err != nil {
+			return fmt.Errorf("compiling condition failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// decode reads the next value from the decoder. Duplicate keys in objects
+// are resolved by the standard decoder by using the last value. For other
+// duplicate-key handling the value is decoded token by token as decoding
+// into a map loses the duplicates.
+func (p *Parser) decode(decoder *json.Decoder) (interface{}, error) {
+	if !p.tokenDecode {
+		var data interface{}
+		err := decoder.Decode(&data)
+		return data, err
+	}
+	return p.decodeTokens(decoder)
+}
+
+// decodeTokens reads the next value from the decoder token by token keeping
+// the first value of duplicate keys or returning an error depending on the
+// duplicate-key handling.
+func (p *Parser) decodeTokens(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := make(map[string]interface{})
+		for decoder.More() {
+			t, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := t.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected token %v for object key", t)
+			}
+
+			value, err := p.decodeTokens(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, found := obj[key]; found {
+				if p.DuplicateKeys == "error" {
+					return nil, fmt.Errorf("duplicate key %q", key)
+				}
+				continue
+			}
+			obj[key] = value
+		}
+		// Consume the closing brace
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case json.Delim('['):
+		arr := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := p.decodeTokens(decoder)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		// Consume the closing bracket
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	}
+	return token, nil
+}
+
+// convertNumbers replaces all numbers decoded as json.Number by float64
+// values or, if integers are preserved, by int64 values for integers within
+// the int64 range. Integers not exactly representable as float64 are kept as
+// json.Number or cause an error depending on the number-handling setting.
+func (p *Parser) convertNumbers(data interface{}) error {
+	if !p.useNumber {
+		return nil
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if n, ok := item.(json.Number); ok {
+				value, err := p.convertNumber(n)
+				if err != nil {
+					return fmt.Errorf("converting key %q failed: %w", k, err)
+				}
+				v[k] = value
+				continue
+			}
+			if err := p.convertNumbers(item); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if n, ok := item.(json.Number); ok {
+				value, err := p.convertNumber(n)
+				if err != nil {
+					return fmt.Errorf("converting element %d failed: %w", i, err)
+				}
+				v[i] = value
+				continue
+			}
+			if err := p.convertNumbers(item); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+func (p *Parser) convertNumber(n json.Number) (interface{}, error) {
+	if p.PreserveIntegers {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return nil, err
+	}
+	if p.NumberHandling == "float" {
+		return f, nil
+	}
+
+	// Only integers might lose precision when converting to float
+	if i, ok := new(big.Int).SetString(n.String(), 10); ok {
+		if exact, _ := big.NewFloat(f).Int(nil); exact.Cmp(i) != 0 {
+			if p.NumberHandling == "strict" {
+				return nil, fmt.Errorf("integer %s cannot be represented exactly", n)
+			}
+			return n, nil
+		}
+	}
+	return f, nil
+}
+
 func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if p.LineDelimited {
+		return p.parseLines(buf)
+	}
+	return p.parseDocument(buf)
+}
+
+// parseLines parses each non-empty line of the buffer as a separate JSON
+// document. Lines failing to parse are skipped so a single malformed line
+// does not drop the remaining ones.
+func (p *Parser) parseLines(buf []byte) ([]telegraf.Metric, error) {
+	metrics := make([]telegraf.Metric, 0)
+
+	var errs []error
+	var valid int
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	scanner.Buffer(nil, len(buf)+1)
+	for i := 1; scanner.Scan(); i++ {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		m, err := p.parseDocument(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", i, err))
+			continue
+		}
+		valid++
+		metrics = append(metrics, m...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Only fail if none of the lines could be parsed
+	if valid == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		if p.Log != nil {
+			p.Log.Errorf("Skipping line: %v", err)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) parseDocument(buf []byte) ([]telegraf.Metric, error) {
 	if p.Query != "" {
 		result := gjson.GetBytes(buf, p.Query)
 		buf = []byte(result.Raw)
@@ -200,18 +603,43 @@ func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 		return make([]telegraf.Metric, 0), nil
 	}
 
+	timestamp := p.now().UTC()
+
+	// Stream top-level arrays element by element instead of decoding the
+	// whole array at once
+	if buf[0] == '[' {
+		decoder := json.NewDecoder(bytes.NewReader(buf))
+		if p.useNumber {
+			decoder.UseNumber()
+		}
+		return p.parseArrayStream(decoder, timestamp)
+	}
+
 	var data interface{}
-	err := json.Unmarshal(buf, &data)
-	if err != nil {
-		return nil, err
+	if p.useNumber || p.tokenDecode {
+		decoder := json.NewDecoder(bytes.NewReader(buf))
+		if p.useNumber {
+			decoder.UseNumber()
+		}
+		var err error
+		if data, err = p.decode(decoder); err != nil {
+			return nil, err
+		}
+		if decoder.More() {
+			return nil, errors.New("unexpected data after top-level value")
+		}
+	} else {
+		if err := json.Unmarshal(buf, &data); err != nil {
+			return nil, err
+		}
 	}
 
-	timestamp := time.Now().UTC()
 	switch v := data.(type) {
 	case map[string]interface{}:
+		if err := p.convertNumbers(v); err != nil {
+			return nil, err
+		}
 		return p.parseObject(v, timestamp)
-	case []interface{}:
-		return p.parseArray(v, timestamp)
 	case nil:
 		return nil, nil
 	default:
@@ -233,6 +661,19 @@ func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
 	return metrics[0], nil
 }
 
+// SetTimeFunc sets the function used to determine the time of metrics
+// without a timestamp.
+func (p *Parser) SetTimeFunc(fn func() time.Time) {
+	p.timeFunc = fn
+}
+
+func (p *Parser) now() time.Time {
+	if p.timeFunc == nil {
+		return time.Now()
+	}
+	return p.timeFunc()
+}
+
 func (p *Parser) SetDefaultTags(tags map[string]string) {
 	p.DefaultTags = tags
 }
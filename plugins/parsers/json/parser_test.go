@@ -1,7 +1,10 @@
 package json
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -675,6 +678,451 @@ func TestUseCaseJSONQuery(t *testing.T) {
 	require.Equal(t, "Murphy", actual[0].Fields()["last"])
 }
 
+func TestCondition(t *testing.T) {
+	testString := `{
+		"devices": [
+			{"name": "a", "status": "active", "value": 1, "info": {"level": 3}},
+			{"name": "b", "status": "inactive", "value": 2, "info": {"level": 5}},
+			{"name": "c", "status": "active", "value": 3, "info": {"level": 7}}
+		]
+	}`
+
+	tests := []struct {
+		name      string
+		condition string
+		expected  []telegraf.Metric
+	}{
+		{
+			name: "no condition",
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{"name": "a"}, map[string]interface{}{"value": float64(1), "info_level": float64(3)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"name": "b"}, map[string]interface{}{"value": float64(2), "info_level": float64(5)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"name": "c"}, map[string]interface{}{"value": float64(3), "info_level": float64(7)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:      "string comparison",
+			condition: `status == "active"`,
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{"name": "a"}, map[string]interface{}{"value": float64(1), "info_level": float64(3)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"name": "c"}, map[string]interface{}{"value": float64(3), "info_level": float64(7)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:      "nested key",
+			condition: `info.level > 4 && status == "active"`,
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{"name": "c"}, map[string]interface{}{"value": float64(3), "info_level": float64(7)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:      "no match",
+			condition: `value > 10`,
+			expected:  []telegraf.Metric{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName: "json_test",
+				Query:      "devices",
+				TagKeys:    []string{"name"},
+				Condition:  tt.condition,
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(testString))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestConditionInvalid(t *testing.T) {
+	parser := &Parser{
+		MetricName: "json_test",
+		Condition:  `status ==`,
+	}
+	require.ErrorContains(t, parser.Init(), "compiling condition failed")
+}
+
+func TestFlattenDepth(t *testing.T) {
+	testString := `{
+		"value": 1,
+		"a": {
+			"b": {
+				"c": 2,
+				"d": [1, 2]
+			},
+			"e": 3
+		}
+	}`
+
+	tests := []struct {
+		name     string
+		depth    int
+		expected telegraf.Metric
+	}{
+		{
+			name: "unlimited",
+			expected: metric.New(
+				"json_test",
+				map[string]string{},
+				map[string]interface{}{
+					"value":   float64(1),
+					"a_b_c":   float64(2),
+					"a_b_d_0": float64(1),
+					"a_b_d_1": float64(2),
+					"a_e":     float64(3),
+				},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:  "depth 1",
+			depth: 1,
+			expected: metric.New(
+				"json_test",
+				map[string]string{},
+				map[string]interface{}{
+					"value": float64(1),
+					"a":     `{"b":{"c":2,"d":[1,2]},"e":3}`,
+				},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:  "depth 2",
+			depth: 2,
+			expected: metric.New(
+				"json_test",
+				map[string]string{},
+				map[string]interface{}{
+					"value": float64(1),
+					"a_b":   `{"c":2,"d":[1,2]}`,
+					"a_e":   float64(3),
+				},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:  "depth 3",
+			depth: 3,
+			expected: metric.New(
+				"json_test",
+				map[string]string{},
+				map[string]interface{}{
+					"value": float64(1),
+					"a_b_c": float64(2),
+					"a_b_d": "[1,2]",
+					"a_e":   float64(3),
+				},
+				time.Unix(0, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:   "json_test",
+				StringFields: []string{"*"},
+				FlattenDepth: tt.depth,
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(testString))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			testutil.RequireMetricEqual(t, tt.expected, actual[0], testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestFlattenDepthInvalid(t *testing.T) {
+	parser := &Parser{
+		MetricName:   "json_test",
+		FlattenDepth: -1,
+	}
+	require.ErrorContains(t, parser.Init(), "invalid flatten depth")
+}
+
+func TestArrayIndexTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		query     string
+		condition string
+		expected  []telegraf.Metric
+	}{
+		{
+			name:  "array",
+			input: `[{"value": 1}, {"value": 2}, {"value": 3}]`,
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{"index": "0"}, map[string]interface{}{"value": float64(1)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"index": "1"}, map[string]interface{}{"value": float64(2)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"index": "2"}, map[string]interface{}{"value": float64(3)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:  "query resolving to array",
+			input: `{"data": [{"value": 1}, {"value": 2}]}`,
+			query: "data",
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{"index": "0"}, map[string]interface{}{"value": float64(1)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"index": "1"}, map[string]interface{}{"value": float64(2)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:      "index preserved with condition",
+			input:     `[{"value": 1}, {"value": 2}, {"value": 3}]`,
+			condition: "value > 1",
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{"index": "1"}, map[string]interface{}{"value": float64(2)}, time.Unix(0, 0)),
+				metric.New("json_test", map[string]string{"index": "2"}, map[string]interface{}{"value": float64(3)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:  "object",
+			input: `{"value": 1}`,
+			expected: []telegraf.Metric{
+				metric.New("json_test", map[string]string{}, map[string]interface{}{"value": float64(1)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:     "empty array",
+			input:    `[]`,
+			expected: []telegraf.Metric{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:    "json_test",
+				Query:         tt.query,
+				Condition:     tt.condition,
+				ArrayIndexTag: "index",
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestNumberHandling(t *testing.T) {
+	input := `{"id": 12345678901234567890, "small": 42, "value": 1.5, "nested": {"id": -9007199254740993}}`
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected []telegraf.Metric
+		err      string
+	}{
+		{
+			name: "default",
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{},
+					map[string]interface{}{
+						"id":        float64(12345678901234567890),
+						"small":     float64(42),
+						"value":     float64(1.5),
+						"nested_id": float64(-9007199254740993),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "float",
+			mode: "float",
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{},
+					map[string]interface{}{
+						"id":        float64(12345678901234567890),
+						"small":     float64(42),
+						"value":     float64(1.5),
+						"nested_id": float64(-9007199254740993),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "string",
+			mode: "string",
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{},
+					map[string]interface{}{
+						"id":        "12345678901234567890",
+						"small":     float64(42),
+						"value":     float64(1.5),
+						"nested_id": "-9007199254740993",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "strict",
+			mode: "strict",
+			err:  "cannot be represented exactly",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:     "json_test",
+				NumberHandling: tt.mode,
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(input))
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestNumberHandlingArray(t *testing.T) {
+	input := `[{"id": 1}, {"id": 12345678901234567890}, {"id": 3}]`
+
+	parser := &Parser{
+		MetricName:     "json_test",
+		NumberHandling: "strict",
+		Strict:         false,
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"id": float64(1)}, time.Unix(0, 0)),
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"id": float64(3)}, time.Unix(0, 0)),
+	}
+
+	actual, err := parser.Parse([]byte(input))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	parser.Strict = true
+	_, err = parser.Parse([]byte(input))
+	require.ErrorContains(t, err, "cannot be represented exactly")
+}
+
+func TestNumberHandlingTimestamp(t *testing.T) {
+	parser := &Parser{
+		MetricName:     "json_test",
+		TimeKey:        "time",
+		TimeFormat:     "unix_ns",
+		NumberHandling: "string",
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.Parse([]byte(`{"value": 1, "time": 1568338208000000501}`))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, time.Unix(1568338208, 501).UTC(), actual[0].Time())
+}
+
+func TestPreserveIntegers(t *testing.T) {
+	input := `{"id": 9007199254740993, "small": -42, "value": 1.5, "whole": 2.0, "big": 12345678901234567890, "tag": 7}`
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected []telegraf.Metric
+		err      string
+	}{
+		{
+			name: "float",
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{"tag": "7"},
+					map[string]interface{}{
+						"id":    int64(9007199254740993),
+						"small": int64(-42),
+						"value": float64(1.5),
+						"whole": float64(2),
+						"big":   float64(12345678901234567890),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "string",
+			mode: "string",
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{"tag": "7"},
+					map[string]interface{}{
+						"id":    int64(9007199254740993),
+						"small": int64(-42),
+						"value": float64(1.5),
+						"whole": float64(2),
+						"big":   "12345678901234567890",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "strict",
+			mode: "strict",
+			err:  "cannot be represented exactly",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:       "json_test",
+				TagKeys:          []string{"tag"},
+				NumberHandling:   tt.mode,
+				PreserveIntegers: true,
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(input))
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+
+			// Arrays are streamed element by element
+			actual, err = parser.Parse([]byte("[" + input + "]"))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestNumberHandlingInvalid(t *testing.T) {
+	parser := &Parser{
+		MetricName:     "json_test",
+		NumberHandling: "foo",
+	}
+	require.ErrorContains(t, parser.Init(), "invalid number handling")
+}
+
 func TestTimeParser(t *testing.T) {
 	testString := `[
 		{
@@ -799,6 +1247,35 @@ func TestUnixMsTimeParser(t *testing.T) {
 	require.NotEqual(t, actual[0].Time(), actual[1].Time())
 }
 
+func TestUnixAutoTimeParser(t *testing.T) {
+	testString := `[
+		{"a": 1, "time": 1568338208},
+		{"a": 2, "time": 1568338208500},
+		{"a": 3, "time": "1568338208000500"},
+		{"a": 4, "time": "1568338208000000500"},
+		{"a": 5, "time": 100000000000}
+	]`
+
+	parser := &Parser{
+		MetricName: "json_test",
+		TimeKey:    "time",
+		TimeFormat: "unix_auto",
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"a": float64(1)}, time.Unix(1568338208, 0)),
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"a": float64(2)}, time.Unix(1568338208, 500000000)),
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"a": float64(3)}, time.Unix(1568338208, 500000)),
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"a": float64(4)}, time.Unix(1568338208, 500)),
+		metric.New("json_test", map[string]string{}, map[string]interface{}{"a": float64(5)}, time.Unix(100000000, 0)),
+	}
+
+	actual, err := parser.Parse([]byte(testString))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
 func TestTimeErrors(t *testing.T) {
 	testString := `{
 		"a": 5,
@@ -1104,8 +1581,29 @@ func TestParseWithWildcardTagKeys(t *testing.T) {
 				),
 			},
 		},
-	}
-	for _, tt := range tests {
+		{
+			name: "prefix matching with flattened keys",
+			parser: &Parser{
+				MetricName: "json_test",
+				TagKeys:    []string{"label_*"},
+			},
+			input: []byte(`{"label_env": "prod", "label": {"team": "core"}, "labels": "none", "value": 1}`),
+			expected: []telegraf.Metric{
+				testutil.MustMetric(
+					"json_test",
+					map[string]string{
+						"label_env":  "prod",
+						"label_team": "core",
+					},
+					map[string]interface{}{
+						"value": float64(1),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			parser := tt.parser
 			require.NoError(t, parser.Init())
@@ -1443,6 +1941,417 @@ func TestBenchmarkData(t *testing.T) {
 	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
 }
 
+func TestParseLineDelimited(t *testing.T) {
+	parser := &Parser{
+		MetricName:    "json_test",
+		TagKeys:       []string{"lvl"},
+		LineDelimited: true,
+		Log:           testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	input := "{\"lvl\": \"info\", \"value\": 1}\n\n{\"lvl\": \"warn\", \"value\": 2}\n"
+	expected := []telegraf.Metric{
+		metric.New(
+			"json_test",
+			map[string]string{"lvl": "info"},
+			map[string]interface{}{"value": float64(1)},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"json_test",
+			map[string]string{"lvl": "warn"},
+			map[string]interface{}{"value": float64(2)},
+			time.Unix(0, 0),
+		),
+	}
+
+	actual, err := parser.Parse([]byte(input))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// A malformed line must not drop the other lines
+	actual, err = parser.Parse([]byte("{\"lvl\": \"info\", \"value\": 1}\n{\"lvl\": \n{\"lvl\": \"warn\", \"value\": 2}"))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	// Fail if no line is valid
+	_, err = parser.Parse([]byte("{\"lvl\": \nnot json"))
+	require.ErrorContains(t, err, "line 1")
+	require.ErrorContains(t, err, "line 2")
+}
+
+func TestParseKeyTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "scalar values",
+			parser: &Parser{KeyTag: "cpu"},
+			input:  `{"cpu0": 12, "cpu1": 34}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{"cpu": "cpu0"},
+					map[string]interface{}{"value": float64(12)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"json_test",
+					map[string]string{"cpu": "cpu1"},
+					map[string]interface{}{"value": float64(34)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "custom field",
+			parser: &Parser{
+				KeyTag:        "cpu",
+				KeyValueField: "usage",
+			},
+			input: `{"cpu0": 12, "cpu1": 34}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{"cpu": "cpu0"},
+					map[string]interface{}{"usage": float64(12)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"json_test",
+					map[string]string{"cpu": "cpu1"},
+					map[string]interface{}{"usage": float64(34)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "object values",
+			parser: &Parser{
+				KeyTag:  "device",
+				TagKeys: []string{"state"},
+			},
+			input: `{"sda": {"reads": 1, "state": "ok"}, "sdb": {"reads": 2, "state": "failed"}}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{"device": "sda", "state": "ok"},
+					map[string]interface{}{"reads": float64(1)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"json_test",
+					map[string]string{"device": "sdb", "state": "failed"},
+					map[string]interface{}{"reads": float64(2)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "json_test"
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseMeasurementKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:  "string value",
+			input: `{"type":"http","duration":5}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"http",
+					map[string]string{},
+					map[string]interface{}{"duration": float64(5)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:  "missing key",
+			input: `{"duration":5}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{},
+					map[string]interface{}{"duration": float64(5)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:  "non-string value",
+			input: `{"type":3,"duration":5}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{"type": "3"},
+					map[string]interface{}{"duration": float64(5)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:     "json_test",
+				MeasurementKey: "type",
+				TagKeys:        []string{"type"},
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseTimeKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		selected string
+		input    string
+		expected time.Time
+	}{
+		{
+			name:     "max",
+			selected: "max",
+			input:    `{"received_at":1577923199,"event_at":1577923201,"value":42}`,
+			expected: time.Unix(1577923201, 0),
+		},
+		{
+			name:     "min",
+			selected: "min",
+			input:    `{"received_at":1577923199,"event_at":1577923201,"value":42}`,
+			expected: time.Unix(1577923199, 0),
+		},
+		{
+			name:     "first",
+			input:    `{"received_at":1577923199,"event_at":1577923201,"value":42}`,
+			expected: time.Unix(1577923201, 0),
+		},
+		{
+			name:     "missing key",
+			selected: "max",
+			input:    `{"received_at":1577923199,"value":42}`,
+			expected: time.Unix(1577923199, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName: "json_test",
+				TimeKeys:   []string{"event_at", "received_at"},
+				TimeSelect: tt.selected,
+				TimeFormat: "unix",
+			}
+			require.NoError(t, parser.Init())
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"json_test",
+					map[string]string{},
+					map[string]interface{}{"value": float64(42)},
+					tt.expected,
+				),
+			}
+
+			actual, err := parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, actual)
+		})
+	}
+}
+
+func TestParseTimeKeysInvalid(t *testing.T) {
+	parser := &Parser{
+		MetricName: "json_test",
+		TimeKeys:   []string{"event_at", "received_at"},
+		TimeSelect: "last",
+		TimeFormat: "unix",
+	}
+	require.ErrorContains(t, parser.Init(), `invalid time selection "last"`)
+
+	parser = &Parser{
+		MetricName: "json_test",
+		TimeKeys:   []string{"event_at", "received_at"},
+		TimeFormat: "unix",
+	}
+	require.NoError(t, parser.Init())
+	_, err := parser.Parse([]byte(`{"value":42}`))
+	require.ErrorContains(t, err, "JSON time key could not be found")
+}
+
+func TestParseDuplicateKeys(t *testing.T) {
+	input := `{"a":1,"b":{"c":2,"c":3},"a":4}`
+
+	tests := []struct {
+		name     string
+		handling string
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			name:     "default",
+			expected: map[string]interface{}{"a": float64(4), "b_c": float64(3)},
+		},
+		{
+			name:     "last",
+			handling: "last",
+			expected: map[string]interface{}{"a": float64(4), "b_c": float64(3)},
+		},
+		{
+			name:     "first",
+			handling: "first",
+			expected: map[string]interface{}{"a": float64(1), "b_c": float64(2)},
+		},
+		{
+			name:     "error",
+			handling: "error",
+			err:      `duplicate key "c"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:    "json_test",
+				DuplicateKeys: tt.handling,
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(input))
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			require.Equal(t, tt.expected, actual[0].Fields())
+
+			// Top-level arrays are decoded element by element
+			actual, err = parser.Parse([]byte("[" + input + "]"))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			require.Equal(t, tt.expected, actual[0].Fields())
+		})
+	}
+
+	parser := &Parser{DuplicateKeys: "merge"}
+	require.ErrorContains(t, parser.Init(), `invalid duplicate key handling "merge"`)
+}
+
+func TestParseMeasurementCase(t *testing.T) {
+	parser := &Parser{
+		MetricName:     "json_test",
+		MeasurementKey: "type",
+		NameCase:       "lower",
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"http",
+			map[string]string{},
+			map[string]interface{}{"duration": float64(5)},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"json_test",
+			map[string]string{},
+			map[string]interface{}{"duration": float64(7)},
+			time.Unix(0, 0),
+		),
+	}
+
+	actual, err := parser.Parse([]byte(`[{"type":"HTTP","duration":5},{"duration":7}]`))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	parser = &Parser{NameCase: "camel"}
+	require.ErrorContains(t, parser.Init(), `invalid measurement case "camel"`)
+}
+
+func TestTimeFormatFraction(t *testing.T) {
+	parser := &Parser{
+		MetricName: "json_test",
+		TimeKey:    "time",
+		TimeFormat: "2006-01-02T15:04:05.000Z07:00",
+	}
+	require.NoError(t, parser.Init())
+
+	for _, ts := range []string{"12:00:00.123Z", "12:00:00.123456Z", "12:00:00.123456789Z"} {
+		t.Run(ts, func(t *testing.T) {
+			actual, err := parser.Parse([]byte(`{"time": "2024-03-01T` + ts + `", "value": 1}`))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+
+			expected, err := time.Parse(time.RFC3339Nano, "2024-03-01T"+ts)
+			require.NoError(t, err)
+			require.True(t, expected.Equal(actual[0].Time()), "expected %v but got %v", expected, actual[0].Time())
+		})
+	}
+}
+
+func TestParseArrayInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "trailing data",
+			input:    `[{"a": 1}] {"a": 2}`,
+			expected: "unexpected data after top-level value",
+		},
+		{
+			name:     "trailing comma",
+			input:    `[{"a": 1},]`,
+			expected: "invalid character",
+		},
+		{
+			name:     "unterminated",
+			input:    `[{"a": 1}, {"a": 2}`,
+			expected: "unexpected end of JSON input",
+		},
+		{
+			name:     "scalar element",
+			input:    `[{"a": 1}, 2]`,
+			expected: ErrWrongType.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{MetricName: "json_test"}
+			require.NoError(t, parser.Init())
+
+			_, err := parser.Parse([]byte(tt.input))
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
 func BenchmarkParsingSequential(b *testing.B) {
 	// Configure the plugin
 	plugin := &Parser{
@@ -1473,6 +2382,51 @@ func BenchmarkParsingParallel(b *testing.B) {
 	})
 }
 
+func BenchmarkParsingLargeArray(b *testing.B) {
+	// Create an array with 10k elements
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i := 0; i < 10000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, `{"name":"device-%d","value":%d,"status":{"ok":true,"load":0.%d}}`, i, i, i)
+	}
+	buf.WriteString("]")
+	data := buf.Bytes()
+
+	// Configure the plugin
+	plugin := &Parser{
+		MetricName: "benchmark",
+		TagKeys:    []string{"name"},
+	}
+	require.NoError(b, plugin.Init())
+
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_, _ = plugin.Parse(data)
+		}
+	})
+
+	// Decode the whole array at once as done before streaming was added
+	b.Run("materialized", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			var items []interface{}
+			if err := json.Unmarshal(data, &items); err != nil {
+				b.Fatal(err)
+			}
+			timestamp := time.Now()
+			metrics := make([]telegraf.Metric, 0)
+			for i, item := range items {
+				m, _ := plugin.parseArrayItem(i, item, timestamp)
+				metrics = append(metrics, m...)
+			}
+		}
+	})
+}
+
 func FuzzParserJSON(f *testing.F) {
 	for _, value := range fuzz.JSONDictionary {
 		f.Add([]byte(value))
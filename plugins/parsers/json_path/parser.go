@@ -0,0 +1,226 @@
+package json_path
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// Entry maps the result of a single query to a field or tag
+type Entry struct {
+	Name     string `toml:"name"`
+	Query    string `toml:"query"`
+	Type     string `toml:"type"`
+	Optional bool   `toml:"optional"`
+}
+
+type Parser struct {
+	MetricNameQuery string  `toml:"json_path_metric_name"`
+	TimestampQuery  string  `toml:"json_path_timestamp"`
+	TimestampFormat string  `toml:"json_path_timestamp_format"`
+	Timezone        string  `toml:"json_path_timezone"`
+	Tags            []Entry `toml:"json_path_tag"`
+	Fields          []Entry `toml:"json_path_field"`
+
+	DefaultMetricName string            `toml:"-"`
+	DefaultTags       map[string]string `toml:"-"`
+	Log               telegraf.Logger   `toml:"-"`
+
+	location *time.Location
+}
+
+func (p *Parser) Init() error {
+	if len(p.Fields) == 0 {
+		return errors.New("no fields configured")
+	}
+
+	if p.TimestampQuery != "" && p.TimestampFormat == "" {
+		return errors.New("'json_path_timestamp_format' is required when 'json_path_timestamp' is set")
+	}
+
+	p.location = time.UTC
+	if p.Timezone != "" {
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		p.location = loc
+	}
+
+	for i, e := range p.Tags {
+		if err := e.check(); err != nil {
+			return fmt.Errorf("tag %d invalid: %w", i+1, err)
+		}
+		switch e.Type {
+		case "", "string":
+		default:
+			return fmt.Errorf("tag %q invalid: type %q not supported for tags", e.Name, e.Type)
+		}
+	}
+
+	for i, e := range p.Fields {
+		if err := e.check(); err != nil {
+			return fmt.Errorf("field %d invalid: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *Entry) check() error {
+	if e.Name == "" {
+		return errors.New("missing name")
+	}
+	if e.Query == "" {
+		return fmt.Errorf("missing query for %q", e.Name)
+	}
+	switch e.Type {
+	case "", "auto", "int", "uint", "float", "bool", "string":
+	default:
+		return fmt.Errorf("unknown type %q for %q", e.Type, e.Name)
+	}
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if !gjson.ValidBytes(buf) {
+		return nil, errors.New("invalid JSON")
+	}
+
+	name := p.DefaultMetricName
+	if p.MetricNameQuery != "" {
+		result := gjson.GetBytes(buf, p.MetricNameQuery)
+		if result.Exists() && result.String() != "" {
+			name = result.String()
+		}
+	}
+
+	timestamp := time.Now()
+	if p.TimestampQuery != "" {
+		result := gjson.GetBytes(buf, p.TimestampQuery)
+		if !result.Exists() {
+			return nil, fmt.Errorf("timestamp query %q returned no result", p.TimestampQuery)
+		}
+		var err error
+		timestamp, err = internal.ParseTimestamp(p.TimestampFormat, result.String(), p.location)
+		if err != nil {
+			return nil, fmt.Errorf("parsing timestamp failed: %w", err)
+		}
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags)+len(p.Tags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	for _, e := range p.Tags {
+		v, err := e.extract(buf)
+		if err != nil {
+			return nil, fmt.Errorf("extracting tag %q failed: %w", e.Name, err)
+		}
+		if v == nil {
+			continue
+		}
+		tag, err := internal.ToString(v)
+		if err != nil {
+			return nil, fmt.Errorf("converting tag %q failed: %w", e.Name, err)
+		}
+		tags[e.Name] = tag
+	}
+
+	fields := make(map[string]interface{}, len(p.Fields))
+	for _, e := range p.Fields {
+		v, err := e.extract(buf)
+		if err != nil {
+			return nil, fmt.Errorf("extracting field %q failed: %w", e.Name, err)
+		}
+		if v == nil {
+			continue
+		}
+		fields[e.Name] = v
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return []telegraf.Metric{metric.New(name, tags, fields, timestamp)}, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, nil
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+// extract runs the query of the entry against the given document and
+// converts the result to the configured type. A nil value is returned
+// for optional entries without a result.
+func (e *Entry) extract(buf []byte) (interface{}, error) {
+	result := gjson.GetBytes(buf, e.Query)
+	if !result.Exists() || result.Type == gjson.Null {
+		if e.Optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query %q returned no result", e.Query)
+	}
+
+	var value interface{}
+	switch result.Type {
+	case gjson.String:
+		value = result.Str
+	case gjson.Number:
+		if v, err := strconv.ParseInt(result.Raw, 10, 64); err == nil {
+			value = v
+		} else {
+			value = result.Num
+		}
+	case gjson.True, gjson.False:
+		value = result.Bool()
+	default:
+		return nil, fmt.Errorf("query %q returned a non-scalar value", e.Query)
+	}
+
+	switch e.Type {
+	case "", "auto":
+		return value, nil
+	case "int":
+		return internal.ToInt64(value)
+	case "uint":
+		return internal.ToUint64(value)
+	case "float":
+		return internal.ToFloat64(value)
+	case "bool":
+		return internal.ToBool(value)
+	case "string":
+		return internal.ToString(value)
+	}
+
+	return nil, fmt.Errorf("unknown type %q", e.Type)
+}
+
+func init() {
+	parsers.Add("json_path",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{DefaultMetricName: defaultMetricName}
+		},
+	)
+}
@@ -0,0 +1,239 @@
+package json_path
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+const testJSON = `
+{
+  "device": {
+    "name": "sensor-1",
+    "location": {"room": "kitchen", "floor": 2},
+    "status": {"online": true, "uptime": "3600"}
+  },
+  "timestamp": 1577923199,
+  "readings": [
+    {"type": "temperature", "value": 21.5},
+    {"type": "humidity", "value": 45}
+  ]
+}
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		expected []telegraf.Metric
+	}{
+		{
+			name: "nested scalar",
+			parser: &Parser{
+				Tags: []Entry{
+					{Name: "room", Query: "device.location.room"},
+				},
+				Fields: []Entry{
+					{Name: "floor", Query: "device.location.floor"},
+					{Name: "online", Query: "device.status.online"},
+				},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_path",
+					map[string]string{"room": "kitchen"},
+					map[string]interface{}{
+						"floor":  int64(2),
+						"online": true,
+					},
+					time.Unix(1577923199, 0),
+				),
+			},
+		},
+		{
+			name: "array element",
+			parser: &Parser{
+				MetricNameQuery: "device.name",
+				Fields: []Entry{
+					{Name: "temperature", Query: "readings.0.value"},
+					{Name: "humidity", Query: `readings.#(type=="humidity").value`},
+				},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"sensor-1",
+					map[string]string{},
+					map[string]interface{}{
+						"temperature": 21.5,
+						"humidity":    int64(45),
+					},
+					time.Unix(1577923199, 0),
+				),
+			},
+		},
+		{
+			name: "type coercion",
+			parser: &Parser{
+				Tags: []Entry{
+					{Name: "floor", Query: "device.location.floor"},
+				},
+				Fields: []Entry{
+					{Name: "uptime", Query: "device.status.uptime", Type: "int"},
+					{Name: "humidity", Query: "readings.1.value", Type: "float"},
+					{Name: "temperature", Query: "readings.0.value", Type: "string"},
+					{Name: "online", Query: "device.status.online", Type: "uint"},
+				},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_path",
+					map[string]string{"floor": "2"},
+					map[string]interface{}{
+						"uptime":      int64(3600),
+						"humidity":    float64(45),
+						"temperature": "21.5",
+						"online":      uint64(1),
+					},
+					time.Unix(1577923199, 0),
+				),
+			},
+		},
+		{
+			name: "optional entries",
+			parser: &Parser{
+				Tags: []Entry{
+					{Name: "building", Query: "device.location.building", Optional: true},
+				},
+				Fields: []Entry{
+					{Name: "temperature", Query: "readings.0.value"},
+					{Name: "pressure", Query: "readings.2.value", Optional: true},
+				},
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"json_path",
+					map[string]string{},
+					map[string]interface{}{
+						"temperature": 21.5,
+					},
+					time.Unix(1577923199, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.DefaultMetricName = "json_path"
+			tt.parser.TimestampQuery = "timestamp"
+			tt.parser.TimestampFormat = "unix"
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse([]byte(testJSON))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		fields   []Entry
+		expected string
+	}{
+		{
+			name:     "invalid json",
+			input:    `{"a": `,
+			fields:   []Entry{{Name: "a", Query: "a"}},
+			expected: "invalid JSON",
+		},
+		{
+			name:     "missing value",
+			input:    testJSON,
+			fields:   []Entry{{Name: "pressure", Query: "readings.2.value"}},
+			expected: `extracting field "pressure" failed: query "readings.2.value" returned no result`,
+		},
+		{
+			name:     "non-scalar value",
+			input:    testJSON,
+			fields:   []Entry{{Name: "location", Query: "device.location"}},
+			expected: `extracting field "location" failed: query "device.location" returned a non-scalar value`,
+		},
+		{
+			name:     "conversion failure",
+			input:    testJSON,
+			fields:   []Entry{{Name: "room", Query: "device.location.room", Type: "int"}},
+			expected: `extracting field "room" failed: strconv.ParseInt: parsing "kitchen": invalid syntax`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				DefaultMetricName: "json_path",
+				Fields:            tt.fields,
+			}
+			require.NoError(t, parser.Init())
+
+			_, err := parser.Parse([]byte(tt.input))
+			require.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestInitInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		expected string
+	}{
+		{
+			name:     "no fields",
+			parser:   &Parser{},
+			expected: "no fields configured",
+		},
+		{
+			name: "missing query",
+			parser: &Parser{
+				Fields: []Entry{{Name: "a"}},
+			},
+			expected: `field 1 invalid: missing query for "a"`,
+		},
+		{
+			name: "unknown type",
+			parser: &Parser{
+				Fields: []Entry{{Name: "a", Query: "a", Type: "complex"}},
+			},
+			expected: `field 1 invalid: unknown type "complex" for "a"`,
+		},
+		{
+			name: "typed tag",
+			parser: &Parser{
+				Tags:   []Entry{{Name: "t", Query: "t", Type: "int"}},
+				Fields: []Entry{{Name: "a", Query: "a"}},
+			},
+			expected: `tag "t" invalid: type "int" not supported for tags`,
+		},
+		{
+			name: "timestamp without format",
+			parser: &Parser{
+				TimestampQuery: "timestamp",
+				Fields:         []Entry{{Name: "a", Query: "a"}},
+			},
+			expected: "'json_path_timestamp_format' is required when 'json_path_timestamp' is set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, tt.parser.Init(), tt.expected)
+		})
+	}
+}
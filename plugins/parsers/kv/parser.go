@@ -0,0 +1,238 @@
+package kv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+var ErrNoMetric = errors.New("no metric in line")
+
+// Parser decodes key-value pairs with configurable separators into metrics.
+type Parser struct {
+	RecordSeparator   string            `toml:"kv_record_separator"`
+	FieldSeparator    string            `toml:"kv_field_separator"`
+	KeyValueSeparator string            `toml:"kv_key_value_separator"`
+	TagKeys           []string          `toml:"kv_tag_keys"`
+	NumericFields     []string          `toml:"kv_numeric_fields"`
+	MeasurementKey    string            `toml:"kv_measurement_key"`
+	NameCase          string            `toml:"measurement_case"`
+	DefaultTags       map[string]string `toml:"-"`
+	Log               telegraf.Logger   `toml:"-"`
+
+	metricName    string
+	tagFilter     filter.Filter
+	numericFilter filter.Filter
+}
+
+// Parse converts each line, or each record of a line if a record separator
+// is set, of the given data to a metric.
+func (p *Parser) Parse(b []byte) ([]telegraf.Metric, error) {
+	metrics := make([]telegraf.Metric, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		records := []string{scanner.Text()}
+		if p.RecordSeparator != "" {
+			var err error
+			if records, err = split(scanner.Text(), p.RecordSeparator); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, record := range records {
+			m, err := p.parseRecord(record)
+			if err != nil {
+				return nil, err
+			}
+			if m != nil {
+				metrics = append(metrics, m)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) parseRecord(line string) (telegraf.Metric, error) {
+	pairs, err := split(line, p.FieldSeparator)
+	if err != nil {
+		return nil, err
+	}
+
+	name := p.metricName
+	fields := make(map[string]interface{})
+	tags := make(map[string]string)
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		// Keys without value are skipped similar to the logfmt parser
+		parts, err := split(pair, p.KeyValueSeparator)
+		if err != nil {
+			return nil, err
+		}
+		if len(parts) < 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(strings.Join(parts[1:], p.KeyValueSeparator))
+		if key == "" || value == "" {
+			continue
+		}
+		value, err = unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of key %q: %w", key, err)
+		}
+
+		if p.MeasurementKey != "" && key == p.MeasurementKey {
+			name = parsers.NormalizeMeasurement(value, p.NameCase)
+		} else if p.tagFilter != nil && p.tagFilter.Match(key) {
+			tags[key] = value
+		} else if p.numericFilter != nil && !p.numericFilter.Match(key) {
+			fields[key] = value
+		} else {
+			fields[key] = p.convert(key, value)
+		}
+	}
+	if len(fields) == 0 && len(tags) == 0 {
+		return nil, nil
+	}
+
+	for k, v := range p.DefaultTags {
+		if _, found := tags[k]; !found {
+			tags[k] = v
+		}
+	}
+
+	return metric.New(name, tags, fields, time.Now()), nil
+}
+
+// split splits the given string at the separator except for separators
+// enclosed in double quotes.
+func split(s, sep string) ([]string, error) {
+	parts := make([]string, 0)
+
+	var quoted, escaped bool
+	var start int
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+
+	return append(parts, s[start:]), nil
+}
+
+// unquote removes the surrounding double quotes of the value and resolves
+// escape sequences within.
+func unquote(value string) (string, error) {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value, nil
+	}
+	return strconv.Unquote(value)
+}
+
+// convert returns the value as integer, float or boolean if possible and as
+// string otherwise.
+func (p *Parser) convert(key, value string) interface{} {
+	if iValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return iValue
+	}
+	if fValue, err := strconv.ParseFloat(value, 64); err == nil {
+		return fValue
+	}
+	if bValue, err := strconv.ParseBool(value); err == nil {
+		return bValue
+	}
+	if p.numericFilter != nil && p.Log != nil {
+		p.Log.Debugf("Cannot convert value %q of key %q, keeping string", value, key)
+	}
+	return value
+}
+
+// ParseLine converts a single line of key-value pairs to a metric.
+func (p *Parser) ParseLine(s string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, ErrNoMetric
+	}
+	return metrics[0], nil
+}
+
+// SetDefaultTags adds tags to the metrics outputs of Parse and ParseLine.
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) Init() error {
+	if p.FieldSeparator == "" {
+		p.FieldSeparator = " "
+	}
+	if p.KeyValueSeparator == "" {
+		p.KeyValueSeparator = "="
+	}
+	if p.FieldSeparator == p.KeyValueSeparator {
+		return errors.New("field and key-value separator must differ")
+	}
+	if p.RecordSeparator == p.FieldSeparator || p.RecordSeparator == p.KeyValueSeparator {
+		return errors.New("record separator must differ from field and key-value separator")
+	}
+
+	if err := parsers.CheckMeasurementCase(p.NameCase); err != nil {
+		return err
+	}
+
+	var err error
+
+	// Compile tag key patterns
+	if p.tagFilter, err = filter.Compile(p.TagKeys); err != nil {
+		return fmt.Errorf("error compiling tag pattern: %w", err)
+	}
+
+	// Compile numeric field patterns
+	if p.numericFilter, err = filter.Compile(p.NumericFields); err != nil {
+		return fmt.Errorf("error compiling numeric-fields pattern: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	// Register parser
+	parsers.Add("kv",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{metricName: defaultMetricName}
+		},
+	)
+}
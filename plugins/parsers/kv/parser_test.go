@@ -0,0 +1,184 @@
+package kv
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "default separators",
+			parser: &Parser{},
+			input:  `method=GET status=200 ratio=0.5 cached=true msg="took 4ms"`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"kv",
+					map[string]string{},
+					map[string]interface{}{
+						"method": "GET",
+						"status": int64(200),
+						"ratio":  0.5,
+						"cached": true,
+						"msg":    "took 4ms",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "custom separators",
+			parser: &Parser{
+				FieldSeparator:    ";",
+				KeyValueSeparator: ":",
+				TagKeys:           []string{"host"},
+			},
+			input: "host:example.org; status:200; url:http://example.org/a\nhost:example.com;status:404",
+			expected: []telegraf.Metric{
+				metric.New(
+					"kv",
+					map[string]string{"host": "example.org"},
+					map[string]interface{}{
+						"status": int64(200),
+						"url":    "http://example.org/a",
+					},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"kv",
+					map[string]string{"host": "example.com"},
+					map[string]interface{}{
+						"status": int64(404),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "quoted values",
+			parser: &Parser{
+				FieldSeparator:    ";",
+				KeyValueSeparator: ":",
+				NumericFields:     []string{"status"},
+				MeasurementKey:    "event",
+			},
+			input: `event:request; status:200; code:"404"; msg:"took 4ms; \"cached\""; empty:`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"request",
+					map[string]string{},
+					map[string]interface{}{
+						"status": int64(200),
+						"code":   "404",
+						"msg":    `took 4ms; "cached"`,
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "record separator",
+			parser: &Parser{
+				RecordSeparator: "|",
+				TagKeys:         []string{"host"},
+			},
+			input: `host=a cpu=1||host=b cpu=2 msg="a|b"|`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"kv",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"cpu": int64(1)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"kv",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"cpu": int64(2), "msg": "a|b"},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "measurement case",
+			parser: &Parser{
+				MeasurementKey: "event",
+				NameCase:       "lower",
+			},
+			input: "event=HTTP status=200",
+			expected: []telegraf.Metric{
+				metric.New(
+					"http",
+					map[string]string{},
+					map[string]interface{}{"status": int64(200)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:     "no pairs",
+			parser:   &Parser{},
+			input:    "\njust text\n",
+			expected: []telegraf.Metric{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.metricName = "kv"
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	parser := &Parser{metricName: "kv"}
+	parser.SetDefaultTags(map[string]string{"source": "test"})
+	require.NoError(t, parser.Init())
+
+	m, err := parser.ParseLine("a=1")
+	require.NoError(t, err)
+	expected := metric.New(
+		"kv",
+		map[string]string{"source": "test"},
+		map[string]interface{}{"a": int64(1)},
+		time.Unix(0, 0),
+	)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{expected}, []telegraf.Metric{m}, testutil.IgnoreTime())
+
+	_, err = parser.ParseLine("")
+	require.ErrorIs(t, err, ErrNoMetric)
+}
+
+func TestParseErrors(t *testing.T) {
+	parser := &Parser{metricName: "kv"}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse([]byte(`msg="unterminated value`))
+	require.ErrorContains(t, err, "unterminated quote")
+
+	_, err = parser.Parse([]byte(`msg="invalid \q escape"`))
+	require.ErrorContains(t, err, `invalid value of key "msg"`)
+}
+
+func TestInitErrors(t *testing.T) {
+	parser := &Parser{FieldSeparator: ":", KeyValueSeparator: ":"}
+	require.ErrorContains(t, parser.Init(), "field and key-value separator must differ")
+
+	parser = &Parser{RecordSeparator: "="}
+	require.ErrorContains(t, parser.Init(), "record separator must differ from field and key-value separator")
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logfmt/logfmt"
@@ -19,19 +20,39 @@ var ErrNoMetric = errors.New("no metric in line")
 
 // Parser decodes logfmt formatted messages into metrics.
 type Parser struct {
-	TagKeys     []string          `toml:"logfmt_tag_keys"`
-	DefaultTags map[string]string `toml:"-"`
+	TagKeys         []string          `toml:"logfmt_tag_keys"`
+	NumericFields   []string          `toml:"logfmt_numeric_fields"`
+	MeasurementKey  string            `toml:"logfmt_measurement_key"`
+	TimestampFormat string            `toml:"logfmt_timestamp_format"`
+	Timezone        string            `toml:"logfmt_timezone"`
+	BracketContext  bool              `toml:"logfmt_bracket_context"`
+	NameCase        string            `toml:"measurement_case"`
+	DefaultTags     map[string]string `toml:"-"`
+	Log             telegraf.Logger   `toml:"-"`
 
-	metricName string
-	tagFilter  filter.Filter
+	metricName      string
+	tagFilter       filter.Filter
+	numericFilter   filter.Filter
+	location        *time.Location
+	timestampFields int
+	timeFunc        func() time.Time
 }
 
 // Parse converts a slice of bytes in logfmt format to metrics.
 func (p *Parser) Parse(b []byte) ([]telegraf.Metric, error) {
+	var timestamps []time.Time
+	if p.TimestampFormat != "" || p.BracketContext {
+		var err error
+		b, timestamps, err = p.preprocess(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	reader := bytes.NewReader(b)
 	decoder := logfmt.NewDecoder(reader)
 	metrics := make([]telegraf.Metric, 0)
-	for {
+	for line := 0; ; line++ {
 		ok := decoder.ScanRecord()
 		if !ok {
 			err := decoder.Err()
@@ -40,6 +61,7 @@ func (p *Parser) Parse(b []byte) ([]telegraf.Metric, error) {
 			}
 			break
 		}
+		name := p.metricName
 		fields := make(map[string]interface{})
 		tags := make(map[string]string)
 		for decoder.ScanKeyval() {
@@ -47,25 +69,27 @@ func (p *Parser) Parse(b []byte) ([]telegraf.Metric, error) {
 				continue
 			}
 
-			//type conversions
+			key := string(decoder.Key())
 			value := string(decoder.Value())
-			if p.tagFilter != nil && p.tagFilter.Match(string(decoder.Key())) {
-				tags[string(decoder.Key())] = value
-			} else if iValue, err := strconv.ParseInt(value, 10, 64); err == nil {
-				fields[string(decoder.Key())] = iValue
-			} else if fValue, err := strconv.ParseFloat(value, 64); err == nil {
-				fields[string(decoder.Key())] = fValue
-			} else if bValue, err := strconv.ParseBool(value); err == nil {
-				fields[string(decoder.Key())] = bValue
+			if p.MeasurementKey != "" && key == p.MeasurementKey {
+				name = parsers.NormalizeMeasurement(value, p.NameCase)
+			} else if p.tagFilter != nil && p.tagFilter.Match(key) {
+				tags[key] = value
+			} else if p.numericFilter != nil && !p.numericFilter.Match(key) {
+				fields[key] = value
 			} else {
-				fields[string(decoder.Key())] = value
+				fields[key] = p.convert(key, value)
 			}
 		}
 		if len(fields) == 0 && len(tags) == 0 {
 			continue
 		}
 
-		m := metric.New(p.metricName, tags, fields, time.Now())
+		timestamp := p.now()
+		if line < len(timestamps) && !timestamps[line].IsZero() {
+			timestamp = timestamps[line]
+		}
+		m := metric.New(name, tags, fields, timestamp)
 
 		metrics = append(metrics, m)
 	}
@@ -73,6 +97,102 @@ func (p *Parser) Parse(b []byte) ([]telegraf.Metric, error) {
 	return metrics, nil
 }
 
+// preprocess removes the leading timestamp from each line and unwraps the
+// key-value pairs of bracketed context such as "[traceId=abc, spanId=def]"
+// depending on the settings. It returns the resulting lines along with the
+// timestamp of each line, empty lines have a zero timestamp.
+func (p *Parser) preprocess(b []byte) ([]byte, []time.Time, error) {
+	lines := strings.Split(string(b), "\n")
+	timestamps := make([]time.Time, len(lines))
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if p.TimestampFormat != "" {
+			prefix, remainder := splitWords(line, p.timestampFields)
+			ts, err := parsers.ParseTimestamp(p.TimestampFormat, prefix, p.location)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing timestamp of line %d failed: %w", i+1, err)
+			}
+			timestamps[i] = ts
+			line = remainder
+		}
+
+		if p.BracketContext {
+			line = unwrapBrackets(line)
+		}
+		lines[i] = line
+	}
+	return []byte(strings.Join(lines, "\n")), timestamps, nil
+}
+
+// splitWords splits the line after the given number of space separated words
+// and returns the words and the remainder of the line.
+func splitWords(line string, n int) (words, remainder string) {
+	line = strings.TrimLeft(line, " \t")
+	end := 0
+	for i := 0; i < n; i++ {
+		// Skip the separating whitespace before the next word
+		for end < len(line) && (line[end] == ' ' || line[end] == '\t') {
+			end++
+		}
+		next := strings.IndexAny(line[end:], " \t")
+		if next < 0 {
+			return line, ""
+		}
+		end += next
+	}
+	return line[:end], line[end:]
+}
+
+// unwrapBrackets removes the brackets of context outside of quoted values and
+// replaces the commas separating the key-value pairs in the context by spaces.
+func unwrapBrackets(line string) string {
+	var quoted, escaped bool
+	var depth int
+	buf := []byte(line)
+	for i, c := range buf {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[':
+			depth++
+			buf[i] = ' '
+		case c == ']' && depth > 0:
+			depth--
+			buf[i] = ' '
+		case c == ',' && depth > 0:
+			buf[i] = ' '
+		}
+	}
+	return string(buf)
+}
+
+// convert returns the value as integer, float or boolean if possible and as
+// string otherwise.
+func (p *Parser) convert(key, value string) interface{} {
+	if iValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return iValue
+	}
+	if fValue, err := strconv.ParseFloat(value, 64); err == nil {
+		return fValue
+	}
+	if bValue, err := strconv.ParseBool(value); err == nil {
+		return bValue
+	}
+	if p.numericFilter != nil && p.Log != nil {
+		p.Log.Debugf("Cannot convert value %q of key %q, keeping string", value, key)
+	}
+	return value
+}
+
 // ParseLine converts a single line of text in logfmt format to metrics.
 func (p *Parser) ParseLine(s string) (telegraf.Metric, error) {
 	metrics, err := p.Parse([]byte(s))
@@ -86,6 +206,19 @@ func (p *Parser) ParseLine(s string) (telegraf.Metric, error) {
 	return metrics[0], nil
 }
 
+// SetTimeFunc sets the function used to determine the time of metrics
+// without a timestamp.
+func (p *Parser) SetTimeFunc(fn func() time.Time) {
+	p.timeFunc = fn
+}
+
+func (p *Parser) now() time.Time {
+	if p.timeFunc == nil {
+		return time.Now()
+	}
+	return p.timeFunc()
+}
+
 // SetDefaultTags adds tags to the metrics outputs of Parse and ParseLine.
 func (p *Parser) SetDefaultTags(tags map[string]string) {
 	p.DefaultTags = tags
@@ -113,6 +246,26 @@ func (p *Parser) Init() error {
 		return fmt.Errorf("error compiling tag pattern: %w", err)
 	}
 
+	// Compile numeric field patterns
+	if p.numericFilter, err = filter.Compile(p.NumericFields); err != nil {
+		return fmt.Errorf("error compiling numeric-fields pattern: %w", err)
+	}
+
+	if err := parsers.CheckMeasurementCase(p.NameCase); err != nil {
+		return err
+	}
+
+	// The timestamp prefix spans as many words as the format, e.g. two for
+	// "2006-01-02 15:04:05"
+	p.timestampFields = len(strings.Fields(p.TimestampFormat))
+
+	p.location = time.UTC
+	if p.Timezone != "" {
+		if p.location, err = time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+
 	return nil
 }
 
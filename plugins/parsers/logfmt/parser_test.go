@@ -284,10 +284,242 @@ func TestTags(t *testing.T) {
 	}
 }
 
+func TestNumericFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		numericFields []string
+		s             string
+		want          telegraf.Metric
+	}{
+		{
+			name: "all keys converted by default",
+			s:    "count=7 ratio=0.5 ok=true msg=done",
+			want: testutil.MustMetric(
+				"testlog",
+				map[string]string{},
+				map[string]interface{}{
+					"count": int64(7),
+					"ratio": float64(0.5),
+					"ok":    true,
+					"msg":   "done",
+				},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:          "only listed keys converted",
+			numericFields: []string{"count", "ratio"},
+			s:             "count=7 ratio=0.5 ok=true version=1.10",
+			want: testutil.MustMetric(
+				"testlog",
+				map[string]string{},
+				map[string]interface{}{
+					"count":   int64(7),
+					"ratio":   float64(0.5),
+					"ok":      "true",
+					"version": "1.10",
+				},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:          "glob keys converted",
+			numericFields: []string{"*"},
+			s:             "count=7 ratio=0.5 ok=true",
+			want: testutil.MustMetric(
+				"testlog",
+				map[string]string{},
+				map[string]interface{}{
+					"count": int64(7),
+					"ratio": float64(0.5),
+					"ok":    true,
+				},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:          "unparsable listed key kept as string",
+			numericFields: []string{"count", "ok"},
+			s:             "count=seven ok=true",
+			want: testutil.MustMetric(
+				"testlog",
+				map[string]string{},
+				map[string]interface{}{
+					"count": "seven",
+					"ok":    true,
+				},
+				time.Unix(0, 0),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Parser{
+				metricName:    "testlog",
+				NumericFields: tt.numericFields,
+				Log:           testutil.Logger{},
+			}
+			require.NoError(t, l.Init())
+
+			got, err := l.ParseLine(tt.s)
+			require.NoError(t, err)
+			testutil.RequireMetricEqual(t, tt.want, got, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestMeasurementKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		nameCase string
+		want     telegraf.Metric
+	}{
+		{
+			name: "key present",
+			s:    "event=login user=alice lvl=info",
+			want: testutil.MustMetric(
+				"login",
+				map[string]string{"lvl": "info"},
+				map[string]interface{}{"user": "alice"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "key missing",
+			s:    "user=alice lvl=info",
+			want: testutil.MustMetric(
+				"testlog",
+				map[string]string{"lvl": "info"},
+				map[string]interface{}{"user": "alice"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name: "key empty",
+			s:    "event= user=alice lvl=info",
+			want: testutil.MustMetric(
+				"testlog",
+				map[string]string{"lvl": "info"},
+				map[string]interface{}{"user": "alice"},
+				time.Unix(0, 0),
+			),
+		},
+		{
+			name:     "snake case",
+			s:        "event=UserLogin user=alice lvl=info",
+			nameCase: "snake",
+			want: testutil.MustMetric(
+				"user_login",
+				map[string]string{"lvl": "info"},
+				map[string]interface{}{"user": "alice"},
+				time.Unix(0, 0),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Parser{
+				metricName:     "testlog",
+				TagKeys:        []string{"lvl"},
+				MeasurementKey: "event",
+				NameCase:       tt.nameCase,
+			}
+			require.NoError(t, l.Init())
+
+			got, err := l.ParseLine(tt.s)
+			require.NoError(t, err)
+			testutil.RequireMetricEqual(t, tt.want, got, testutil.IgnoreTime())
+		})
+	}
+}
+
 const benchmarkData = `tags_host=myhost tags_platform=python tags_sdkver=3.11.5 value=5
 tags_host=myhost tags_platform=python tags_sdkver=3.11.4 value=4
 `
 
+func TestTimestampAndContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "date prefix",
+			format: "2006-01-02",
+			input:  `2024-01-01 level=INFO [traceId=abc] msg="done"`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"testlog",
+					map[string]string{},
+					map[string]interface{}{
+						"level":   "INFO",
+						"traceId": "abc",
+						"msg":     "done",
+					},
+					time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				),
+			},
+		},
+		{
+			name:   "multiple words and lines",
+			format: "2006-01-02 15:04:05.000",
+			input: `2024-01-01 12:00:00.5 [main] level=INFO [traceId=abc, spanId=d1] msg="[x=1] done"
+
+2024-01-01 12:00:01.250 level=WARN count=3`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"testlog",
+					map[string]string{},
+					map[string]interface{}{
+						"level":   "INFO",
+						"traceId": "abc",
+						"spanId":  "d1",
+						"msg":     "[x=1] done",
+					},
+					time.Date(2024, 1, 1, 12, 0, 0, 500000000, time.UTC),
+				),
+				metric.New(
+					"testlog",
+					map[string]string{},
+					map[string]interface{}{
+						"level": "WARN",
+						"count": int64(3),
+					},
+					time.Date(2024, 1, 1, 12, 0, 1, 250000000, time.UTC),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Parser{
+				TimestampFormat: tt.format,
+				BracketContext:  true,
+				metricName:      "testlog",
+			}
+			require.NoError(t, plugin.Init())
+
+			actual, err := plugin.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestTimestampInvalid(t *testing.T) {
+	plugin := &Parser{
+		TimestampFormat: "2006-01-02",
+		metricName:      "testlog",
+	}
+	require.NoError(t, plugin.Init())
+
+	_, err := plugin.Parse([]byte("level=INFO msg=done"))
+	require.ErrorContains(t, err, "parsing timestamp of line 1 failed")
+}
+
 func TestBenchmarkData(t *testing.T) {
 	plugin := &Parser{
 		TagKeys: []string{"tags_host", "tags_platform", "tags_sdkver"},
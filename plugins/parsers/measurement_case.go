@@ -0,0 +1,62 @@
+package parsers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CheckMeasurementCase returns an error if the given case is not supported
+// by NormalizeMeasurement.
+func CheckMeasurementCase(mode string) error {
+	switch mode {
+	case "", "none", "lower", "upper", "snake":
+		return nil
+	}
+	return fmt.Errorf("invalid measurement case %q", mode)
+}
+
+// NormalizeMeasurement converts the case of a measurement name derived from
+// the parsed data. Supported modes are "lower", "upper" and "snake", e.g.
+// "HTTPRequest" becomes "http_request". An empty mode or "none" keeps the
+// name as is.
+func NormalizeMeasurement(name, mode string) string {
+	switch mode {
+	case "lower":
+		return strings.ToLower(name)
+	case "upper":
+		return strings.ToUpper(name)
+	case "snake":
+		return snakeCase(name)
+	}
+	return name
+}
+
+// snakeCase converts the name to lower case words separated by underscores.
+// Words are delimited by spaces, dashes, dots and underscores as well as by
+// changes from lower to upper case and at the end of acronyms.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	b.Grow(len(name) + 4)
+	separate := false
+	for i, r := range runes {
+		if r == ' ' || r == '-' || r == '.' || r == '_' {
+			separate = b.Len() > 0
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				separate = true
+			}
+		}
+		if separate && b.Len() > 0 {
+			b.WriteRune('_')
+		}
+		separate = false
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
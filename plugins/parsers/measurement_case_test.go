@@ -0,0 +1,64 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMeasurement(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		expected string
+	}{
+		{
+			name:     "HTTP",
+			mode:     "lower",
+			expected: "http",
+		},
+		{
+			name:     "http",
+			mode:     "upper",
+			expected: "HTTP",
+		},
+		{
+			name:     "HTTP",
+			mode:     "none",
+			expected: "HTTP",
+		},
+		{
+			name:     "HTTP",
+			expected: "HTTP",
+		},
+		{
+			name:     "HTTPRequest",
+			mode:     "snake",
+			expected: "http_request",
+		},
+		{
+			name:     "diskIO2Stats",
+			mode:     "snake",
+			expected: "disk_io2_stats",
+		},
+		{
+			name:     "Request Count-total.v2",
+			mode:     "snake",
+			expected: "request_count_total_v2",
+		},
+		{
+			name:     "already_snake",
+			mode:     "snake",
+			expected: "already_snake",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.name, func(t *testing.T) {
+			require.NoError(t, CheckMeasurementCase(tt.mode))
+			require.Equal(t, tt.expected, NormalizeMeasurement(tt.name, tt.mode))
+		})
+	}
+
+	require.EqualError(t, CheckMeasurementCase("camel"), `invalid measurement case "camel"`)
+}
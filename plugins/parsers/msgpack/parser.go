@@ -0,0 +1,297 @@
+package msgpack
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tinylib/msgp/msgp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// ErrWrongType is returned if the document is neither a map nor an array of maps
+var ErrWrongType = errors.New("must be a map or an array of maps")
+
+// Extension type of the official timestamp extension, see
+// https://github.com/msgpack/msgpack/blob/master/spec.md#timestamp-extension-type
+const timestampExtension = -1
+
+type Parser struct {
+	MetricName    string   `toml:"metric_name"`
+	TagKeys       []string `toml:"tag_keys"`
+	NameKey       string   `toml:"msgpack_name_key"`
+	StringFields  []string `toml:"msgpack_string_fields"`
+	TimeKey       string   `toml:"msgpack_time_key"`
+	TimeFormat    string   `toml:"msgpack_time_format"`
+	Timezone      string   `toml:"msgpack_timezone"`
+	Strict        bool     `toml:"msgpack_strict"`
+	ArrayIndexTag string   `toml:"msgpack_array_index_tag"`
+
+	DefaultTags map[string]string `toml:"-"`
+	Log         telegraf.Logger   `toml:"-"`
+
+	location     *time.Location
+	tagFilter    filter.Filter
+	stringFilter filter.Filter
+}
+
+func (p *Parser) Init() error {
+	var err error
+
+	p.stringFilter, err = filter.Compile(p.StringFields)
+	if err != nil {
+		return fmt.Errorf("compiling string-fields filter failed: %w", err)
+	}
+
+	p.tagFilter, err = filter.Compile(p.TagKeys)
+	if err != nil {
+		return fmt.Errorf("compiling tag-key filter failed: %w", err)
+	}
+
+	p.location = time.UTC
+	if p.Timezone != "" {
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		p.location = loc
+	}
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	timestamp := time.Now().UTC()
+
+	// The buffer might contain multiple concatenated messages, e.g. as
+	// produced by the msgpack serializer in batch mode
+	metrics := make([]telegraf.Metric, 0)
+	for len(buf) > 0 {
+		data, remainder, err := msgp.ReadIntfBytes(buf)
+		if err != nil {
+			return nil, fmt.Errorf("decoding message failed: %w", err)
+		}
+		buf = remainder
+
+		switch v := data.(type) {
+		case map[string]interface{}:
+			m, err := p.parseObject(v, timestamp)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m)
+		case []interface{}:
+			m, err := p.parseArray(v, timestamp)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m...)
+		case nil:
+		default:
+			return nil, ErrWrongType
+		}
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("can not parse the line: %s, for data format: msgpack ", line)
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) parseArray(data []interface{}, timestamp time.Time) ([]telegraf.Metric, error) {
+	results := make([]telegraf.Metric, 0, len(data))
+
+	for i, item := range data {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, ErrWrongType
+		}
+
+		m, err := p.parseObject(obj, timestamp)
+		if err != nil {
+			if p.Strict {
+				return nil, err
+			}
+			p.Log.Debugf("Skipping element %d: %v", i, err)
+			continue
+		}
+		if p.ArrayIndexTag != "" {
+			m.AddTag(p.ArrayIndexTag, strconv.Itoa(i))
+		}
+		results = append(results, m)
+	}
+
+	return results, nil
+}
+
+func (p *Parser) parseObject(data map[string]interface{}, timestamp time.Time) (telegraf.Metric, error) {
+	fields := make(map[string]interface{})
+	if err := flatten("", data, fields); err != nil {
+		return nil, err
+	}
+
+	name := p.MetricName
+	if p.NameKey != "" {
+		if v, ok := fields[p.NameKey].(string); ok {
+			name = v
+		}
+	}
+
+	if p.TimeKey != "" {
+		ts, found := fields[p.TimeKey]
+		if !found {
+			return nil, errors.New("time key could not be found")
+		}
+
+		if t, ok := ts.(time.Time); ok {
+			timestamp = t
+		} else {
+			if p.TimeFormat == "" {
+				return nil, errors.New("use of 'msgpack_time_key' requires 'msgpack_time_format'")
+			}
+
+			var err error
+			timestamp, err = internal.ParseTimestamp(p.TimeFormat, ts, p.location)
+			if err != nil {
+				return nil, err
+			}
+		}
+		delete(fields, p.TimeKey)
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+
+	for k, v := range fields {
+		if p.tagFilter != nil && p.tagFilter.Match(k) {
+			tag, err := internal.ToString(v)
+			if err != nil {
+				return nil, fmt.Errorf("converting tag %q failed: %w", k, err)
+			}
+			tags[k] = tag
+			delete(fields, k)
+			continue
+		}
+
+		// Only keep strings and booleans if explicitly requested similar to
+		// the JSON parser
+		switch t := v.(type) {
+		case string, bool:
+			if p.stringFilter == nil || !p.stringFilter.Match(k) {
+				delete(fields, k)
+			}
+		case time.Time:
+			fields[k] = t.UnixNano()
+		}
+	}
+
+	return metric.New(name, tags, fields, timestamp), nil
+}
+
+// flatten adds all values of nested maps and arrays to the given fields
+// using underscores to join the keys.
+func flatten(prefix string, v interface{}, fields map[string]interface{}) error {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, item := range t {
+			key := k
+			if prefix != "" {
+				key = prefix + "_" + k
+			}
+			if err := flatten(key, item, fields); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range t {
+			key := strconv.Itoa(i)
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+			if err := flatten(key, item, fields); err != nil {
+				return err
+			}
+		}
+	case int64, uint64, float64, string, bool, time.Time:
+		fields[prefix] = t
+	case float32:
+		fields[prefix] = float64(t)
+	case []byte:
+		// Binary data is often used for plain strings, so only encode
+		// values that cannot be represented as string
+		if utf8.Valid(t) {
+			fields[prefix] = string(t)
+		} else {
+			fields[prefix] = base64.StdEncoding.EncodeToString(t)
+		}
+	case msgp.Extension:
+		if t.ExtensionType() != timestampExtension {
+			return fmt.Errorf("unsupported extension type %d for %q", t.ExtensionType(), prefix)
+		}
+		raw := make([]byte, t.Len())
+		if err := t.MarshalBinaryTo(raw); err != nil {
+			return fmt.Errorf("reading timestamp %q failed: %w", prefix, err)
+		}
+		ts, err := decodeTimestamp(raw)
+		if err != nil {
+			return fmt.Errorf("decoding timestamp %q failed: %w", prefix, err)
+		}
+		fields[prefix] = ts
+	case nil:
+	default:
+		return fmt.Errorf("unexpected type %T for %q", v, prefix)
+	}
+	return nil
+}
+
+// decodeTimestamp converts the payload of the official timestamp extension
+// in its 32, 64 or 96 bit representation to time
+func decodeTimestamp(data []byte) (time.Time, error) {
+	switch len(data) {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(v&0x03_ffff_ffff), int64(v>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		sec := binary.BigEndian.Uint64(data[4:])
+		return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid length %d", len(data))
+}
+
+func init() {
+	parsers.Add("msgpack",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{
+				MetricName: defaultMetricName,
+				Strict:     true,
+			}
+		})
+}
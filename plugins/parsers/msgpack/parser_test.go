@@ -0,0 +1,319 @@
+package msgpack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	serializer "github.com/influxdata/telegraf/plugins/serializers/msgpack"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParseGolden(t *testing.T) {
+	// {"host": "a", "value": 42, "temp": 21.5, "ok": true}
+	input := []byte{
+		0x84,
+		0xa4, 'h', 'o', 's', 't', 0xa1, 'a',
+		0xa5, 'v', 'a', 'l', 'u', 'e', 0x2a,
+		0xa4, 't', 'e', 'm', 'p', 0xcb, 0x40, 0x35, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xa2, 'o', 'k', 0xc3,
+	}
+
+	parser := &Parser{
+		MetricName: "msgpack",
+		TagKeys:    []string{"host"},
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"msgpack",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"value": int64(42),
+				"temp":  21.5,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	actual, err := parser.Parse(input)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    func() []byte
+		expected []telegraf.Metric
+	}{
+		{
+			name: "nested map",
+			parser: &Parser{
+				TagKeys:      []string{"device_name"},
+				StringFields: []string{"state"},
+			},
+			input: func() []byte {
+				buf := msgp.AppendMapHeader(nil, 3)
+				buf = msgp.AppendString(buf, "device")
+				buf = msgp.AppendMapHeader(buf, 2)
+				buf = msgp.AppendString(buf, "name")
+				buf = msgp.AppendString(buf, "sensor-1")
+				buf = msgp.AppendString(buf, "battery")
+				buf = msgp.AppendUint8(buf, 80)
+				buf = msgp.AppendString(buf, "samples")
+				buf = msgp.AppendArrayHeader(buf, 2)
+				buf = msgp.AppendInt64(buf, -1)
+				buf = msgp.AppendFloat32(buf, 1.5)
+				buf = msgp.AppendString(buf, "state")
+				buf = msgp.AppendString(buf, "ok")
+				return buf
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"msgpack",
+					map[string]string{"device_name": "sensor-1"},
+					map[string]interface{}{
+						"device_battery": int64(80),
+						"samples_0":      int64(-1),
+						"samples_1":      1.5,
+						"state":          "ok",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "array of maps",
+			parser: &Parser{
+				TagKeys:       []string{"name"},
+				ArrayIndexTag: "index",
+			},
+			input: func() []byte {
+				buf := msgp.AppendArrayHeader(nil, 2)
+				for i, name := range []string{"a", "b"} {
+					buf = msgp.AppendMapHeader(buf, 2)
+					buf = msgp.AppendString(buf, "name")
+					buf = msgp.AppendString(buf, name)
+					buf = msgp.AppendString(buf, "value")
+					buf = msgp.AppendInt(buf, i)
+				}
+				return buf
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"msgpack",
+					map[string]string{"name": "a", "index": "0"},
+					map[string]interface{}{"value": int64(0)},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"msgpack",
+					map[string]string{"name": "b", "index": "1"},
+					map[string]interface{}{"value": int64(1)},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "binary values",
+			parser: &Parser{
+				StringFields: []string{"*"},
+			},
+			input: func() []byte {
+				buf := msgp.AppendMapHeader(nil, 2)
+				buf = msgp.AppendString(buf, "text")
+				buf = msgp.AppendBytes(buf, []byte("hello"))
+				buf = msgp.AppendString(buf, "raw")
+				buf = msgp.AppendBytes(buf, []byte{0xff, 0x00, 0x01})
+				return buf
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"msgpack",
+					map[string]string{},
+					map[string]interface{}{
+						"text": "hello",
+						"raw":  "/wAB",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "time key with format",
+			parser: &Parser{
+				TimeKey:    "ts",
+				TimeFormat: "unix_ms",
+			},
+			input: func() []byte {
+				buf := msgp.AppendMapHeader(nil, 2)
+				buf = msgp.AppendString(buf, "ts")
+				buf = msgp.AppendInt64(buf, 1577923199500)
+				buf = msgp.AppendString(buf, "value")
+				buf = msgp.AppendFloat64(buf, 42.5)
+				return buf
+			},
+			expected: []telegraf.Metric{
+				metric.New(
+					"msgpack",
+					map[string]string{},
+					map[string]interface{}{"value": 42.5},
+					time.Unix(1577923199, 500000000),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "msgpack"
+			tt.parser.Log = testutil.Logger{}
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse(tt.input())
+			require.NoError(t, err)
+
+			if tt.parser.TimeKey == "" {
+				testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime(), testutil.SortMetrics())
+			} else {
+				testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.SortMetrics())
+			}
+		})
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	metrics := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"usage":  42.5,
+				"count":  int64(3),
+				"status": "ok",
+			},
+			time.Unix(1577923199, 0),
+		),
+		metric.New(
+			"mem",
+			map[string]string{"host": "b"},
+			map[string]interface{}{
+				"free": uint64(1024),
+			},
+			time.Unix(1577923199, 123456789),
+		),
+	}
+
+	s := &serializer.Serializer{}
+	buf, err := s.SerializeBatch(metrics)
+	require.NoError(t, err)
+
+	parser := &Parser{
+		NameKey:      "name",
+		TimeKey:      "time",
+		TagKeys:      []string{"tags_*"},
+		StringFields: []string{"fields_*"},
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"cpu",
+			map[string]string{"tags_host": "a"},
+			map[string]interface{}{
+				"fields_usage":  42.5,
+				"fields_count":  int64(3),
+				"fields_status": "ok",
+			},
+			time.Unix(1577923199, 0),
+		),
+		metric.New(
+			"mem",
+			map[string]string{"tags_host": "b"},
+			map[string]interface{}{
+				"fields_free": uint64(1024),
+			},
+			time.Unix(1577923199, 123456789),
+		),
+	}
+
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestDecodeTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected time.Time
+	}{
+		{
+			name:     "32 bit",
+			data:     []byte{0x5e, 0x0d, 0x32, 0x7f},
+			expected: time.Unix(1577923199, 0),
+		},
+		{
+			name:     "64 bit",
+			data:     []byte{0x77, 0x35, 0x94, 0x00, 0x5e, 0x0d, 0x32, 0x7f},
+			expected: time.Unix(1577923199, 500000000),
+		},
+		{
+			name:     "96 bit",
+			data:     []byte{0x00, 0x00, 0x00, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			expected: time.Unix(-1, 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := decodeTimestamp(tt.data)
+			require.NoError(t, err)
+			require.True(t, tt.expected.Equal(actual), "expected %v but got %v", tt.expected, actual)
+		})
+	}
+
+	_, err := decodeTimestamp([]byte{0x00})
+	require.EqualError(t, err, "invalid length 1")
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{
+			name:     "truncated",
+			input:    []byte{0x81, 0xa4, 'h', 'o'},
+			expected: "decoding message failed",
+		},
+		{
+			name:     "scalar document",
+			input:    []byte{0x2a},
+			expected: "must be a map or an array of maps",
+		},
+		{
+			name:     "array of scalars",
+			input:    []byte{0x92, 0x01, 0x02},
+			expected: "must be a map or an array of maps",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{MetricName: "msgpack"}
+			require.NoError(t, parser.Init())
+
+			_, err := parser.Parse(tt.input)
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
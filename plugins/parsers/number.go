@@ -0,0 +1,29 @@
+package parsers
+
+import (
+	"errors"
+	"strings"
+)
+
+// CheckNumberSeparators returns an error if the given decimal and group
+// separators cannot be used together by NormalizeNumber.
+func CheckNumberSeparators(decimal, group string) error {
+	if decimal != "" && decimal == group {
+		return errors.New("decimal and group separator must differ")
+	}
+	return nil
+}
+
+// NormalizeNumber converts a number formatted with the given decimal and
+// group separators to the format understood by the "strconv" package, e.g.
+// "1.234,56" with decimal separator "," and group separator "." becomes
+// "1234.56". Empty separators are left untouched.
+func NormalizeNumber(value, decimal, group string) string {
+	if group != "" {
+		value = strings.ReplaceAll(value, group, "")
+	}
+	if decimal != "" && decimal != "." {
+		value = strings.ReplaceAll(value, decimal, ".")
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		decimal  string
+		group    string
+		expected string
+	}{
+		{
+			name:     "european",
+			value:    "1.234,56",
+			decimal:  ",",
+			group:    ".",
+			expected: "1234.56",
+		},
+		{
+			name:     "english",
+			value:    "1,234.56",
+			decimal:  ".",
+			group:    ",",
+			expected: "1234.56",
+		},
+		{
+			name:     "swiss",
+			value:    "-1'234'567",
+			group:    "'",
+			expected: "-1234567",
+		},
+		{
+			name:     "decimal comma only",
+			value:    "0,5",
+			decimal:  ",",
+			expected: "0.5",
+		},
+		{
+			name:     "no separators",
+			value:    "1.234,56",
+			expected: "1.234,56",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, CheckNumberSeparators(tt.decimal, tt.group))
+			require.Equal(t, tt.expected, NormalizeNumber(tt.value, tt.decimal, tt.group))
+		})
+	}
+
+	require.EqualError(t, CheckNumberSeparators(",", ","), "decimal and group separator must differ")
+}
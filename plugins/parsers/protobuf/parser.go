@@ -0,0 +1,349 @@
+package protobuf
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	// Register the well-known types so descriptor sets can import them
+	// without including them
+	_ "google.golang.org/protobuf/types/known/anypb"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+type Parser struct {
+	DescriptorSet   string   `toml:"protobuf_descriptor_set"`
+	MessageType     string   `toml:"protobuf_message_type"`
+	TagKeys         []string `toml:"protobuf_tag_keys"`
+	TimestampField  string   `toml:"protobuf_timestamp_field"`
+	TimestampFormat string   `toml:"protobuf_timestamp_format"`
+	LengthDelimited bool     `toml:"protobuf_length_delimited"`
+
+	DefaultMetricName string            `toml:"-"`
+	DefaultTags       map[string]string `toml:"-"`
+	Log               telegraf.Logger   `toml:"-"`
+
+	msgType   protoreflect.MessageType
+	tagFilter filter.Filter
+}
+
+func (p *Parser) Init() error {
+	if p.DescriptorSet == "" {
+		return errors.New("'protobuf_descriptor_set' is required")
+	}
+	if p.MessageType == "" {
+		return errors.New("'protobuf_message_type' is required")
+	}
+
+	resolver, err := loadDescriptorSet(p.DescriptorSet)
+	if err != nil {
+		return fmt.Errorf("loading descriptor set %q failed: %w", p.DescriptorSet, err)
+	}
+
+	desc, err := resolver.FindDescriptorByName(protoreflect.FullName(p.MessageType))
+	if err != nil {
+		return fmt.Errorf("finding message type %q failed: %w", p.MessageType, err)
+	}
+	msgDesc, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return fmt.Errorf("%q is not a message type", p.MessageType)
+	}
+	p.msgType = dynamicpb.NewMessageType(msgDesc)
+
+	if p.TimestampField != "" && p.TimestampFormat == "" {
+		p.TimestampFormat = "unix"
+	}
+
+	p.tagFilter, err = filter.Compile(p.TagKeys)
+	if err != nil {
+		return fmt.Errorf("creating tag filter failed: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	t := time.Now()
+
+	if !p.LengthDelimited {
+		m, err := p.parseMessage(buf, t)
+		if err != nil {
+			return nil, err
+		}
+		return []telegraf.Metric{m}, nil
+	}
+
+	metrics := make([]telegraf.Metric, 0)
+	for len(buf) > 0 {
+		length, n := protowire.ConsumeVarint(buf)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid length prefix: %w", protowire.ParseError(n))
+		}
+		buf = buf[n:]
+		if uint64(len(buf)) < length {
+			return nil, fmt.Errorf("message length %d exceeds remaining %d bytes", length, len(buf))
+		}
+
+		m, err := p.parseMessage(buf[:length], t)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+		buf = buf[length:]
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(metrics) {
+	case 0:
+		return nil, nil
+	case 1:
+		return metrics[0], nil
+	default:
+		return metrics[0], fmt.Errorf("cannot parse line with multiple (%d) metrics", len(metrics))
+	}
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) parseMessage(buf []byte, t time.Time) (telegraf.Metric, error) {
+	msg := p.msgType.New()
+	if err := proto.Unmarshal(buf, msg.Interface()); err != nil {
+		return nil, fmt.Errorf("decoding message failed: %w", err)
+	}
+
+	fields := make(map[string]interface{})
+	flattenMessage("", msg, fields)
+
+	if p.TimestampField != "" {
+		v, found := fields[p.TimestampField]
+		if !found {
+			return nil, fmt.Errorf("timestamp field %q not found", p.TimestampField)
+		}
+		delete(fields, p.TimestampField)
+
+		if ts, ok := v.(time.Time); ok {
+			t = ts
+		} else {
+			var err error
+			t, err = internal.ParseTimestamp(p.TimestampFormat, v, time.UTC)
+			if err != nil {
+				return nil, fmt.Errorf("parsing timestamp failed: %w", err)
+			}
+		}
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	for k, v := range fields {
+		// Timestamps not used as metric time are kept as unix nanoseconds
+		if ts, ok := v.(time.Time); ok {
+			v = ts.UnixNano()
+			fields[k] = v
+		}
+
+		if p.tagFilter == nil || !p.tagFilter.Match(k) {
+			continue
+		}
+		tag, err := internal.ToString(v)
+		if err != nil {
+			return nil, fmt.Errorf("converting tag %q failed: %w", k, err)
+		}
+		tags[k] = tag
+		delete(fields, k)
+	}
+
+	return metric.New(p.DefaultMetricName, tags, fields, t), nil
+}
+
+// flattenMessage adds all fields of the given message to the field map
+// using underscores to join the names of nested messages, repeated
+// elements and map entries.
+func flattenMessage(prefix string, msg protoreflect.Message, fields map[string]interface{}) {
+	// Unwrap nested well-known types to plain values
+	var wkt protoreflect.FullName
+	if prefix != "" {
+		wkt = msg.Descriptor().FullName()
+	}
+	switch wkt {
+	case "google.protobuf.Timestamp":
+		seconds, nanos := secondsAndNanos(msg)
+		fields[prefix] = time.Unix(seconds, nanos).UTC()
+		return
+	case "google.protobuf.Duration":
+		seconds, nanos := secondsAndNanos(msg)
+		fields[prefix] = seconds*int64(time.Second) + nanos
+		return
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue",
+		"google.protobuf.BytesValue":
+		fd := msg.Descriptor().Fields().ByName("value")
+		fields[prefix] = convertValue(fd, msg.Get(fd))
+		return
+	}
+
+	fds := msg.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+
+		// Skip unset fields with explicit presence, fields without presence
+		// are reported with their default value.
+		if (fd.HasPresence() || fd.IsList() || fd.IsMap()) && !msg.Has(fd) {
+			continue
+		}
+
+		name := string(fd.Name())
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch {
+		case fd.IsList():
+			list := msg.Get(fd).List()
+			for idx := 0; idx < list.Len(); idx++ {
+				addValue(name+"_"+strconv.Itoa(idx), fd, list.Get(idx), fields)
+			}
+		case fd.IsMap():
+			msg.Get(fd).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				addValue(name+"_"+k.String(), fd.MapValue(), v, fields)
+				return true
+			})
+		default:
+			addValue(name, fd, msg.Get(fd), fields)
+		}
+	}
+}
+
+func addValue(name string, fd protoreflect.FieldDescriptor, v protoreflect.Value, fields map[string]interface{}) {
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		flattenMessage(name, v.Message(), fields)
+		return
+	}
+	fields[name] = convertValue(fd, v)
+}
+
+func convertValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int64(v.Enum())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	}
+	return v.Interface()
+}
+
+func secondsAndNanos(msg protoreflect.Message) (seconds, nanos int64) {
+	fds := msg.Descriptor().Fields()
+	return msg.Get(fds.ByName("seconds")).Int(), msg.Get(fds.ByName("nanos")).Int()
+}
+
+// loadDescriptorSet reads a binary FileDescriptorSet, e.g. generated by
+// "protoc --descriptor_set_out", and resolves all contained files. Imports
+// not contained in the set are looked up in the global registry which
+// contains the well-known types.
+func loadDescriptorSet(filename string) (*fallbackResolver, error) {
+	buf, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(buf, &set); err != nil {
+		return nil, fmt.Errorf("decoding failed: %w", err)
+	}
+
+	files := &protoregistry.Files{}
+	resolver := &fallbackResolver{local: files}
+	for _, fdp := range set.GetFile() {
+		// Prefer the global definition of the well-known types
+		if _, err := protoregistry.GlobalFiles.FindFileByPath(fdp.GetName()); err == nil {
+			continue
+		}
+		fd, err := protodesc.NewFile(fdp, resolver)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %q failed: %w", fdp.GetName(), err)
+		}
+		if err := files.RegisterFile(fd); err != nil {
+			return nil, fmt.Errorf("registering %q failed: %w", fdp.GetName(), err)
+		}
+	}
+
+	return resolver, nil
+}
+
+// fallbackResolver looks up descriptors in the local files first and falls
+// back to the global registry.
+type fallbackResolver struct {
+	local *protoregistry.Files
+}
+
+func (r *fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *fallbackResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+func init() {
+	parsers.Add("protobuf",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{DefaultMetricName: defaultMetricName}
+		},
+	)
+}
@@ -0,0 +1,280 @@
+package protobuf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+// encode converts the JSON representation of a message to its binary
+// protocol-buffer encoding using the message type of the parser.
+func encode(t *testing.T, p *Parser, data string) []byte {
+	msg := p.msgType.New().Interface()
+	require.NoError(t, protojson.Unmarshal([]byte(data), msg))
+	buf, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "scalars",
+			parser: &Parser{},
+			input:  `{"name": "sensor-1", "temperature": 21.5, "unit": "CELSIUS", "count": "-3", "flags": 7, "online": true}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"protobuf",
+					map[string]string{},
+					map[string]interface{}{
+						"name":        "sensor-1",
+						"location":    "",
+						"temperature": 21.5,
+						"unit":        "CELSIUS",
+						"count":       int64(-3),
+						"flags":       uint64(7),
+						"online":      true,
+						"raw":         "",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name:   "nested and repeated",
+			parser: &Parser{},
+			input: `{
+				"name": "sensor-1",
+				"samples": [1, 2],
+				"battery": {"voltage": 3.3, "level": 80},
+				"extra": {"pressure": 1013.25},
+				"raw": "AAE="
+			}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"protobuf",
+					map[string]string{},
+					map[string]interface{}{
+						"name":            "sensor-1",
+						"location":        "",
+						"temperature":     0.0,
+						"unit":            "UNKNOWN",
+						"count":           int64(0),
+						"flags":           uint64(0),
+						"online":          false,
+						"samples_0":       int64(1),
+						"samples_1":       int64(2),
+						"battery_voltage": 3.3,
+						"battery_level":   uint64(80),
+						"extra_pressure":  1013.25,
+						"raw":             "AAE=",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "well-known types with tags",
+			parser: &Parser{
+				TagKeys:        []string{"name", "location"},
+				TimestampField: "time",
+			},
+			input: `{"name": "sensor-1", "location": "kitchen", "time": "2020-01-01T23:59:59.5Z", "humidity": 45.5}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"protobuf",
+					map[string]string{
+						"name":     "sensor-1",
+						"location": "kitchen",
+					},
+					map[string]interface{}{
+						"temperature": 0.0,
+						"unit":        "UNKNOWN",
+						"count":       int64(0),
+						"flags":       uint64(0),
+						"online":      false,
+						"humidity":    45.5,
+						"raw":         "",
+					},
+					time.Unix(1577923199, 500000000),
+				),
+			},
+		},
+		{
+			name: "integer timestamp",
+			parser: &Parser{
+				TimestampField:  "count",
+				TimestampFormat: "unix_ms",
+			},
+			input: `{"count": "1577923199500", "temperature": 21.5}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"protobuf",
+					map[string]string{},
+					map[string]interface{}{
+						"name":        "",
+						"location":    "",
+						"temperature": 21.5,
+						"unit":        "UNKNOWN",
+						"flags":       uint64(0),
+						"online":      false,
+						"raw":         "",
+					},
+					time.Unix(1577923199, 500000000),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.DescriptorSet = "testdata/sensor.pb"
+			tt.parser.MessageType = "telegraf.test.Reading"
+			tt.parser.DefaultMetricName = "protobuf"
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse(encode(t, tt.parser, tt.input))
+			require.NoError(t, err)
+
+			var options []cmp.Option
+			if tt.parser.TimestampField == "" {
+				options = append(options, testutil.IgnoreTime())
+			}
+			testutil.RequireMetricsEqual(t, tt.expected, actual, options...)
+		})
+	}
+}
+
+func TestParseLengthDelimited(t *testing.T) {
+	parser := &Parser{
+		DescriptorSet:     "testdata/sensor.pb",
+		MessageType:       "telegraf.test.Battery",
+		LengthDelimited:   true,
+		DefaultMetricName: "battery",
+	}
+	require.NoError(t, parser.Init())
+
+	var buf []byte
+	for _, data := range []string{`{"voltage": 3.3, "level": 80}`, `{"voltage": 3.1, "level": 20}`, `{}`} {
+		msg := encode(t, parser, data)
+		buf = protowire.AppendVarint(buf, uint64(len(msg)))
+		buf = append(buf, msg...)
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"battery",
+			map[string]string{},
+			map[string]interface{}{"voltage": 3.3, "level": uint64(80)},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"battery",
+			map[string]string{},
+			map[string]interface{}{"voltage": 3.1, "level": uint64(20)},
+			time.Unix(0, 0),
+		),
+		metric.New(
+			"battery",
+			map[string]string{},
+			map[string]interface{}{"voltage": 0.0, "level": uint64(0)},
+			time.Unix(0, 0),
+		),
+	}
+
+	actual, err := parser.Parse(buf)
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+
+	_, err = parser.Parse(buf[:len(buf)-4])
+	require.ErrorContains(t, err, "exceeds remaining")
+}
+
+func TestParseWellKnownType(t *testing.T) {
+	parser := &Parser{
+		DescriptorSet:     "testdata/sensor.pb",
+		MessageType:       "google.protobuf.Duration",
+		DefaultMetricName: "duration",
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.Parse(encode(t, parser, `"1.5s"`))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, map[string]interface{}{"seconds": int64(1), "nanos": int64(500000000)}, actual[0].Fields())
+}
+
+func TestParseInvalid(t *testing.T) {
+	parser := &Parser{
+		DescriptorSet:     "testdata/sensor.pb",
+		MessageType:       "telegraf.test.Reading",
+		DefaultMetricName: "protobuf",
+	}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse([]byte{0x0a, 0x05, 0x61})
+	require.ErrorContains(t, err, "decoding message failed")
+}
+
+func TestInitInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		expected string
+	}{
+		{
+			name:     "missing descriptor set",
+			parser:   &Parser{MessageType: "telegraf.test.Reading"},
+			expected: "'protobuf_descriptor_set' is required",
+		},
+		{
+			name:     "missing message type",
+			parser:   &Parser{DescriptorSet: "testdata/sensor.pb"},
+			expected: "'protobuf_message_type' is required",
+		},
+		{
+			name: "unknown message type",
+			parser: &Parser{
+				DescriptorSet: "testdata/sensor.pb",
+				MessageType:   "telegraf.test.Unknown",
+			},
+			expected: `finding message type "telegraf.test.Unknown" failed`,
+		},
+		{
+			name: "enum instead of message",
+			parser: &Parser{
+				DescriptorSet: "testdata/sensor.pb",
+				MessageType:   "telegraf.test.Reading.Unit",
+			},
+			expected: `"telegraf.test.Reading.Unit" is not a message type`,
+		},
+		{
+			name: "invalid descriptor set",
+			parser: &Parser{
+				DescriptorSet: "testdata/sensor.proto",
+				MessageType:   "telegraf.test.Reading",
+			},
+			expected: `loading descriptor set "testdata/sensor.proto" failed: decoding failed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.parser.Init(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package regex
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// ErrNoMatch is returned if the pattern does not match the input
+var ErrNoMatch = errors.New("pattern does not match")
+
+type Parser struct {
+	Pattern          string            `toml:"regex_pattern"`
+	TagGroups        []string          `toml:"regex_tag_groups"`
+	FieldTypes       map[string]string `toml:"regex_field_types"`
+	MeasurementGroup string            `toml:"regex_measurement_group"`
+
+	MetricName  string            `toml:"-"`
+	DefaultTags map[string]string `toml:"-"`
+
+	re *regexp.Regexp
+}
+
+func (p *Parser) Init() error {
+	if p.Pattern == "" {
+		return errors.New("'regex_pattern' is required")
+	}
+
+	var err error
+	p.re, err = regexp.Compile(p.Pattern)
+	if err != nil {
+		return fmt.Errorf("compiling pattern failed: %w", err)
+	}
+
+	groups := make([]string, 0, p.re.NumSubexp())
+	for _, name := range p.re.SubexpNames() {
+		if name != "" {
+			groups = append(groups, name)
+		}
+	}
+	if len(groups) == 0 {
+		return errors.New("pattern does not contain any named group")
+	}
+
+	for _, name := range p.TagGroups {
+		if !slices.Contains(groups, name) {
+			return fmt.Errorf("unknown tag group %q", name)
+		}
+	}
+	for name, typ := range p.FieldTypes {
+		if !slices.Contains(groups, name) {
+			return fmt.Errorf("unknown field group %q", name)
+		}
+		switch typ {
+		case "auto", "int", "float", "bool", "string":
+		default:
+			return fmt.Errorf("invalid type %q for group %q", typ, name)
+		}
+	}
+	if p.MeasurementGroup != "" && !slices.Contains(groups, p.MeasurementGroup) {
+		return fmt.Errorf("unknown measurement group %q", p.MeasurementGroup)
+	}
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	metrics := make([]telegraf.Metric, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		m, err := p.ParseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	matches := p.re.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, ErrNoMatch
+	}
+
+	name := p.MetricName
+	tags := make(map[string]string, len(p.DefaultTags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	fields := make(map[string]interface{}, len(matches))
+
+	for i, group := range p.re.SubexpNames() {
+		// Skip unnamed groups and optional groups not taking part in the match
+		value := matches[i]
+		if group == "" || value == "" {
+			continue
+		}
+
+		switch {
+		case group == p.MeasurementGroup:
+			name = value
+		case slices.Contains(p.TagGroups, group):
+			tags[group] = value
+		default:
+			v, err := convert(value, p.FieldTypes[group])
+			if err != nil {
+				return nil, fmt.Errorf("converting group %q failed: %w", group, err)
+			}
+			fields[group] = v
+		}
+	}
+
+	return metric.New(name, tags, fields, time.Now()), nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+// convert returns the value in the given type. Values without type are
+// converted to integer, float or boolean if possible and kept as string
+// otherwise.
+func convert(value, typ string) (interface{}, error) {
+	switch typ {
+	case "int":
+		return strconv.ParseInt(value, 10, 64)
+	case "float":
+		return strconv.ParseFloat(value, 64)
+	case "bool":
+		return strconv.ParseBool(value)
+	case "string":
+		return value, nil
+	}
+
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseBool(value); err == nil {
+		return v, nil
+	}
+	return value, nil
+}
+
+func init() {
+	parsers.Add("regex",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{MetricName: defaultMetricName}
+		},
+	)
+}
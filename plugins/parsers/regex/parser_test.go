@@ -0,0 +1,141 @@
+package regex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name: "numeric and string group",
+			parser: &Parser{
+				Pattern: `^(?P<method>\w+) (?P<path>\S+) took (?P<duration>\d+)ms$`,
+			},
+			input: "GET /index.html took 42ms",
+			expected: []telegraf.Metric{
+				metric.New(
+					"regex",
+					map[string]string{},
+					map[string]interface{}{
+						"method":   "GET",
+						"path":     "/index.html",
+						"duration": int64(42),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "tags, types and measurement",
+			parser: &Parser{
+				Pattern:          `^(?P<service>\w+): (?P<method>\w+) (?:(?P<code>\d+) )?took (?P<duration>\d+)ms$`,
+				TagGroups:        []string{"method"},
+				FieldTypes:       map[string]string{"duration": "float", "code": "string"},
+				MeasurementGroup: "service",
+			},
+			input: "api: GET 200 took 42ms\nweb: POST took 7ms",
+			expected: []telegraf.Metric{
+				metric.New(
+					"api",
+					map[string]string{"method": "GET"},
+					map[string]interface{}{
+						"code":     "200",
+						"duration": float64(42),
+					},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"web",
+					map[string]string{"method": "POST"},
+					map[string]interface{}{
+						"duration": float64(7),
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "regex"
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	parser := &Parser{
+		Pattern:    `^(?P<method>\w+) took (?P<duration>\S+)ms$`,
+		FieldTypes: map[string]string{"duration": "int"},
+	}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse([]byte("GET took 42ms\nsomething else"))
+	require.ErrorIs(t, err, ErrNoMatch)
+
+	_, err = parser.Parse([]byte("GET took 4.2ms"))
+	require.ErrorContains(t, err, `converting group "duration" failed`)
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		expected string
+	}{
+		{
+			name:     "no pattern",
+			parser:   &Parser{},
+			expected: "'regex_pattern' is required",
+		},
+		{
+			name:     "invalid pattern",
+			parser:   &Parser{Pattern: `(?P<a>\d+`},
+			expected: "compiling pattern failed",
+		},
+		{
+			name:     "no named groups",
+			parser:   &Parser{Pattern: `(\d+)`},
+			expected: "pattern does not contain any named group",
+		},
+		{
+			name: "unknown tag group",
+			parser: &Parser{
+				Pattern:   `(?P<a>\d+)`,
+				TagGroups: []string{"b"},
+			},
+			expected: `unknown tag group "b"`,
+		},
+		{
+			name: "invalid type",
+			parser: &Parser{
+				Pattern:    `(?P<a>\d+)`,
+				FieldTypes: map[string]string{"a": "double"},
+			},
+			expected: `invalid type "double" for group "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorContains(t, tt.parser.Init(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package split
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+type Parser struct {
+	Delimiter   string   `toml:"split_delimiter"`
+	ColumnNames []string `toml:"split_column_names"`
+	ColumnTypes []string `toml:"split_column_types"`
+	TagColumns  []string `toml:"split_tag_columns"`
+	Mismatch    string   `toml:"split_column_mismatch"`
+
+	MetricName  string            `toml:"-"`
+	DefaultTags map[string]string `toml:"-"`
+
+	tagColumns map[string]bool
+}
+
+func (p *Parser) Init() error {
+	if p.Delimiter == "" {
+		p.Delimiter = ","
+	}
+
+	if len(p.ColumnNames) == 0 {
+		return errors.New("'split_column_names' is required")
+	}
+
+	if len(p.ColumnTypes) > 0 && len(p.ColumnTypes) != len(p.ColumnNames) {
+		return fmt.Errorf("number of column types (%d) does not match number of column names (%d)", len(p.ColumnTypes), len(p.ColumnNames))
+	}
+	for i, t := range p.ColumnTypes {
+		switch t {
+		case "", "auto", "int", "float", "bool", "string":
+		default:
+			return fmt.Errorf("invalid type %q for column %q", t, p.ColumnNames[i])
+		}
+	}
+
+	switch p.Mismatch {
+	case "":
+		p.Mismatch = "error"
+	case "error", "pad":
+	default:
+		return fmt.Errorf("invalid column mismatch handling %q", p.Mismatch)
+	}
+
+	p.tagColumns = make(map[string]bool, len(p.TagColumns))
+	for _, name := range p.TagColumns {
+		p.tagColumns[name] = true
+	}
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	metrics := make([]telegraf.Metric, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		m, err := p.ParseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	values := strings.Split(line, p.Delimiter)
+	if len(values) != len(p.ColumnNames) && p.Mismatch == "error" {
+		return nil, fmt.Errorf("expected %d columns but got %d", len(p.ColumnNames), len(values))
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags))
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	fields := make(map[string]interface{}, len(values))
+
+	// Missing columns are skipped and extra values are dropped when padding
+	for i, name := range p.ColumnNames {
+		if i >= len(values) {
+			break
+		}
+
+		// Skip columns without a name
+		if name == "" {
+			continue
+		}
+
+		// Treat empty values as missing
+		value := strings.TrimSpace(values[i])
+		if value == "" {
+			continue
+		}
+
+		if p.tagColumns[name] {
+			tags[name] = value
+			continue
+		}
+
+		var typ string
+		if len(p.ColumnTypes) > 0 {
+			typ = p.ColumnTypes[i]
+		}
+		v, err := convert(value, typ)
+		if err != nil {
+			return nil, fmt.Errorf("converting column %q failed: %w", name, err)
+		}
+		fields[name] = v
+	}
+
+	return metric.New(p.MetricName, tags, fields, time.Now()), nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+// convert returns the value in the given type. Values without type are
+// converted to integer, float or boolean if possible and kept as string
+// otherwise.
+func convert(value, typ string) (interface{}, error) {
+	switch typ {
+	case "int":
+		return strconv.ParseInt(value, 10, 64)
+	case "float":
+		return strconv.ParseFloat(value, 64)
+	case "bool":
+		return strconv.ParseBool(value)
+	case "string":
+		return value, nil
+	}
+
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseBool(value); err == nil {
+		return v, nil
+	}
+	return value, nil
+}
+
+func init() {
+	parsers.Add("split",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{MetricName: defaultMetricName}
+		},
+	)
+}
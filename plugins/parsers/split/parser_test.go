@@ -0,0 +1,219 @@
+package split
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name: "pipe delimited floats",
+			parser: &Parser{
+				Delimiter:   "|",
+				ColumnNames: []string{"min", "avg", "max"},
+				ColumnTypes: []string{"float", "float", "float"},
+			},
+			input: "1.2|3.4|5.6",
+			expected: []telegraf.Metric{
+				metric.New(
+					"split",
+					map[string]string{},
+					map[string]interface{}{
+						"min": 1.2,
+						"avg": 3.4,
+						"max": 5.6,
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "auto types and tags",
+			parser: &Parser{
+				ColumnNames: []string{"host", "count", "load", "up", "state", ""},
+				TagColumns:  []string{"host"},
+			},
+			input: "a, 42, 0.5, true, running, skipped\nb,1,1.5,false,stopped,skipped",
+			expected: []telegraf.Metric{
+				metric.New(
+					"split",
+					map[string]string{"host": "a"},
+					map[string]interface{}{
+						"count": int64(42),
+						"load":  0.5,
+						"up":    true,
+						"state": "running",
+					},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"split",
+					map[string]string{"host": "b"},
+					map[string]interface{}{
+						"count": int64(1),
+						"load":  1.5,
+						"up":    false,
+						"state": "stopped",
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "missing column with padding",
+			parser: &Parser{
+				Delimiter:   "|",
+				ColumnNames: []string{"min", "avg", "max"},
+				ColumnTypes: []string{"float", "float", "float"},
+				Mismatch:    "pad",
+			},
+			input: "1.2|3.4",
+			expected: []telegraf.Metric{
+				metric.New(
+					"split",
+					map[string]string{},
+					map[string]interface{}{
+						"min": 1.2,
+						"avg": 3.4,
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "extra and empty columns with padding",
+			parser: &Parser{
+				Delimiter:   "|",
+				ColumnNames: []string{"min", "avg", "max"},
+				ColumnTypes: []string{"float", "float", "float"},
+				Mismatch:    "pad",
+			},
+			input: "1.2||5.6|7.8",
+			expected: []telegraf.Metric{
+				metric.New(
+					"split",
+					map[string]string{},
+					map[string]interface{}{
+						"min": 1.2,
+						"max": 5.6,
+					},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "split"
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected string
+	}{
+		{
+			name: "missing column",
+			parser: &Parser{
+				Delimiter:   "|",
+				ColumnNames: []string{"min", "avg", "max"},
+			},
+			input:    "1.2|3.4",
+			expected: "expected 3 columns but got 2",
+		},
+		{
+			name: "extra column",
+			parser: &Parser{
+				Delimiter:   "|",
+				ColumnNames: []string{"min", "avg", "max"},
+			},
+			input:    "1.2|3.4|5.6|7.8",
+			expected: "expected 3 columns but got 4",
+		},
+		{
+			name: "invalid type",
+			parser: &Parser{
+				Delimiter:   "|",
+				ColumnNames: []string{"min", "avg", "max"},
+				ColumnTypes: []string{"float", "int", "float"},
+			},
+			input:    "1.2|3.4|5.6",
+			expected: `converting column "avg" failed`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.parser.Init())
+
+			_, err := tt.parser.Parse([]byte(tt.input))
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		expected string
+	}{
+		{
+			name:     "no columns",
+			parser:   &Parser{},
+			expected: "'split_column_names' is required",
+		},
+		{
+			name: "type count mismatch",
+			parser: &Parser{
+				ColumnNames: []string{"a", "b"},
+				ColumnTypes: []string{"int"},
+			},
+			expected: "number of column types (1) does not match number of column names (2)",
+		},
+		{
+			name: "invalid type",
+			parser: &Parser{
+				ColumnNames: []string{"a"},
+				ColumnTypes: []string{"double"},
+			},
+			expected: `invalid type "double" for column "a"`,
+		},
+		{
+			name: "invalid mismatch handling",
+			parser: &Parser{
+				ColumnNames: []string{"a"},
+				Mismatch:    "ignore",
+			},
+			expected: `invalid column mismatch handling "ignore"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.EqualError(t, tt.parser.Init(), tt.expected)
+		})
+	}
+}
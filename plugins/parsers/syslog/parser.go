@@ -0,0 +1,208 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/leodido/go-syslog/v4"
+	"github.com/leodido/go-syslog/v4/rfc3164"
+	"github.com/leodido/go-syslog/v4/rfc5424"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+type Parser struct {
+	MetricName     string `toml:"metric_name"`
+	SyslogStandard string `toml:"syslog_standard"`
+	BestEffort     bool   `toml:"syslog_best_effort"`
+	Separator      string `toml:"syslog_sdparam_separator"`
+
+	DefaultTags map[string]string `toml:"-"`
+
+	rfc3164 syslog.Machine
+	rfc5424 syslog.Machine
+}
+
+func (p *Parser) Init() error {
+	switch p.SyslogStandard {
+	case "":
+		p.SyslogStandard = "auto"
+	case "auto", "RFC3164", "RFC5424":
+	default:
+		return fmt.Errorf("invalid 'syslog_standard' %q", p.SyslogStandard)
+	}
+
+	if p.Separator == "" {
+		p.Separator = "_"
+	}
+
+	p.rfc3164 = rfc3164.NewParser(rfc3164.WithYear(rfc3164.CurrentYear{}))
+	p.rfc5424 = rfc5424.NewParser()
+	if p.BestEffort {
+		p.rfc3164.WithBestEffort()
+		p.rfc5424.WithBestEffort()
+	}
+
+	return nil
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	now := time.Now()
+
+	// Each line of the buffer contains a separate message
+	metrics := make([]telegraf.Metric, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		m, err := p.parseMessage(line, now)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("can not parse the line: %s, for data format: syslog ", line)
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
+
+func (p *Parser) parseMessage(line []byte, timestamp time.Time) (telegraf.Metric, error) {
+	machine := p.rfc3164
+	switch p.SyslogStandard {
+	case "RFC5424":
+		machine = p.rfc5424
+	case "auto":
+		if isRFC5424(line) {
+			machine = p.rfc5424
+		}
+	}
+
+	msg, err := machine.Parse(line)
+	if msg == nil {
+		if err == nil {
+			err = errors.New("no message found")
+		}
+		return nil, fmt.Errorf("parsing message %q failed: %w", string(line), err)
+	}
+
+	tags := make(map[string]string, len(p.DefaultTags)+4)
+	for k, v := range p.DefaultTags {
+		tags[k] = v
+	}
+	if v := msg.SeverityShortLevel(); v != nil {
+		tags["severity"] = *v
+	}
+	if v := msg.FacilityLevel(); v != nil {
+		tags["facility"] = *v
+	}
+
+	fields := make(map[string]interface{})
+	var base *syslog.Base
+	switch msg := msg.(type) {
+	case *rfc5424.SyslogMessage:
+		base = &msg.Base
+		fields["version"] = msg.Version
+		if msg.StructuredData != nil {
+			for sdid, sdparams := range *msg.StructuredData {
+				if len(sdparams) == 0 {
+					// When SD-ID does not have params we indicate its presence with a bool
+					fields[sdid] = true
+					continue
+				}
+				for k, v := range sdparams {
+					fields[sdid+p.Separator+k] = v
+				}
+			}
+		}
+	case *rfc3164.SyslogMessage:
+		base = &msg.Base
+	default:
+		return nil, fmt.Errorf("unexpected message type %T", msg)
+	}
+
+	if base.Priority != nil {
+		fields["priority"] = int(*base.Priority)
+	}
+	if base.Facility != nil {
+		fields["facility_code"] = int(*base.Facility)
+	}
+	if base.Severity != nil {
+		fields["severity_code"] = int(*base.Severity)
+	}
+	if base.Timestamp != nil {
+		timestamp = *base.Timestamp
+	}
+	if base.Hostname != nil {
+		tags["hostname"] = *base.Hostname
+	}
+	if base.Appname != nil {
+		tags["appname"] = *base.Appname
+	}
+	if base.ProcID != nil {
+		fields["procid"] = *base.ProcID
+	}
+	if base.MsgID != nil {
+		fields["msgid"] = *base.MsgID
+	}
+	if base.Message != nil {
+		fields["message"] = strings.TrimRightFunc(*base.Message, unicode.IsSpace)
+	}
+
+	return metric.New(p.MetricName, tags, fields, timestamp), nil
+}
+
+// isRFC5424 checks if the message contains the version number following
+// the priority as required by RFC5424, e.g. "<165>1 ...". RFC3164 messages
+// continue with the timestamp or hostname instead.
+func isRFC5424(line []byte) bool {
+	idx := bytes.IndexByte(line, '>')
+	if idx < 0 {
+		return false
+	}
+	version, _, found := bytes.Cut(line[idx+1:], []byte(" "))
+	if !found || len(version) == 0 || len(version) > 3 {
+		return false
+	}
+	for _, c := range version {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	parsers.Add("syslog",
+		func(defaultMetricName string) telegraf.Parser {
+			return &Parser{MetricName: defaultMetricName}
+		})
+}
@@ -0,0 +1,137 @@
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+)
+
+func TestParseRFC5424(t *testing.T) {
+	parser := &Parser{MetricName: "syslog"}
+	require.NoError(t, parser.Init())
+
+	input := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 1234 ID47 ` +
+		`[exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"][origin] An application event log entry...`
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"syslog",
+			map[string]string{
+				"severity": "notice",
+				"facility": "local4",
+				"hostname": "mymachine.example.com",
+				"appname":  "evntslog",
+			},
+			map[string]interface{}{
+				"version":                       uint16(1),
+				"priority":                      165,
+				"facility_code":                 20,
+				"severity_code":                 5,
+				"procid":                        "1234",
+				"msgid":                         "ID47",
+				"exampleSDID@32473_iut":         "3",
+				"exampleSDID@32473_eventSource": "Application",
+				"exampleSDID@32473_eventID":     "1011",
+				"origin":                        true,
+				"message":                       "An application event log entry...",
+			},
+			time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+		),
+	}
+
+	actual, err := parser.Parse([]byte(input))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestParseRFC3164(t *testing.T) {
+	tests := []struct {
+		name     string
+		standard string
+	}{
+		{
+			name: "auto detection",
+		},
+		{
+			name:     "explicit",
+			standard: "RFC3164",
+		},
+	}
+
+	input := "<34>Oct 11 22:14:15 mymachine su[42]: 'su root' failed for lonvick on /dev/pts/8\n"
+	expected := []telegraf.Metric{
+		metric.New(
+			"syslog",
+			map[string]string{
+				"severity": "crit",
+				"facility": "auth",
+				"hostname": "mymachine",
+				"appname":  "su",
+			},
+			map[string]interface{}{
+				"priority":      34,
+				"facility_code": 4,
+				"severity_code": 2,
+				"procid":        "42",
+				"message":       "'su root' failed for lonvick on /dev/pts/8",
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				MetricName:     "syslog",
+				SyslogStandard: tt.standard,
+			}
+			require.NoError(t, parser.Init())
+
+			actual, err := parser.Parse([]byte(input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseMultipleMessages(t *testing.T) {
+	parser := &Parser{
+		MetricName:  "syslog",
+		DefaultTags: map[string]string{"source": "file"},
+	}
+	require.NoError(t, parser.Init())
+
+	input := "<13>1 2018-10-01T12:00:00.0Z example.org root - - - first\n\n" +
+		"<13>Oct  1 12:00:00 example.org root: second\n"
+
+	actual, err := parser.Parse([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, "first", actual[0].Fields()["message"])
+	require.Equal(t, "second", actual[1].Fields()["message"])
+	for _, m := range actual {
+		require.Equal(t, "file", m.Tags()["source"])
+		require.Equal(t, "user", m.Tags()["facility"])
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	parser := &Parser{
+		MetricName:     "syslog",
+		SyslogStandard: "RFC5424",
+	}
+	require.NoError(t, parser.Init())
+
+	_, err := parser.Parse([]byte("<34>Oct 11 22:14:15 mymachine su: failed"))
+	require.ErrorContains(t, err, "parsing message")
+}
+
+func TestInitInvalid(t *testing.T) {
+	parser := &Parser{SyslogStandard: "RFC1234"}
+	require.EqualError(t, parser.Init(), `invalid 'syslog_standard' "RFC1234"`)
+}
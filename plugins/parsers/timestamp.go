@@ -0,0 +1,125 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf/internal"
+)
+
+var (
+	// ISO 8601 week dates like "2024-W05-3" or "2024W053" with optional day
+	weekDateExtendedRe = regexp.MustCompile(`^(\d{4})-W(\d{2})(?:-(\d))?$`)
+	weekDateBasicRe    = regexp.MustCompile(`^(\d{4})W(\d{2})(\d)?$`)
+	// ISO 8601 ordinal dates like "2024-123" or "2024123"
+	ordinalDateRe = regexp.MustCompile(`^(\d{4})-?(\d{3})$`)
+)
+
+// ParseTimestamp converts the given timestamp to time similar to
+// internal.ParseTimestamp but accepts fractional seconds of arbitrary
+// precision for layouts containing a fixed-width fraction. For example, the
+// layout "15:04:05.000" matches "12:00:00.1", "12:00:00.123456789" and
+// "12:00:00". Additionally, the "iso_week_date" and "iso_ordinal_date"
+// formats parse ISO 8601 week dates like "2024-W05-3" and ordinal dates like
+// "2024-123".
+func ParseTimestamp(format string, timestamp interface{}, location *time.Location, separator ...string) (time.Time, error) {
+	switch format {
+	case "iso_week_date", "iso_ordinal_date":
+		v, ok := timestamp.(string)
+		if !ok {
+			return time.Unix(0, 0), errors.New("unsupported type")
+		}
+		if location == nil {
+			location = time.UTC
+		}
+		if format == "iso_week_date" {
+			return parseWeekDate(v, location)
+		}
+		return parseOrdinalDate(v, location)
+	}
+	return internal.ParseTimestamp(flexibleFraction(format), timestamp, location, separator...)
+}
+
+// parseWeekDate returns the start of the day of the given ISO 8601 week date.
+// The day of the week is optional and defaults to Monday.
+func parseWeekDate(value string, location *time.Location) (time.Time, error) {
+	match := weekDateExtendedRe.FindStringSubmatch(value)
+	if match == nil {
+		match = weekDateBasicRe.FindStringSubmatch(value)
+	}
+	if match == nil {
+		return time.Unix(0, 0), fmt.Errorf("invalid week date %q", value)
+	}
+
+	// The expressions only match digits so conversion cannot fail
+	year, _ := strconv.Atoi(match[1])
+	week, _ := strconv.Atoi(match[2])
+	day := 1
+	if match[3] != "" {
+		day, _ = strconv.Atoi(match[3])
+	}
+
+	// The 28th of December is always in the last week of the year
+	_, weeks := time.Date(year, 12, 28, 0, 0, 0, 0, location).ISOWeek()
+	if week < 1 || week > weeks {
+		return time.Unix(0, 0), fmt.Errorf("invalid week %d in week date %q", week, value)
+	}
+	if day < 1 || day > 7 {
+		return time.Unix(0, 0), fmt.Errorf("invalid day %d in week date %q", day, value)
+	}
+
+	// The 4th of January is always in the first week of the year, so go
+	// back to the Monday of that week
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, location)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, (week-1)*7+day-1-offset), nil
+}
+
+// parseOrdinalDate returns the start of the day of the given ISO 8601
+// ordinal date.
+func parseOrdinalDate(value string, location *time.Location) (time.Time, error) {
+	match := ordinalDateRe.FindStringSubmatch(value)
+	if match == nil {
+		return time.Unix(0, 0), fmt.Errorf("invalid ordinal date %q", value)
+	}
+
+	// The expression only matches digits so conversion cannot fail
+	year, _ := strconv.Atoi(match[1])
+	day, _ := strconv.Atoi(match[2])
+
+	days := time.Date(year, 12, 31, 0, 0, 0, 0, location).YearDay()
+	if day < 1 || day > days {
+		return time.Unix(0, 0), fmt.Errorf("invalid day %d in ordinal date %q", day, value)
+	}
+	return time.Date(year, 1, day, 0, 0, 0, 0, location), nil
+}
+
+// flexibleFraction replaces the fixed-width fractional seconds of the layout
+// such as ".000" by their variable-width equivalent ".999". A fraction is a
+// run of zeros or nines directly following a period or comma and not being
+// followed by another digit, see the "time" package.
+func flexibleFraction(layout string) string {
+	buf := []byte(layout)
+	for i := 0; i+1 < len(buf); i++ {
+		if (buf[i] != '.' && buf[i] != ',') || buf[i+1] != '0' {
+			continue
+		}
+
+		j := i + 1
+		for j < len(buf) && buf[j] == '0' {
+			j++
+		}
+		if j < len(buf) && buf[j] >= '0' && buf[j] <= '9' {
+			i = j
+			continue
+		}
+		for k := i + 1; k < j; k++ {
+			buf[k] = '9'
+		}
+		i = j - 1
+	}
+	return string(buf)
+}
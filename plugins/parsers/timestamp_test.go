@@ -0,0 +1,238 @@
+package parsers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestampFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		expected time.Time
+	}{
+		{
+			name:     "milliseconds",
+			format:   "2006-01-02 15:04:05.000",
+			input:    "2024-03-01 12:00:00.123",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 123000000, time.UTC),
+		},
+		{
+			name:     "microseconds with millisecond layout",
+			format:   "2006-01-02 15:04:05.000",
+			input:    "2024-03-01 12:00:00.123456",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 123456000, time.UTC),
+		},
+		{
+			name:     "nanoseconds with millisecond layout",
+			format:   "2006-01-02 15:04:05.000",
+			input:    "2024-03-01 12:00:00.123456789",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC),
+		},
+		{
+			name:     "milliseconds with nanosecond layout",
+			format:   "2006-01-02 15:04:05.000000000",
+			input:    "2024-03-01 12:00:00.123",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 123000000, time.UTC),
+		},
+		{
+			name:     "trailing zeros omitted",
+			format:   "2006-01-02 15:04:05.000000",
+			input:    "2024-03-01 12:00:00.5",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 500000000, time.UTC),
+		},
+		{
+			name:     "no fraction",
+			format:   "2006-01-02 15:04:05.000",
+			input:    "2024-03-01 12:00:00",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "comma separator",
+			format:   "2006-01-02 15:04:05,000",
+			input:    "2024-03-01 12:00:00,123456",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 123456000, time.UTC),
+		},
+		{
+			name:     "fraction followed by timezone",
+			format:   "2006-01-02T15:04:05.000Z07:00",
+			input:    "2024-03-01T12:00:00.123456789+01:00",
+			expected: time.Date(2024, 3, 1, 11, 0, 0, 123456789, time.UTC),
+		},
+		{
+			name:     "unix timestamp",
+			format:   "unix",
+			input:    "1709294400.123456789",
+			expected: time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseTimestamp(tt.format, tt.input, time.UTC)
+			require.NoError(t, err)
+			require.Truef(t, tt.expected.Equal(actual), "expected %v but got %v", tt.expected, actual)
+		})
+	}
+}
+
+func TestParseTimestampISODates(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		expected time.Time
+	}{
+		{
+			name:     "week date",
+			format:   "iso_week_date",
+			input:    "2024-W05-3",
+			expected: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week date basic format",
+			format:   "iso_week_date",
+			input:    "2024W053",
+			expected: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week date without day",
+			format:   "iso_week_date",
+			input:    "2024-W05",
+			expected: time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week date in previous year",
+			format:   "iso_week_date",
+			input:    "2021-W01-1",
+			expected: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week date in next year",
+			format:   "iso_week_date",
+			input:    "2020-W53-5",
+			expected: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "ordinal date",
+			format:   "iso_ordinal_date",
+			input:    "2024-123",
+			expected: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "ordinal date basic format",
+			format:   "iso_ordinal_date",
+			input:    "2024123",
+			expected: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "ordinal date leap day",
+			format:   "iso_ordinal_date",
+			input:    "2024-366",
+			expected: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseTimestamp(tt.format, tt.input, time.UTC)
+			require.NoError(t, err)
+			require.Truef(t, tt.expected.Equal(actual), "expected %v but got %v", tt.expected, actual)
+		})
+	}
+
+	// The location applies to the start of the day
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	actual, err := ParseTimestamp("iso_ordinal_date", "2024-123", loc)
+	require.NoError(t, err)
+	require.True(t, time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC).Equal(actual))
+}
+
+func TestParseTimestampISODatesInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    interface{}
+		expected string
+	}{
+		{
+			name:     "week out of range",
+			format:   "iso_week_date",
+			input:    "2021-W53-1",
+			expected: `invalid week 53 in week date "2021-W53-1"`,
+		},
+		{
+			name:     "week zero",
+			format:   "iso_week_date",
+			input:    "2024-W00-1",
+			expected: `invalid week 0 in week date "2024-W00-1"`,
+		},
+		{
+			name:     "day out of range",
+			format:   "iso_week_date",
+			input:    "2024-W05-8",
+			expected: `invalid day 8 in week date "2024-W05-8"`,
+		},
+		{
+			name:     "mixed week date format",
+			format:   "iso_week_date",
+			input:    "2024-W053",
+			expected: `invalid week date "2024-W053"`,
+		},
+		{
+			name:     "ordinal day out of range",
+			format:   "iso_ordinal_date",
+			input:    "2023-366",
+			expected: `invalid day 366 in ordinal date "2023-366"`,
+		},
+		{
+			name:     "ordinal day zero",
+			format:   "iso_ordinal_date",
+			input:    "2024-000",
+			expected: `invalid day 0 in ordinal date "2024-000"`,
+		},
+		{
+			name:     "calendar date as ordinal date",
+			format:   "iso_ordinal_date",
+			input:    "2024-05-02",
+			expected: `invalid ordinal date "2024-05-02"`,
+		},
+		{
+			name:     "non-string",
+			format:   "iso_ordinal_date",
+			input:    2024123,
+			expected: "unsupported type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTimestamp(tt.format, tt.input, time.UTC)
+			require.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestFlexibleFraction(t *testing.T) {
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{layout: "15:04:05.000", expected: "15:04:05.999"},
+		{layout: "15:04:05,000000", expected: "15:04:05,999999"},
+		{layout: "15:04:05.999999999", expected: "15:04:05.999999999"},
+		{layout: "2006.01.02", expected: "2006.01.02"},
+		{layout: "15:04:05.0001", expected: "15:04:05.0001"},
+		{layout: "Jan _2 15:04:05.000000 MST", expected: "Jan _2 15:04:05.999999 MST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			require.Equal(t, tt.expected, flexibleFraction(tt.layout))
+		})
+	}
+}
@@ -2,6 +2,8 @@ package value
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,82 +14,280 @@ import (
 	"github.com/influxdata/telegraf/plugins/parsers"
 )
 
+// DataTypeList holds either a single data type or an ordered list of
+// candidate data types joined by commas.
+type DataTypeList string
+
+// UnmarshalTOML accepts both a single string and a list of strings
+func (d *DataTypeList) UnmarshalTOML(fn func(interface{}) error) error {
+	var single string
+	if err := fn(&single); err == nil {
+		*d = DataTypeList(single)
+		return nil
+	}
+
+	var list []string
+	if err := fn(&list); err != nil {
+		return err
+	}
+	*d = DataTypeList(strings.Join(list, ","))
+	return nil
+}
+
 type Parser struct {
-	DataType    string            `toml:"data_type"`
-	FieldName   string            `toml:"value_field_name"`
-	MetricName  string            `toml:"-"`
-	DefaultTags map[string]string `toml:"-"`
+	DataType         DataTypeList      `toml:"data_type"`
+	FieldName        string            `toml:"value_field_name"`
+	Base             *int              `toml:"value_base"`
+	DurationUnit     string            `toml:"value_duration_unit"`
+	TrueValues       []string          `toml:"value_true_values"`
+	FalseValues      []string          `toml:"value_false_values"`
+	Scale            *float64          `toml:"value_scale"`
+	Offset           *float64          `toml:"value_offset"`
+	ArrayMode        bool              `toml:"value_array_mode"`
+	Delimiter        string            `toml:"value_array_delimiter"`
+	IndexTag         string            `toml:"value_array_index_tag"`
+	DecimalSeparator string            `toml:"decimal_separator"`
+	GroupSeparator   string            `toml:"group_separator"`
+	MetricName       string            `toml:"-"`
+	DefaultTags      map[string]string `toml:"-"`
+
+	dataTypes []string
+	base      int
+	booleans  map[string]bool
 }
 
 func (v *Parser) Init() error {
-	switch v.DataType {
-	case "", "int", "integer":
-		v.DataType = "int"
-	case "float", "long":
-		v.DataType = "float"
-	case "str", "string":
-		v.DataType = "string"
-	case "bool", "boolean":
-		v.DataType = "bool"
-	case "auto_integer", "auto_float":
-		// Do nothing both are valid
-	default:
-		return fmt.Errorf("unknown datatype %q", v.DataType)
+	v.dataTypes = make([]string, 0, 1)
+	for _, dt := range strings.Split(string(v.DataType), ",") {
+		dt = strings.TrimSpace(dt)
+		switch dt {
+		case "", "int", "integer":
+			dt = "int"
+		case "float", "long":
+			dt = "float"
+		case "str", "string":
+			dt = "string"
+		case "bool", "boolean":
+			dt = "bool"
+		case "duration", "auto_integer", "auto_float":
+			// Do nothing all are valid
+		default:
+			return fmt.Errorf("unknown datatype %q", dt)
+		}
+		v.dataTypes = append(v.dataTypes, dt)
+	}
+
+	if err := parsers.CheckDurationUnit(v.DurationUnit); err != nil {
+		return err
+	}
+
+	if err := parsers.CheckNumberSeparators(v.DecimalSeparator, v.GroupSeparator); err != nil {
+		return err
+	}
+
+	if len(v.TrueValues) > 0 || len(v.FalseValues) > 0 {
+		if len(v.TrueValues) == 0 || len(v.FalseValues) == 0 {
+			return errors.New("value_true_values and value_false_values must be specified together")
+		}
+		v.booleans = make(map[string]bool, len(v.TrueValues)+len(v.FalseValues))
+		for _, s := range v.TrueValues {
+			v.booleans[strings.ToLower(s)] = true
+		}
+		for _, s := range v.FalseValues {
+			s = strings.ToLower(s)
+			if _, found := v.booleans[s]; found {
+				return fmt.Errorf("value %q is both a true and a false value", s)
+			}
+			v.booleans[s] = false
+		}
 	}
 
 	if v.FieldName == "" {
 		v.FieldName = "value"
 	}
 
+	if v.Delimiter == "" {
+		v.Delimiter = ","
+	}
+
+	v.base = 10
+	if v.Base != nil {
+		switch *v.Base {
+		case 0, 2, 8, 10, 16:
+			v.base = *v.Base
+		default:
+			return fmt.Errorf("invalid base %d", *v.Base)
+		}
+	}
+
 	return nil
 }
 
 func (v *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
 	vStr := string(bytes.TrimSpace(bytes.Trim(buf, "\x00")))
+	now := time.Now().UTC()
 
-	// unless it's a string, separate out any fields in the buffer,
-	// ignore anything but the last.
-	if v.DataType != "string" {
-		values := strings.Fields(vStr)
-		if len(values) < 1 {
+	if !v.ArrayMode {
+		value, converted, err := v.parseValue(vStr)
+		if err != nil {
+			return nil, err
+		}
+		if !converted {
 			return []telegraf.Metric{}, nil
 		}
-		vStr = values[len(values)-1]
+		fields := map[string]interface{}{v.FieldName: value}
+		return []telegraf.Metric{metric.New(v.MetricName, v.DefaultTags, fields, now)}, nil
 	}
 
-	var value interface{}
+	elements, err := v.splitArray(vStr)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]telegraf.Metric, 0, len(elements))
+	for i, element := range elements {
+		value, converted, err := v.parseValue(strings.TrimSpace(element))
+		if err != nil {
+			return nil, fmt.Errorf("parsing element %d failed: %w", i, err)
+		}
+		if !converted {
+			continue
+		}
+		fields := map[string]interface{}{v.FieldName: value}
+		m := metric.New(v.MetricName, v.DefaultTags, fields, now)
+		if v.IndexTag != "" {
+			m.AddTag(v.IndexTag, strconv.Itoa(i))
+		}
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
+
+// parseValue converts the given string using the first matching data type.
+// The returned flag is false if the string was empty and no conversion
+// was possible.
+func (v *Parser) parseValue(vStr string) (interface{}, bool, error) {
+	// unless it's a string, separate out any fields in the buffer,
+	// ignore anything but the last.
+	var last string
+	if values := strings.Fields(vStr); len(values) > 0 {
+		last = values[len(values)-1]
+	}
+
+	// Try the data types in order and use the first successful conversion
 	var err error
-	switch v.DataType {
+	for _, dt := range v.dataTypes {
+		if dt != "string" && last == "" {
+			continue
+		}
+		var value interface{}
+		value, err = v.convert(dt, vStr, last)
+		if err == nil {
+			// Durations are scaled by their unit instead
+			if dt != "duration" {
+				value = v.calibrate(value)
+			}
+			return value, true, nil
+		}
+	}
+	return nil, false, err
+}
+
+// splitArray separates the elements of a JSON array of scalars or of a
+// delimited list of values
+func (v *Parser) splitArray(vStr string) ([]string, error) {
+	if !strings.HasPrefix(vStr, "[") || !strings.HasSuffix(vStr, "]") {
+		if vStr == "" {
+			return nil, nil
+		}
+		return strings.Split(vStr, v.Delimiter), nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(vStr), &raw); err != nil {
+		return nil, fmt.Errorf("decoding array failed: %w", err)
+	}
+
+	elements := make([]string, 0, len(raw))
+	for _, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			elements = append(elements, s)
+			continue
+		}
+		switch {
+		case bytes.HasPrefix(r, []byte("{")), bytes.HasPrefix(r, []byte("[")):
+			return nil, errors.New("arrays may only contain scalar values")
+		case bytes.Equal(r, []byte("null")):
+			elements = append(elements, "")
+		default:
+			elements = append(elements, string(r))
+		}
+	}
+	return elements, nil
+}
+
+// calibrate applies the configured scale and offset to integer and float
+// values resulting in a float. Other values are returned unchanged.
+func (v *Parser) calibrate(value interface{}) interface{} {
+	if v.Scale == nil && v.Offset == nil {
+		return value
+	}
+
+	scale, offset := 1.0, 0.0
+	if v.Scale != nil {
+		scale = *v.Scale
+	}
+	if v.Offset != nil {
+		offset = *v.Offset
+	}
+
+	switch x := value.(type) {
+	case int64:
+		return float64(x)*scale + offset
+	case float64:
+		return x*scale + offset
+	}
+	return value
+}
+
+func (v *Parser) convert(dt, full, last string) (interface{}, error) {
+	switch dt {
 	case "int":
-		value, err = strconv.Atoi(vStr)
+		return strconv.ParseInt(v.number(last), v.base, 64)
 	case "float":
-		value, err = strconv.ParseFloat(vStr, 64)
+		return strconv.ParseFloat(v.number(last), 64)
 	case "string":
-		value = vStr
+		return full, nil
 	case "bool":
-		value, err = strconv.ParseBool(vStr)
+		if v.booleans == nil {
+			return strconv.ParseBool(last)
+		}
+		if value, found := v.booleans[strings.ToLower(last)]; found {
+			return value, nil
+		}
+		return nil, fmt.Errorf("invalid boolean value %q", last)
+	case "duration":
+		return parsers.ParseDuration(last, v.DurationUnit)
 	case "auto_integer":
-		value, err = strconv.Atoi(vStr)
-		if err != nil {
-			value = vStr
-			err = nil
+		if value, err := strconv.ParseInt(v.number(last), v.base, 64); err == nil {
+			return value, nil
 		}
+		return last, nil
 	case "auto_float":
-		value, err = strconv.ParseFloat(vStr, 64)
-		if err != nil {
-			value = vStr
-			err = nil
+		if value, err := strconv.ParseFloat(v.number(last), 64); err == nil {
+			return value, nil
 		}
+		return last, nil
 	}
-	if err != nil {
-		return nil, err
-	}
-
-	fields := map[string]interface{}{v.FieldName: value}
-	m := metric.New(v.MetricName, v.DefaultTags,
-		fields, time.Now().UTC())
+	return nil, fmt.Errorf("unknown datatype %q", dt)
+}
 
-	return []telegraf.Metric{m}, nil
+// number removes the group separators and replaces the decimal separator of
+// numeric values if configured
+func (v *Parser) number(value string) string {
+	return parsers.NormalizeNumber(value, v.DecimalSeparator, v.GroupSeparator)
 }
 
 func (v *Parser) ParseLine(line string) (telegraf.Metric, error) {
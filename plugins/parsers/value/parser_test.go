@@ -7,13 +7,14 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
+	"github.com/influxdata/toml"
 	"github.com/stretchr/testify/require"
 )
 
 func TestParseValidValues(t *testing.T) {
 	tests := []struct {
 		name     string
-		dtype    string
+		dtype    DataTypeList
 		input    []byte
 		expected interface{}
 	}{
@@ -110,7 +111,7 @@ func TestParseValidValues(t *testing.T) {
 func TestParseLineValidValues(t *testing.T) {
 	tests := []struct {
 		name     string
-		dtype    string
+		dtype    DataTypeList
 		input    string
 		expected interface{}
 	}{
@@ -183,7 +184,7 @@ func TestParseCustomFieldName(t *testing.T) {
 func TestParseInvalidValues(t *testing.T) {
 	tests := []struct {
 		name  string
-		dtype string
+		dtype DataTypeList
 		input []byte
 	}{
 		{
@@ -220,7 +221,7 @@ func TestParseInvalidValues(t *testing.T) {
 func TestParseLineInvalidValues(t *testing.T) {
 	tests := []struct {
 		name  string
-		dtype string
+		dtype DataTypeList
 		input string
 	}{
 		{
@@ -300,6 +301,499 @@ func TestInvalidDatatype(t *testing.T) {
 	require.ErrorContains(t, parser.Init(), "unknown datatype")
 }
 
+func TestParseCandidateDatatypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected interface{}
+	}{
+		{
+			name:     "integer",
+			input:    []byte("55"),
+			expected: int64(55),
+		},
+		{
+			name:     "float fallback",
+			input:    []byte("1.5"),
+			expected: float64(1.5),
+		},
+		{
+			name:     "string fallback",
+			input:    []byte("foo bar"),
+			expected: "foo bar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := metric.New(
+				"value_test",
+				map[string]string{},
+				map[string]interface{}{"value": tt.expected},
+				time.Unix(0, 0),
+			)
+
+			plugin := Parser{
+				MetricName: "value_test",
+				DataType:   "integer, float, string",
+			}
+			require.NoError(t, plugin.Init())
+			actual, err := plugin.Parse(tt.input)
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			testutil.RequireMetricEqual(t, expected, actual[0], testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseCandidateDatatypesInvalid(t *testing.T) {
+	plugin := Parser{
+		MetricName: "value_test",
+		DataType:   "integer,boolean",
+	}
+	require.NoError(t, plugin.Init())
+	_, err := plugin.Parse([]byte("1.5"))
+	require.ErrorContains(t, err, "invalid syntax")
+}
+
+func TestInvalidCandidateDatatype(t *testing.T) {
+	parser := Parser{
+		MetricName: "value_test",
+		DataType:   "integer,foo",
+	}
+	require.ErrorContains(t, parser.Init(), "unknown datatype \"foo\"")
+}
+
+func TestDatatypeTOML(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      string
+		expected DataTypeList
+	}{
+		{
+			name:     "single",
+			cfg:      `data_type = "float"`,
+			expected: "float",
+		},
+		{
+			name:     "list",
+			cfg:      `data_type = ["integer", "float", "string"]`,
+			expected: "integer,float,string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var plugin Parser
+			require.NoError(t, toml.Unmarshal([]byte(tt.cfg), &plugin))
+			require.Equal(t, tt.expected, plugin.DataType)
+		})
+	}
+}
+
+func TestParseIntegerBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     *int
+		dtype    DataTypeList
+		input    []byte
+		expected interface{}
+	}{
+		{
+			name:     "default base",
+			dtype:    "integer",
+			input:    []byte("010"),
+			expected: int64(10),
+		},
+		{
+			name:     "binary",
+			base:     intPtr(2),
+			dtype:    "integer",
+			input:    []byte("1010"),
+			expected: int64(10),
+		},
+		{
+			name:     "octal",
+			base:     intPtr(8),
+			dtype:    "integer",
+			input:    []byte("017"),
+			expected: int64(15),
+		},
+		{
+			name:     "hexadecimal",
+			base:     intPtr(16),
+			dtype:    "integer",
+			input:    []byte("1f"),
+			expected: int64(31),
+		},
+		{
+			name:     "auto-detect hexadecimal",
+			base:     intPtr(0),
+			dtype:    "integer",
+			input:    []byte("0x1f"),
+			expected: int64(31),
+		},
+		{
+			name:     "auto-detect octal",
+			base:     intPtr(0),
+			dtype:    "integer",
+			input:    []byte("0o17"),
+			expected: int64(15),
+		},
+		{
+			name:     "auto_integer hexadecimal",
+			base:     intPtr(16),
+			dtype:    "auto_integer",
+			input:    []byte("ff"),
+			expected: int64(255),
+		},
+		{
+			name:     "auto_integer fallback",
+			base:     intPtr(16),
+			dtype:    "auto_integer",
+			input:    []byte("xyz"),
+			expected: "xyz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := metric.New(
+				"value_test",
+				map[string]string{},
+				map[string]interface{}{"value": tt.expected},
+				time.Unix(0, 0),
+			)
+
+			plugin := Parser{
+				MetricName: "value_test",
+				DataType:   tt.dtype,
+				Base:       tt.base,
+			}
+			require.NoError(t, plugin.Init())
+			actual, err := plugin.Parse(tt.input)
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			testutil.RequireMetricEqual(t, expected, actual[0], testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestInvalidBase(t *testing.T) {
+	parser := Parser{
+		MetricName: "value_test",
+		DataType:   "integer",
+		Base:       intPtr(7),
+	}
+	require.ErrorContains(t, parser.Init(), "invalid base 7")
+}
+
+func TestParseCustomBooleans(t *testing.T) {
+	plugin := Parser{
+		MetricName:  "value_test",
+		DataType:    "boolean",
+		TrueValues:  []string{"on", "yes", "1", "Enabled"},
+		FalseValues: []string{"off", "no", "0", "disabled"},
+	}
+	require.NoError(t, plugin.Init())
+
+	tests := map[string]bool{
+		"on":       true,
+		"ON":       true,
+		"enabled":  true,
+		"1":        true,
+		"off":      false,
+		"No":       false,
+		"DISABLED": false,
+	}
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			actual, err := plugin.Parse([]byte(input))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			require.Equal(t, map[string]interface{}{"value": expected}, actual[0].Fields())
+		})
+	}
+
+	// Go's default boolean strings are not accepted anymore
+	_, err := plugin.Parse([]byte("true"))
+	require.EqualError(t, err, `invalid boolean value "true"`)
+}
+
+func TestInvalidCustomBooleans(t *testing.T) {
+	plugin := Parser{
+		MetricName: "value_test",
+		DataType:   "boolean",
+		TrueValues: []string{"on"},
+	}
+	require.ErrorContains(t, plugin.Init(), "must be specified together")
+
+	plugin = Parser{
+		MetricName:  "value_test",
+		DataType:    "boolean",
+		TrueValues:  []string{"on", "x"},
+		FalseValues: []string{"off", "X"},
+	}
+	require.ErrorContains(t, plugin.Init(), `value "x" is both a true and a false value`)
+}
+
+func TestParseCalibration(t *testing.T) {
+	tests := []struct {
+		name     string
+		dtype    DataTypeList
+		scale    *float64
+		offset   *float64
+		input    string
+		expected interface{}
+	}{
+		{
+			name:     "integer scaled",
+			dtype:    "int",
+			scale:    floatPtr(0.1),
+			input:    "123",
+			expected: 12.3,
+		},
+		{
+			name:     "float with offset",
+			dtype:    "float",
+			offset:   floatPtr(-273.15),
+			input:    "300.5",
+			expected: 27.35,
+		},
+		{
+			name:     "scale and offset",
+			dtype:    "auto_integer",
+			scale:    floatPtr(2),
+			offset:   floatPtr(1),
+			input:    "20",
+			expected: 41.0,
+		},
+		{
+			name:     "string unchanged",
+			dtype:    "auto_float",
+			scale:    floatPtr(2),
+			input:    "abc",
+			expected: "abc",
+		},
+		{
+			name:     "boolean unchanged",
+			dtype:    "boolean",
+			scale:    floatPtr(2),
+			input:    "true",
+			expected: true,
+		},
+		{
+			name:     "duration unchanged",
+			dtype:    "duration",
+			scale:    floatPtr(2),
+			input:    "1s",
+			expected: int64(1000000000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := Parser{
+				MetricName: "value_test",
+				DataType:   tt.dtype,
+				Scale:      tt.scale,
+				Offset:     tt.offset,
+			}
+			require.NoError(t, plugin.Init())
+			actual, err := plugin.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+
+			value := actual[0].Fields()["value"]
+			if expected, ok := tt.expected.(float64); ok {
+				require.InDelta(t, expected, value, 1e-9)
+			} else {
+				require.Equal(t, tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestParseNumberSeparators(t *testing.T) {
+	tests := []struct {
+		name     string
+		dtype    DataTypeList
+		input    string
+		expected interface{}
+	}{
+		{
+			name:     "float",
+			dtype:    "float",
+			input:    "1.234,56",
+			expected: 1234.56,
+		},
+		{
+			name:     "integer",
+			dtype:    "int",
+			input:    "1.234.567",
+			expected: int64(1234567),
+		},
+		{
+			name:     "auto float",
+			dtype:    "auto_float",
+			input:    "-0,25",
+			expected: -0.25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := Parser{
+				MetricName:       "value_test",
+				DataType:         tt.dtype,
+				DecimalSeparator: ",",
+				GroupSeparator:   ".",
+			}
+			require.NoError(t, plugin.Init())
+			actual, err := plugin.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			require.Equal(t, tt.expected, actual[0].Fields()["value"])
+		})
+	}
+
+	plugin := Parser{
+		MetricName:       "value_test",
+		DecimalSeparator: ",",
+		GroupSeparator:   ",",
+	}
+	require.ErrorContains(t, plugin.Init(), "decimal and group separator must differ")
+}
+
+func TestParseArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   *Parser
+		input    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "delimited",
+			parser: &Parser{DataType: "int"},
+			input:  "10,20,30",
+			expected: []telegraf.Metric{
+				metric.New("value_test", map[string]string{}, map[string]interface{}{"value": int64(10)}, time.Unix(0, 0)),
+				metric.New("value_test", map[string]string{}, map[string]interface{}{"value": int64(20)}, time.Unix(0, 0)),
+				metric.New("value_test", map[string]string{}, map[string]interface{}{"value": int64(30)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:   "custom delimiter with index",
+			parser: &Parser{DataType: "float", Delimiter: ";", IndexTag: "index"},
+			input:  "1.5; 2.5;",
+			expected: []telegraf.Metric{
+				metric.New("value_test", map[string]string{"index": "0"}, map[string]interface{}{"value": 1.5}, time.Unix(0, 0)),
+				metric.New("value_test", map[string]string{"index": "1"}, map[string]interface{}{"value": 2.5}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:   "json array",
+			parser: &Parser{DataType: "auto_integer", FieldName: "reading"},
+			input:  `[1, "two", null, 3]`,
+			expected: []telegraf.Metric{
+				metric.New("value_test", map[string]string{}, map[string]interface{}{"reading": int64(1)}, time.Unix(0, 0)),
+				metric.New("value_test", map[string]string{}, map[string]interface{}{"reading": "two"}, time.Unix(0, 0)),
+				metric.New("value_test", map[string]string{}, map[string]interface{}{"reading": int64(3)}, time.Unix(0, 0)),
+			},
+		},
+		{
+			name:     "empty",
+			parser:   &Parser{DataType: "int"},
+			input:    "",
+			expected: []telegraf.Metric{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.MetricName = "value_test"
+			tt.parser.ArrayMode = true
+			require.NoError(t, tt.parser.Init())
+
+			actual, err := tt.parser.Parse([]byte(tt.input))
+			require.NoError(t, err)
+			testutil.RequireMetricsEqual(t, tt.expected, actual, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestParseArrayInvalid(t *testing.T) {
+	plugin := Parser{
+		MetricName: "value_test",
+		DataType:   "int",
+		ArrayMode:  true,
+	}
+	require.NoError(t, plugin.Init())
+
+	_, err := plugin.Parse([]byte("10,abc,30"))
+	require.ErrorContains(t, err, "parsing element 1 failed")
+
+	_, err = plugin.Parse([]byte(`[1, [2, 3]]`))
+	require.EqualError(t, err, "arrays may only contain scalar values")
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     string
+		expected int64
+	}{
+		{
+			name:     "default unit",
+			expected: 5400000000000,
+		},
+		{
+			name:     "seconds",
+			unit:     "s",
+			expected: 5400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := metric.New(
+				"value_test",
+				map[string]string{},
+				map[string]interface{}{"value": tt.expected},
+				time.Unix(0, 0),
+			)
+
+			plugin := Parser{
+				MetricName:   "value_test",
+				DataType:     "duration",
+				DurationUnit: tt.unit,
+			}
+			require.NoError(t, plugin.Init())
+			actual, err := plugin.Parse([]byte("1h30m"))
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			testutil.RequireMetricEqual(t, expected, actual[0], testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestInvalidDurationUnit(t *testing.T) {
+	parser := Parser{
+		MetricName:   "value_test",
+		DataType:     "duration",
+		DurationUnit: "days",
+	}
+	require.ErrorContains(t, parser.Init(), `invalid duration unit "days"`)
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
 const benchmarkData = `5`
 
 func TestBenchmarkData(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -43,6 +44,7 @@ type Parser struct {
 	PrintDocument       bool              `toml:"xpath_print_document"`
 	AllowEmptySelection bool              `toml:"xpath_allow_empty_selection"`
 	NativeTypes         bool              `toml:"xpath_native_types"`
+	Namespaces          map[string]string `toml:"xpath_namespaces"`
 	Trace               bool              `toml:"xpath_trace"`
 	Configs             []Config          `toml:"xpath"`
 	DefaultMetricName   string            `toml:"-"`
@@ -86,9 +88,13 @@ type Config struct {
 }
 
 func (p *Parser) Init() error {
+	if len(p.Namespaces) > 0 && p.Format != "" && p.Format != "xml" {
+		return fmt.Errorf("namespaces are not supported for data-format %q", p.Format)
+	}
+
 	switch p.Format {
 	case "", "xml":
-		p.document = &xmlDocument{}
+		p.document = &xmlDocument{namespaces: p.Namespaces}
 
 		// Required for backward compatibility
 		if len(p.ConfigsXML) > 0 {
@@ -185,6 +191,12 @@ func (p *Parser) Init() error {
 		}
 		cfg.FieldsBase64Filter = bf
 
+		if len(p.Namespaces) > 0 {
+			if err := p.checkNamespacePrefixes(cfg); err != nil {
+				return fmt.Errorf("invalid query in config %d: %w", i+1, err)
+			}
+		}
+
 		p.Configs[i] = cfg
 	}
 
@@ -512,7 +524,12 @@ func (p *Parser) executeQuery(doc, selected dataNode, query string) (r interface
 	}
 
 	// Compile the query
-	expr, err := path.Compile(query)
+	var expr *path.Expr
+	if len(p.Namespaces) > 0 {
+		expr, err = path.CompileWithNS(query, p.Namespaces)
+	} else {
+		expr, err = path.Compile(query)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile query %q: %w", query, err)
 	}
@@ -548,6 +565,49 @@ func (p *Parser) executeQuery(doc, selected dataNode, query string) (r interface
 	return nil, nil
 }
 
+var (
+	quotedStringRe    = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+	namespacePrefixRe = regexp.MustCompile(`(^|[^\w.:-])([A-Za-z_][\w.-]*):([A-Za-z_*])`)
+)
+
+// checkNamespacePrefixes makes sure all namespace prefixes used in the
+// queries of the given config are declared.
+func (p *Parser) checkNamespacePrefixes(cfg Config) error {
+	queries := []string{
+		cfg.MetricQuery,
+		cfg.Selection,
+		cfg.Timestamp,
+		cfg.FieldSelection,
+		cfg.FieldNameQuery,
+		cfg.FieldValueQuery,
+		cfg.TagSelection,
+		cfg.TagNameQuery,
+		cfg.TagValueQuery,
+	}
+	for _, q := range cfg.Tags {
+		queries = append(queries, q)
+	}
+	for _, q := range cfg.Fields {
+		queries = append(queries, q)
+	}
+	for _, q := range cfg.FieldsInt {
+		queries = append(queries, q)
+	}
+
+	for _, query := range queries {
+		// Ignore string literals and axis separators as they contain colons
+		stripped := quotedStringRe.ReplaceAllString(query, "''")
+		stripped = strings.ReplaceAll(stripped, "::", "/")
+		for _, match := range namespacePrefixRe.FindAllStringSubmatch(stripped, -1) {
+			if _, found := p.Namespaces[match[2]]; !found {
+				return fmt.Errorf("unknown namespace prefix %q in query %q", match[2], query)
+			}
+		}
+	}
+
+	return nil
+}
+
 func splitLastPathElement(query string) []string {
 	// This is a rudimentary xpath-parser that splits the path
 	// into the last path element and the remaining path-part.
@@ -985,6 +985,75 @@ func TestParseMultiNodes(t *testing.T) {
 				),
 			},
 		},
+		{
+			name:  "select single device",
+			input: multipleNodesXML,
+			configs: []Config{
+				{
+					Selection: "/Device[@name='Device 3']",
+					Timestamp: "/Timestamp/@value",
+					Fields: map[string]string{
+						"value": "number(Value)",
+					},
+					Tags: map[string]string{
+						"name": "@name",
+					},
+				},
+			},
+			defaultTags: map[string]string{},
+			expected: []telegraf.Metric{
+				testutil.MustMetric(
+					"test",
+					map[string]string{
+						"name": "Device 3",
+					},
+					map[string]interface{}{
+						"value": 42.2,
+					},
+					time.Unix(1577923199, 0),
+				),
+			},
+		},
+		{
+			name:  "select descendant devices with name per node",
+			input: multipleNodesXML,
+			configs: []Config{
+				{
+					Selection:   "//Device[State='failed']",
+					MetricQuery: "concat('device_', State)",
+					Timestamp:   "/Timestamp/@value",
+					Fields: map[string]string{
+						"value": "number(Value)",
+					},
+					Tags: map[string]string{
+						"name": "@name",
+					},
+				},
+			},
+			defaultTags: map[string]string{},
+			expected: []telegraf.Metric{
+				testutil.MustMetric(
+					"device_failed",
+					map[string]string{
+						"name": "Device 4",
+					},
+					map[string]interface{}{
+						"value": 42.3,
+					},
+					time.Unix(1577923199, 0),
+				),
+				testutil.MustMetric(
+					"device_failed",
+					map[string]string{
+						"name": "Device 5",
+					},
+					map[string]interface{}{
+						"value": 42.4,
+					},
+					time.Unix(1577923199, 0),
+				),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1117,6 +1186,166 @@ func TestParseErrors(t *testing.T) {
 	}
 }
 
+const namespacedXML = `<?xml version="1.0"?>
+<Envelope xmlns="http://example.com/envelope" xmlns:s="http://example.com/sensors">
+  <Body>
+    <s:Reading s:unit="celsius">
+      <s:Name>kitchen</s:Name>
+      <s:Temperature>21.5</s:Temperature>
+    </s:Reading>
+  </Body>
+</Envelope>
+`
+
+func TestParseNamespaces(t *testing.T) {
+	parser := &Parser{
+		DefaultMetricName: "test",
+		Namespaces: map[string]string{
+			"env":     "http://example.com/envelope",
+			"sensors": "http://example.com/sensors",
+		},
+		Configs: []Config{
+			{
+				Selection: "/env:Envelope/env:Body/sensors:Reading",
+				Tags: map[string]string{
+					"name": "sensors:Name",
+					"unit": "@sensors:unit",
+				},
+				Fields: map[string]string{
+					"temperature": "number(sensors:Temperature)",
+				},
+			},
+		},
+		Log: testutil.Logger{Name: "parsers.xml"},
+	}
+	require.NoError(t, parser.Init())
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{
+				"name": "kitchen",
+				"unit": "celsius",
+			},
+			map[string]interface{}{
+				"temperature": 21.5,
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	actual, err := parser.Parse([]byte(namespacedXML))
+	require.NoError(t, err)
+	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
+}
+
+func TestParseNamespacesUnprefixed(t *testing.T) {
+	parser := &Parser{
+		DefaultMetricName:   "test",
+		AllowEmptySelection: true,
+		Configs: []Config{
+			{
+				Selection: "//Reading",
+				Fields: map[string]string{
+					"temperature": "number(Temperature)",
+				},
+			},
+		},
+		Log: testutil.Logger{Name: "parsers.xml"},
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.Parse([]byte(namespacedXML))
+	require.NoError(t, err)
+	require.Empty(t, actual)
+}
+
+func TestParseNamespacesInvalid(t *testing.T) {
+	var tests = []struct {
+		name     string
+		format   string
+		configs  []Config
+		expected string
+	}{
+		{
+			name: "undeclared prefix",
+			configs: []Config{
+				{
+					Selection: "/env:Envelope/env:Body/foo:Reading",
+					Fields: map[string]string{
+						"temperature": "number(sensors:Temperature)",
+					},
+				},
+			},
+			expected: `invalid query in config 1: unknown namespace prefix "foo" in query "/env:Envelope/env:Body/foo:Reading"`,
+		},
+		{
+			name: "undeclared prefix in field",
+			configs: []Config{
+				{
+					Selection: "/env:Envelope/env:Body/sensors:Reading",
+					Fields: map[string]string{
+						"temperature": "number(x:Temperature)",
+					},
+				},
+			},
+			expected: `invalid query in config 1: unknown namespace prefix "x" in query "number(x:Temperature)"`,
+		},
+		{
+			name:   "non-xml format",
+			format: "xpath_json",
+			configs: []Config{
+				{
+					Selection: "/env:Envelope",
+				},
+			},
+			expected: `namespaces are not supported for data-format "xpath_json"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &Parser{
+				Format:            tt.format,
+				DefaultMetricName: "test",
+				Namespaces: map[string]string{
+					"env":     "http://example.com/envelope",
+					"sensors": "http://example.com/sensors",
+				},
+				Configs: tt.configs,
+				Log:     testutil.Logger{Name: "parsers.xml"},
+			}
+			require.EqualError(t, parser.Init(), tt.expected)
+		})
+	}
+}
+
+func TestParseNamespacesIgnoresStringLiterals(t *testing.T) {
+	parser := &Parser{
+		DefaultMetricName: "test",
+		Namespaces: map[string]string{
+			"sensors": "http://example.com/sensors",
+		},
+		Configs: []Config{
+			{
+				Selection:   "//sensors:Reading",
+				MetricQuery: "'urn:test'",
+				Fields: map[string]string{
+					"ok": "child::sensors:Name = 'kitchen'",
+				},
+			},
+		},
+		Log: testutil.Logger{Name: "parsers.xml"},
+	}
+	require.NoError(t, parser.Init())
+
+	actual, err := parser.Parse([]byte(namespacedXML))
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, "urn:test", actual[0].Name())
+	require.Equal(t, map[string]interface{}{"ok": true}, actual[0].Fields())
+}
+
 func TestEmptySelection(t *testing.T) {
 	var tests = []struct {
 		name    string
@@ -1257,8 +1486,9 @@ func TestEmptySelectionAllowed(t *testing.T) {
 			}
 			require.NoError(t, parser.Init())
 
-			_, err := parser.Parse([]byte(tt.input))
+			actual, err := parser.Parse([]byte(tt.input))
 			require.NoError(t, err)
+			require.Empty(t, actual)
 		})
 	}
 }
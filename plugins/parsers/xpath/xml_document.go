@@ -7,7 +7,9 @@ import (
 	path "github.com/antchfx/xpath"
 )
 
-type xmlDocument struct{}
+type xmlDocument struct {
+	namespaces map[string]string
+}
 
 func (d *xmlDocument) Parse(buf []byte) (dataNode, error) {
 	return xmlquery.Parse(strings.NewReader(string(buf)))
@@ -15,9 +17,19 @@ func (d *xmlDocument) Parse(buf []byte) (dataNode, error) {
 
 func (d *xmlDocument) QueryAll(node dataNode, expr string) ([]dataNode, error) {
 	// If this panics it's a programming error as we changed the document type while processing
-	native, err := xmlquery.QueryAll(node.(*xmlquery.Node), expr)
-	if err != nil {
-		return nil, err
+	var native []*xmlquery.Node
+	if len(d.namespaces) > 0 {
+		compiled, err := path.CompileWithNS(expr, d.namespaces)
+		if err != nil {
+			return nil, err
+		}
+		native = xmlquery.QuerySelectorAll(node.(*xmlquery.Node), compiled)
+	} else {
+		var err error
+		native, err = xmlquery.QueryAll(node.(*xmlquery.Node), expr)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	nodes := make([]dataNode, 0, len(native))
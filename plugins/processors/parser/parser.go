@@ -6,34 +6,254 @@ import (
 	_ "embed"
 	"encoding/base64"
 	gobin "encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/url"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/models"
 	"github.com/influxdata/telegraf/plugins/processors"
 )
 
 //go:embed sample.conf
 var sampleConfig string
 
+// sourceFieldName is a placeholder for parsed field names replaced by the
+// key of the parsed field or tag, e.g. for the value parser's field name
+const sourceFieldName = "{{source}}"
+
+// missingTime is the time parsers supporting a time function use for metrics
+// without a timestamp if a timestamp fallback is configured. The day is far
+// off any sensible timestamp and aligned, so parsers rounding or adjusting
+// the time still produce a time within this day.
+var missingTime = time.Date(1678, time.January, 1, 0, 0, 0, 0, time.UTC)
+
 type Parser struct {
-	DropOriginal bool            `toml:"drop_original"`
-	Merge        string          `toml:"merge"`
-	ParseFields  []string        `toml:"parse_fields"`
-	Base64Fields []string        `toml:"parse_fields_base64"`
-	ParseTags    []string        `toml:"parse_tags"`
-	Log          telegraf.Logger `toml:"-"`
-	parser       telegraf.Parser
+	DropOriginal        bool            `toml:"drop_original"`
+	Merge               string          `toml:"merge"`
+	ParseFields         []string        `toml:"parse_fields"`
+	Base64Fields        []string        `toml:"parse_fields_base64"`
+	GzipFields          []string        `toml:"parse_fields_gzip"`
+	HexFields           []string        `toml:"parse_fields_hex"`
+	ParseTags           []string        `toml:"parse_tags"`
+	MetricOnError       bool            `toml:"metric_on_error"`
+	FieldPrefix         string          `toml:"field_prefix"`
+	TagPrefix           string          `toml:"tag_prefix"`
+	URLDecodeFields     []string        `toml:"parse_fields_urldecode"`
+	MeasurementTemplate string          `toml:"measurement_template"`
+	DryRun              bool            `toml:"dry_run"`
+	ParsedTagsAsTags    bool            `toml:"parsed_tags_as_tags"`
+	MaxDepth            int             `toml:"max_depth"`
+	EmitDuration        bool            `toml:"emit_duration"`
+	Condition           string          `toml:"condition"`
+	CharacterEncoding   string          `toml:"character_encoding"`
+	MergeTags           string          `toml:"merge_tags"`
+	MergeFields         string          `toml:"merge_fields"`
+	SkipOnInitError     bool            `toml:"skip_on_init_error"`
+	ExcludeFields       []string        `toml:"exclude_fields"`
+	ExcludeTags         []string        `toml:"exclude_tags"`
+	DropOnEmpty         bool            `toml:"drop_on_empty"`
+	TimestampFallback   string          `toml:"timestamp_fallback"`
+	CoerceToString      bool            `toml:"coerce_to_string"`
+	LogErrorInterval    config.Duration `toml:"log_error_interval"`
+	AutoDecompress      bool            `toml:"auto_decompress"`
+	SourceFieldTag      string          `toml:"source_field_tag"`
+	FailureMeasurement  string          `toml:"failure_measurement"`
+	MaxMetrics          int             `toml:"max_metrics"`
+	PromoteToTags       []string        `toml:"promote_to_tags"`
+	Log                 telegraf.Logger `toml:"-"`
+
+	parser          telegraf.Parser
+	fieldParsers    map[string]telegraf.Parser
+	fallbackParsers []telegraf.Parser
+
+	parseFieldsFilter  filter.Filter
+	base64FieldsFilter filter.Filter
+	gzipFieldsFilter   filter.Filter
+	hexFieldsFilter    filter.Filter
+	urlFieldsFilter    filter.Filter
+	parseTagsFilter    filter.Filter
+	excludeFields      filter.Filter
+	excludeTags        filter.Filter
+	promoteToTags      filter.Filter
+
+	gzipDecoder *internal.GzipDecoder
+	zlibDecoder *internal.ZlibDecoder
+	zstdDecoder *internal.ZstdDecoder
+	charset     encoding.Encoding
+
+	measurementTmpl *template.Template
+	instances       map[telegraf.Parser]*parserInstance
+	timeFuncParsers map[telegraf.Parser]bool
+	cloneError      error
+	condition       *models.Filter
+	initError       error
+	errorLog        *errorLogger
+	flushing        *flushState
 }
 
 func (p *Parser) Init() error {
 	switch p.Merge {
-	case "", "override", "override-with-timestamp":
+	case "", "override", "override-with-timestamp", "keep-keys", "replace-timestamp-only":
 	default:
 		return fmt.Errorf("unrecognized merge value: %s", p.Merge)
 	}
 
+	for _, mode := range []string{p.MergeTags, p.MergeFields} {
+		switch mode {
+		case "", "keep", "override", "replace":
+		default:
+			return fmt.Errorf("unrecognized merge value: %s", mode)
+		}
+	}
+
+	// Use the legacy merge setting as default for the tag and field merge
+	// behavior if only one of them is set. Without it, merging the other
+	// keys would be enabled implicitly, so require both to be set instead.
+	if p.MergeTags != "" || p.MergeFields != "" {
+		var mode string
+		switch p.Merge {
+		case "":
+			if p.MergeTags == "" || p.MergeFields == "" {
+				return errors.New("merge must be set if only one of merge_tags or merge_fields is set")
+			}
+		case "override":
+			mode = "override"
+		case "keep-keys":
+			mode = "keep"
+		default:
+			return fmt.Errorf("merge %q cannot be combined with merge_tags or merge_fields", p.Merge)
+		}
+		if p.MergeTags == "" {
+			p.MergeTags = mode
+		}
+		if p.MergeFields == "" {
+			p.MergeFields = mode
+		}
+	}
+
+	switch p.TimestampFallback {
+	case "", "now", "original", "zero":
+	default:
+		return fmt.Errorf("unrecognized timestamp fallback: %s", p.TimestampFallback)
+	}
+
+	if p.MaxDepth < 0 {
+		return fmt.Errorf("invalid max depth %d", p.MaxDepth)
+	}
+
+	if p.MaxMetrics < 0 {
+		return fmt.Errorf("invalid max metrics %d", p.MaxMetrics)
+	}
+
+	// Compile the field and tag filters to allow glob patterns
+	var err error
+	p.parseFieldsFilter, err = filter.Compile(p.ParseFields)
+	if err != nil {
+		return fmt.Errorf("creating parse fields filter failed: %w", err)
+	}
+
+	p.base64FieldsFilter, err = filter.Compile(p.Base64Fields)
+	if err != nil {
+		return fmt.Errorf("creating base64 fields filter failed: %w", err)
+	}
+
+	p.gzipFieldsFilter, err = filter.Compile(p.GzipFields)
+	if err != nil {
+		return fmt.Errorf("creating gzip fields filter failed: %w", err)
+	}
+	if p.gzipFieldsFilter != nil || p.AutoDecompress {
+		p.gzipDecoder = internal.NewGzipDecoder()
+	}
+	if p.AutoDecompress {
+		p.zlibDecoder = internal.NewZlibDecoder()
+		p.zstdDecoder, err = internal.NewZstdDecoder()
+		if err != nil {
+			return fmt.Errorf("creating zstd decoder failed: %w", err)
+		}
+	}
+
+	p.hexFieldsFilter, err = filter.Compile(p.HexFields)
+	if err != nil {
+		return fmt.Errorf("creating hex fields filter failed: %w", err)
+	}
+
+	p.urlFieldsFilter, err = filter.Compile(p.URLDecodeFields)
+	if err != nil {
+		return fmt.Errorf("creating urldecode fields filter failed: %w", err)
+	}
+
+	p.parseTagsFilter, err = filter.Compile(p.ParseTags)
+	if err != nil {
+		return fmt.Errorf("creating parse tags filter failed: %w", err)
+	}
+
+	p.excludeFields, err = filter.Compile(p.ExcludeFields)
+	if err != nil {
+		return fmt.Errorf("creating exclude fields filter failed: %w", err)
+	}
+
+	p.excludeTags, err = filter.Compile(p.ExcludeTags)
+	if err != nil {
+		return fmt.Errorf("creating exclude tags filter failed: %w", err)
+	}
+
+	p.promoteToTags, err = filter.Compile(p.PromoteToTags)
+	if err != nil {
+		return fmt.Errorf("creating promote to tags filter failed: %w", err)
+	}
+
+	if p.CharacterEncoding != "" {
+		p.charset, err = htmlindex.Get(p.CharacterEncoding)
+		if err != nil {
+			return fmt.Errorf("unsupported character encoding %q: %w", p.CharacterEncoding, err)
+		}
+	}
+
+	if p.Condition != "" {
+		p.condition = &models.Filter{MetricPass: p.Condition}
+		if err := p.condition.Compile(); err != nil {
+			return fmt.Errorf("compiling condition failed: %w", err)
+		}
+	}
+
+	if p.MeasurementTemplate != "" {
+		p.measurementTmpl, err = template.New("measurement template").Parse(p.MeasurementTemplate)
+		if err != nil {
+			return fmt.Errorf("creating measurement template failed: %w", err)
+		}
+	}
+
+	if p.TimestampFallback != "" && p.TimestampFallback != "now" {
+		for _, parser := range p.allParsers() {
+			p.setTimeFunc(parser)
+		}
+	}
+
+	p.errorLog = &errorLogger{log: p.Log, interval: time.Duration(p.LogErrorInterval)}
+
+	if p.cloneError != nil {
+		return p.cloneError
+	}
+
+	if p.initError != nil {
+		p.Log.Errorf("Disabling processor as parser initialization failed: %v", p.initError)
+	}
+
 	return nil
 }
 
@@ -43,55 +263,344 @@ func (*Parser) SampleConfig() string {
 
 func (p *Parser) SetParser(parser telegraf.Parser) {
 	p.parser = parser
+	p.addInstance(parser)
+}
+
+func (p *Parser) SetFieldParser(field string, parser telegraf.Parser) {
+	if p.fieldParsers == nil {
+		p.fieldParsers = make(map[string]telegraf.Parser)
+	}
+	p.fieldParsers[field] = parser
+	p.addInstance(parser)
+}
+
+func (p *Parser) AddFallbackParser(parser telegraf.Parser) {
+	p.fallbackParsers = append(p.fallbackParsers, parser)
+	p.addInstance(parser)
+}
+
+// Start begins flushing data held back by parsers in the background, e.g.
+// multiline records exceeding their timeout, if any parser supports it.
+func (p *Parser) Start(acc telegraf.Accumulator) error {
+	if p.initError != nil || !slices.ContainsFunc(p.allParsers(), isFlushing) {
+		return nil
+	}
+
+	state := &flushState{
+		acc:     acc,
+		origins: make(map[string]*origin),
+		done:    make(chan struct{}),
+	}
+	p.flushing = state
+
+	state.wg.Add(1)
+	go func() {
+		defer state.wg.Done()
+
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-state.done:
+				return
+			case <-ticker.C:
+				for _, m := range p.flush(false) {
+					state.acc.AddMetric(m)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop flushes all data held back by parsers, reports the errors still
+// suppressed and releases the summary timer.
+func (p *Parser) Stop() {
+	if state := p.flushing; state != nil {
+		close(state.done)
+		state.wg.Wait()
+
+		for _, m := range p.flush(true) {
+			state.acc.AddMetric(m)
+		}
+		p.flushing = nil
+	}
+
+	if p.errorLog != nil {
+		p.errorLog.report()
+	}
+}
+
+// HandleParserInitError disables the processor if the initialization of a
+// parser failed and skipping such errors is enabled.
+func (p *Parser) HandleParserInitError(err error) error {
+	if !p.SkipOnInitError {
+		return err
+	}
+	p.initError = err
+	return nil
 }
 
 func (p *Parser) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
+	if p.initError != nil {
+		return metrics
+	}
+
 	results := []telegraf.Metric{}
 	for _, metric := range metrics {
+		// pass metrics not matching the condition unchanged
+		if p.condition != nil {
+			ok, err := p.condition.Select(metric)
+			if err != nil {
+				p.errorLog.Errorf("evaluating condition failed: %v", err)
+			}
+			if !ok || err != nil {
+				results = append(results, metric)
+				continue
+			}
+		}
+
+		parsed, failures, matched, parseErrors, elapsed := p.parse(metric)
+		if p.DryRun {
+			results = append(results, annotate(metric, parsed, matched, parseErrors))
+			continue
+		}
+
+		// keep the original metric if parsing succeeded without producing
+		// any metric, e.g. for empty payloads, to avoid losing data
+		keepOriginal := matched && len(parsed) == 0 && parseErrors == 0 && !p.DropOnEmpty
+
 		newMetrics := []telegraf.Metric{}
-		if !p.DropOriginal {
+		if !p.DropOriginal || keepOriginal {
+			if len(parsed) > 0 {
+				p.removeReplaced(metric)
+			}
 			newMetrics = append(newMetrics, metric)
 		} else {
 			metric.Drop()
 		}
+		newMetrics = append(newMetrics, parsed...)
 
-		// parse fields
-		for _, field := range metric.FieldList() {
-			plain := slices.Contains(p.ParseFields, field.Key)
-			b64 := slices.Contains(p.Base64Fields, field.Key)
+		if len(newMetrics) == 0 {
+			results = append(results, failures...)
+			continue
+		}
 
-			if !plain && !b64 {
-				continue
-			}
+		// attach the number of failures to the original metric or, in
+		// case the original is dropped, to the first parsed metric
+		if p.MetricOnError && matched {
+			newMetrics[0].AddField("parse_errors", parseErrors)
+		}
 
-			if plain && b64 {
-				p.Log.Errorf("field %s is listed in both parse fields and base64 fields; skipping", field.Key)
-				continue
+		// attach the time spent parsing in the same way as the failures
+		if p.EmitDuration && matched {
+			newMetrics[0].AddField("parse_duration_ns", elapsed.Nanoseconds())
+		}
+
+		merged := p.mergeMetrics(newMetrics)
+		if merged == nil {
+			// rename the parsed metrics only and pass the original metric
+			// unchanged
+			parsed := newMetrics
+			if !p.DropOriginal || keepOriginal {
+				parsed = newMetrics[1:]
+			}
+			for _, m := range parsed {
+				p.applyTemplate(m)
 			}
+			results = append(results, newMetrics...)
+			results = append(results, failures...)
+			continue
+		}
+		p.applyTemplate(merged)
+		results = append(results, merged)
+		results = append(results, failures...)
+	}
+	return results
+}
+
+// mergeMetrics merges the given metrics into the first one according to the
+// configured merge mode or returns nil if merging is disabled.
+func (p *Parser) mergeMetrics(metrics []telegraf.Metric) telegraf.Metric {
+	switch {
+	case p.MergeTags != "":
+		return mergeKeys(metrics[0], metrics[1:], p.MergeTags, p.MergeFields)
+	case p.Merge == "override":
+		return merge(metrics[0], metrics[1:])
+	case p.Merge == "override-with-timestamp":
+		return mergeWithTimestamp(metrics[0], metrics[1:])
+	case p.Merge == "keep-keys":
+		return mergeKeepKeys(metrics[0], metrics[1:])
+	case p.Merge == "replace-timestamp-only":
+		return mergeTimestampOnly(metrics[0], metrics[1:])
+	}
+	return nil
+}
 
-			value, err := p.toBytes(field.Value)
+// parse parses all matching fields and tags of the given metric and returns
+// the resulting metrics and the failure metrics, if configured, along with
+// whether any field or tag matched, the number of failures and the time spent
+// in the parser.
+func (p *Parser) parse(metric telegraf.Metric) (parsed, failures []telegraf.Metric, matched bool, parseErrors int64, elapsed time.Duration) {
+	// parse fields in a deterministic order independent of the field
+	// order of the incoming metric to get stable results when merging
+	fields := slices.Clone(metric.FieldList())
+	slices.SortFunc(fields, func(a, b *telegraf.Field) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	for _, field := range fields {
+		plain := p.parseFieldsFilter != nil && p.parseFieldsFilter.Match(field.Key)
+		b64 := p.base64FieldsFilter != nil && p.base64FieldsFilter.Match(field.Key)
+		gz := p.gzipFieldsFilter != nil && p.gzipFieldsFilter.Match(field.Key)
+		hexed := p.hexFieldsFilter != nil && p.hexFieldsFilter.Match(field.Key)
+		escaped := p.urlFieldsFilter != nil && p.urlFieldsFilter.Match(field.Key)
+
+		if !plain && !b64 && !gz && !hexed && !escaped {
+			continue
+		}
+		matched = true
+
+		// Do not parse any further data once the limit is reached
+		if p.full(parsed) {
+			p.errorLog.Errorf("parsing %q reached the maximum of %d metrics; skipping remaining data", metric.Name(), p.MaxMetrics)
+			return parsed, failures, matched, parseErrors, elapsed
+		}
+
+		if plain && b64 {
+			p.errorLog.Errorf("field %s is listed in both parse fields and base64 fields; skipping", field.Key)
+			parseErrors++
+			continue
+		}
+
+		if plain && gz {
+			p.errorLog.Errorf("field %s is listed in both parse fields and gzip fields; skipping", field.Key)
+			parseErrors++
+			continue
+		}
+
+		if plain && hexed {
+			p.errorLog.Errorf("field %s is listed in both parse fields and hex fields; skipping", field.Key)
+			parseErrors++
+			continue
+		}
+
+		if b64 && hexed {
+			p.errorLog.Errorf("field %s is listed in both base64 fields and hex fields; skipping", field.Key)
+			parseErrors++
+			continue
+		}
+
+		if plain && escaped {
+			p.errorLog.Errorf("field %s is listed in both parse fields and urldecode fields; skipping", field.Key)
+			parseErrors++
+			continue
+		}
+
+		value, err := p.toBytes(field.Value)
+		if err != nil {
+			p.errorLog.Errorf("could not convert field %s: %v; skipping", field.Key, err)
+			parseErrors++
+			failures = p.addFailure(failures, metric, field.Key, field.Value, err)
+			continue
+		}
+
+		steps := decodingSteps{urldecode: escaped, base64: b64, hex: hexed, gzip: gz}
+		value, err = p.decode(field.Key, value, steps)
+		if err != nil {
+			p.errorLog.Errorf("%v; skipping", err)
+			parseErrors++
+			failures = p.addFailure(failures, metric, field.Key, field.Value, err)
+			continue
+		}
+
+		if p.charset != nil {
+			value, err = p.charset.NewDecoder().Bytes(value)
 			if err != nil {
-				p.Log.Errorf("could not convert field %s: %v; skipping", field.Key, err)
+				p.errorLog.Errorf("could not convert field %s from %s: %v; skipping", field.Key, p.CharacterEncoding, err)
+				parseErrors++
+				failures = p.addFailure(failures, metric, field.Key, field.Value, err)
 				continue
 			}
+		}
 
-			if b64 {
-				decoded := make([]byte, base64.StdEncoding.DecodedLen(len(value)))
-				n, err := base64.StdEncoding.Decode(decoded, value)
-				if err != nil {
-					p.Log.Errorf("could not decode base64 field %s: %v; skipping", field.Key, err)
-					continue
-				}
-				value = decoded[:n]
+		parser, found := p.fieldParsers[field.Key]
+		if !found {
+			parser = p.parser
+		}
+
+		source := sourceID(metric, field.Key)
+		p.remember(source, metric, field.Key, parser, steps)
+		start := time.Now()
+		fromFieldMetric, used, skipped, err := p.parseChain(parser, source, value)
+		end := time.Now()
+		elapsed += end.Sub(start)
+		parseErrors += int64(skipped)
+		if err != nil {
+			p.errorLog.Errorf("could not parse field %s: %v", field.Key, err)
+			parseErrors++
+			failures = p.addFailure(failures, metric, field.Key, field.Value, err)
+			continue
+		}
+
+		var limited bool
+		fromFieldMetric, limited = p.limit(fromFieldMetric, len(parsed))
+
+		for _, m := range fromFieldMetric {
+			// The parser get the parent plugin's name as
+			// default measurement name. Thus, in case the
+			// parsed metric does not provide a name itself,
+			// the parser  will return 'parser' as we are in
+			// processors.parser. In those cases we want to
+			// keep the original metric name.
+			if m.Name() == "" || m.Name() == "parser" {
+				m.SetName(metric.Name())
 			}
+			p.fallbackTimestamp(m, metric, used, start, end)
+			renameSourceField(m, field.Key)
+			p.parseNested(m, parser, source, steps, 1)
+			p.promote(m)
+			p.exclude(m)
+			p.addPrefixes(m)
+			p.addSourceTag(m, field.Key)
+		}
+
+		// multiple parsed fields shouldn't create multiple
+		// metrics so we'll merge tags/fields down into one
+		// prior to returning.
+		parsed = append(parsed, fromFieldMetric...)
+
+		if limited {
+			p.errorLog.Errorf("parsing %q exceeded the maximum of %d metrics; skipping remaining data", metric.Name(), p.MaxMetrics)
+			return parsed, failures, matched, parseErrors, elapsed
+		}
+	}
 
-			fromFieldMetric, err := p.parser.Parse(value)
+	// parse tags
+	for _, tag := range metric.TagList() {
+		if p.parseTagsFilter != nil && p.parseTagsFilter.Match(tag.Key) {
+			matched = true
+			if p.full(parsed) {
+				p.errorLog.Errorf("parsing %q reached the maximum of %d metrics; skipping remaining data", metric.Name(), p.MaxMetrics)
+				break
+			}
+			source := sourceID(metric, tag.Key)
+			p.remember(source, metric, tag.Key, p.parser, decodingSteps{})
+			start := time.Now()
+			fromTagMetric, used, skipped, err := p.parseChain(p.parser, source, []byte(tag.Value))
+			end := time.Now()
+			elapsed += end.Sub(start)
+			parseErrors += int64(skipped)
 			if err != nil {
-				p.Log.Errorf("could not parse field %s: %v", field.Key, err)
-				continue
+				p.errorLog.Errorf("could not parse tag %s: %v", tag.Key, err)
+				parseErrors++
+				failures = p.addFailure(failures, metric, tag.Key, tag.Value, err)
 			}
 
-			for _, m := range fromFieldMetric {
+			var limited bool
+			fromTagMetric, limited = p.limit(fromTagMetric, len(parsed))
+
+			for _, m := range fromTagMetric {
 				// The parser get the parent plugin's name as
 				// default measurement name. Thus, in case the
 				// parsed metric does not provide a name itself,
@@ -101,53 +610,84 @@ func (p *Parser) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
 				if m.Name() == "" || m.Name() == "parser" {
 					m.SetName(metric.Name())
 				}
+				p.fallbackTimestamp(m, metric, used, start, end)
+				renameSourceField(m, tag.Key)
+				if p.ParsedTagsAsTags {
+					stringFieldsToTags(m)
+				}
+				p.promote(m)
+				p.exclude(m)
+				p.addPrefixes(m)
+				p.addSourceTag(m, tag.Key)
 			}
 
-			// multiple parsed fields shouldn't create multiple
-			// metrics so we'll merge tags/fields down into one
-			// prior to returning.
-			newMetrics = append(newMetrics, fromFieldMetric...)
+			parsed = append(parsed, fromTagMetric...)
+
+			if limited {
+				p.errorLog.Errorf("parsing %q exceeded the maximum of %d metrics; skipping remaining data", metric.Name(), p.MaxMetrics)
+				break
+			}
 		}
+	}
 
-		// parse tags
-		for _, key := range p.ParseTags {
-			if value, ok := metric.GetTag(key); ok {
-				fromTagMetric, err := p.parseValue(value)
-				if err != nil {
-					p.Log.Errorf("could not parse tag %s: %v", key, err)
-				}
+	return parsed, failures, matched, parseErrors, elapsed
+}
 
-				for _, m := range fromTagMetric {
-					// The parser get the parent plugin's name as
-					// default measurement name. Thus, in case the
-					// parsed metric does not provide a name itself,
-					// the parser  will return 'parser' as we are in
-					// processors.parser. In those cases we want to
-					// keep the original metric name.
-					if m.Name() == "" || m.Name() == "parser" {
-						m.SetName(metric.Name())
-					}
-				}
+// limit truncates the given parsed metrics to not exceed the maximum number
+// of metrics per input metric taking the given number of already parsed
+// metrics into account. The returned flag is true if metrics were removed.
+func (p *Parser) limit(metrics []telegraf.Metric, count int) ([]telegraf.Metric, bool) {
+	if p.MaxMetrics == 0 || count+len(metrics) <= p.MaxMetrics {
+		return metrics, false
+	}
+	return metrics[:p.MaxMetrics-count], true
+}
 
-				newMetrics = append(newMetrics, fromTagMetric...)
-			}
-		}
+// full returns true if the given parsed metrics reached the maximum number
+// of metrics per input metric.
+func (p *Parser) full(parsed []telegraf.Metric) bool {
+	return p.MaxMetrics > 0 && len(parsed) >= p.MaxMetrics
+}
 
-		if len(newMetrics) == 0 {
-			continue
-		}
+// addFailure appends a metric for the field or tag with the given key that
+// failed to parse to the given failures if a failure measurement is
+// configured. The metric carries the tags and timestamp of the original
+// metric, the key as "source" and the error as "error" tag as well as the
+// unparsed value as "raw" field.
+func (p *Parser) addFailure(failures []telegraf.Metric, original telegraf.Metric, key string, raw interface{}, err error) []telegraf.Metric {
+	if p.FailureMeasurement == "" {
+		return failures
+	}
 
-		if p.Merge == "override" {
-			results = append(results, merge(newMetrics[0], newMetrics[1:]))
-		} else if p.Merge == "override-with-timestamp" {
-			results = append(results, mergeWithTimestamp(newMetrics[0], newMetrics[1:]))
-		} else {
-			results = append(results, newMetrics...)
-		}
+	m := metric.New(p.FailureMeasurement, original.Tags(), map[string]interface{}{"raw": raw}, original.Time())
+	m.AddTag("source", key)
+	m.AddTag("error", err.Error())
+	return append(failures, m)
+}
+
+// annotate adds the parse status and a sample of the parsed data to the given
+// metric without otherwise modifying it. Metrics without any matching field
+// or tag are passed unchanged.
+func annotate(metric telegraf.Metric, parsed []telegraf.Metric, matched bool, parseErrors int64) telegraf.Metric {
+	if !matched {
+		return metric
 	}
-	return results
+
+	if parseErrors > 0 {
+		metric.AddTag("parse_status", "fail")
+	} else {
+		metric.AddTag("parse_status", "ok")
+	}
+
+	if len(parsed) > 0 {
+		metric.AddField("parse_sample", sample(parsed[0]))
+	}
+	return metric
 }
 
+// merge adds the fields and tags of the given metrics to the base metric. Fields
+// not present in the base metric are appended in the order of the metrics and
+// their field list, existing fields keep their position.
 func merge(base telegraf.Metric, metrics []telegraf.Metric) telegraf.Metric {
 	for _, metric := range metrics {
 		for _, field := range metric.FieldList() {
@@ -177,8 +717,617 @@ func mergeWithTimestamp(base telegraf.Metric, metrics []telegraf.Metric) telegra
 	return base
 }
 
-func (p *Parser) parseValue(value string) ([]telegraf.Metric, error) {
-	return p.parser.Parse([]byte(value))
+func mergeKeepKeys(base telegraf.Metric, metrics []telegraf.Metric) telegraf.Metric {
+	for _, metric := range metrics {
+		for _, field := range metric.FieldList() {
+			if !base.HasField(field.Key) {
+				base.AddField(field.Key, field.Value)
+			}
+		}
+		for _, tag := range metric.TagList() {
+			if !base.HasTag(tag.Key) {
+				base.AddTag(tag.Key, tag.Value)
+			}
+		}
+	}
+	return base
+}
+
+func mergeTimestampOnly(base telegraf.Metric, metrics []telegraf.Metric) telegraf.Metric {
+	for _, metric := range metrics {
+		if !metric.Time().IsZero() {
+			base.SetTime(metric.Time())
+		}
+	}
+	return base
+}
+
+// mergeKeys adds the tags and fields of the given metrics to the base metric
+// either keeping or overriding existing keys depending on the given modes.
+// Keys of the base metric to be replaced must be removed beforehand.
+func mergeKeys(base telegraf.Metric, metrics []telegraf.Metric, tagMode, fieldMode string) telegraf.Metric {
+	for _, metric := range metrics {
+		for _, field := range metric.FieldList() {
+			if fieldMode == "keep" && base.HasField(field.Key) {
+				continue
+			}
+			base.AddField(field.Key, field.Value)
+		}
+		for _, tag := range metric.TagList() {
+			if tagMode == "keep" && base.HasTag(tag.Key) {
+				continue
+			}
+			base.AddTag(tag.Key, tag.Value)
+		}
+		if fieldMode != "keep" {
+			base.SetName(metric.Name())
+		}
+	}
+	return base
+}
+
+// removeReplaced removes the tags and fields of the original metric if they
+// should be replaced by the parsed ones.
+func (p *Parser) removeReplaced(m telegraf.Metric) {
+	if p.MergeTags == "replace" {
+		for _, tag := range slices.Clone(m.TagList()) {
+			m.RemoveTag(tag.Key)
+		}
+	}
+	if p.MergeFields == "replace" {
+		for _, field := range slices.Clone(m.FieldList()) {
+			m.RemoveField(field.Key)
+		}
+	}
+}
+
+// fallbackTimestamp replaces the time of the parsed metric according to the
+// timestamp fallback setting if the parser did not extract a timestamp.
+// Parsers supporting a time function use the missing time in this case. For
+// other parsers, which use the current time, metrics with a time within the
+// given parsing interval are considered to lack their own timestamp.
+func (p *Parser) fallbackTimestamp(m, original telegraf.Metric, parser telegraf.Parser, start, end time.Time) {
+	if p.TimestampFallback == "" || p.TimestampFallback == "now" {
+		return
+	}
+	if p.timeFuncParsers[parser] {
+		if m.Time().Before(missingTime) || !m.Time().Before(missingTime.AddDate(0, 0, 1)) {
+			return
+		}
+	} else if m.Time().Before(start) || m.Time().After(end) {
+		return
+	}
+
+	switch p.TimestampFallback {
+	case "original":
+		m.SetTime(original.Time())
+	case "zero":
+		m.SetTime(time.Unix(0, 0))
+	}
+}
+
+// exclude removes the fields and tags matching the exclude filters from
+// the given parsed metric.
+func (p *Parser) exclude(m telegraf.Metric) {
+	if p.excludeFields != nil {
+		for _, field := range slices.Clone(m.FieldList()) {
+			if p.excludeFields.Match(field.Key) {
+				m.RemoveField(field.Key)
+			}
+		}
+	}
+	if p.excludeTags != nil {
+		for _, tag := range slices.Clone(m.TagList()) {
+			if p.excludeTags.Match(tag.Key) {
+				m.RemoveTag(tag.Key)
+			}
+		}
+	}
+}
+
+// promote moves the fields matching the promote filter to tags of the given
+// parsed metric converting non-string values to strings. Fields which cannot
+// be converted are kept.
+func (p *Parser) promote(m telegraf.Metric) {
+	if p.promoteToTags == nil {
+		return
+	}
+	for _, field := range slices.Clone(m.FieldList()) {
+		if !p.promoteToTags.Match(field.Key) {
+			continue
+		}
+		v, err := internal.ToString(field.Value)
+		if err != nil {
+			p.errorLog.Errorf("could not convert field %s to tag: %v", field.Key, err)
+			continue
+		}
+		m.RemoveField(field.Key)
+		m.AddTag(field.Key, v)
+	}
+}
+
+// addSourceTag adds the name of the field or tag the given metric was parsed
+// from as tag if configured.
+func (p *Parser) addSourceTag(m telegraf.Metric, source string) {
+	if p.SourceFieldTag != "" {
+		m.AddTag(p.SourceFieldTag, source)
+	}
+}
+
+// addPrefixes prepends the configured prefixes to the field and tag keys of
+// the given parsed metric.
+func (p *Parser) addPrefixes(m telegraf.Metric) {
+	if p.FieldPrefix != "" {
+		for _, field := range slices.Clone(m.FieldList()) {
+			m.RemoveField(field.Key)
+			m.AddField(p.FieldPrefix+field.Key, field.Value)
+		}
+	}
+	if p.TagPrefix != "" {
+		for _, tag := range slices.Clone(m.TagList()) {
+			m.RemoveTag(tag.Key)
+			m.AddTag(p.TagPrefix+tag.Key, tag.Value)
+		}
+	}
+}
+
+// decodingSteps are the decoding steps applied to a field before parsing
+type decodingSteps struct {
+	urldecode bool
+	base64    bool
+	hex       bool
+	gzip      bool
+}
+
+// decode applies the given decoding steps to the value of the field with the
+// given key in the order URL, base64 or hex, and gzip decoding followed by
+// the automatic decompression if enabled.
+func (p *Parser) decode(key string, value []byte, steps decodingSteps) ([]byte, error) {
+	if steps.urldecode {
+		decoded, err := url.PathUnescape(string(value))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode urlencoded field %s: %w", key, err)
+		}
+		value = []byte(decoded)
+	}
+
+	if steps.base64 {
+		decoded := make([]byte, base64.StdEncoding.DecodedLen(len(value)))
+		n, err := base64.StdEncoding.Decode(decoded, value)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode base64 field %s: %w", key, err)
+		}
+		value = decoded[:n]
+	}
+
+	if steps.hex {
+		decoded := make([]byte, hex.DecodedLen(len(value)))
+		n, err := hex.Decode(decoded, value)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode hex field %s: %w", key, err)
+		}
+		value = decoded[:n]
+	}
+
+	if steps.gzip {
+		decoded, err := p.gzipDecoder.Decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode gzip field %s: %w", key, err)
+		}
+		value = decoded
+	}
+
+	if p.AutoDecompress {
+		return p.decompress(key, value)
+	}
+
+	return value, nil
+}
+
+// decompress decompresses the value if it starts with the magic bytes of a
+// known compression format and returns the value unchanged otherwise.
+func (p *Parser) decompress(key string, value []byte) ([]byte, error) {
+	var decoder internal.ContentDecoder
+	var format string
+	switch {
+	case bytes.HasPrefix(value, []byte{0x1f, 0x8b}):
+		decoder, format = p.gzipDecoder, "gzip"
+	case bytes.HasPrefix(value, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		decoder, format = p.zstdDecoder, "zstd"
+	case len(value) > 1 && value[0] == 0x78 && (uint16(value[0])<<8|uint16(value[1]))%31 == 0:
+		// Deflate with 32k window and a valid header checksum
+		decoder, format = p.zlibDecoder, "zlib"
+	default:
+		return value, nil
+	}
+
+	decoded, err := decoder.Decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress %s field %s: %w", format, key, err)
+	}
+	return decoded, nil
+}
+
+// parseNested parses the string fields of the given parsed metric again
+// using the parser and decoding steps of the original field. Each field
+// containing an encoded payload is replaced by the fields and tags of the
+// payload prefixed with the field name. Parsing stops at the configured
+// maximum depth or if parsing does not change the value anymore.
+func (p *Parser) parseNested(m telegraf.Metric, parser telegraf.Parser, source string, steps decodingSteps, depth int) {
+	if depth >= p.MaxDepth {
+		return
+	}
+
+	for _, field := range slices.Clone(m.FieldList()) {
+		v, ok := field.Value.(string)
+		if !ok {
+			continue
+		}
+
+		// Values failing to decode or parse are plain strings, not nested
+		// payloads, so keep those as they are
+		value, err := p.decode(field.Key, []byte(v), steps)
+		if err != nil {
+			continue
+		}
+		nested, _, err := p.parseWith(parser, source+"/"+field.Key, value)
+		if err != nil || len(nested) == 0 {
+			continue
+		}
+
+		// Avoid cycles for parsers reproducing their input, e.g. the value
+		// parser for strings
+		if reproduces(nested, v) {
+			continue
+		}
+
+		m.RemoveField(field.Key)
+		for _, n := range nested {
+			p.parseNested(n, parser, source+"/"+field.Key, steps, depth+1)
+			for _, f := range n.FieldList() {
+				m.AddField(field.Key+"_"+f.Key, f.Value)
+			}
+			for _, t := range n.TagList() {
+				m.AddTag(field.Key+"_"+t.Key, t.Value)
+			}
+		}
+	}
+}
+
+// reproduces returns true if any of the given metrics contains a field with
+// the given string value, i.e. parsing did not make any progress.
+func reproduces(metrics []telegraf.Metric, value string) bool {
+	for _, m := range metrics {
+		for _, field := range m.FieldList() {
+			if v, ok := field.Value.(string); ok && v == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyTemplate sets the name of the given metric to the result of the
+// measurement template if configured. The name is kept on errors.
+func (p *Parser) applyTemplate(m telegraf.Metric) {
+	if p.measurementTmpl == nil {
+		return
+	}
+
+	raw := m
+	if wm, ok := m.(telegraf.UnwrappableMetric); ok {
+		raw = wm.Unwrap()
+	}
+	tm, ok := raw.(telegraf.TemplateMetric)
+	if !ok {
+		p.errorLog.Errorf("metric of type %T is not a template metric", m)
+		return
+	}
+
+	var b strings.Builder
+	if err := p.measurementTmpl.Execute(&b, tm); err != nil {
+		p.errorLog.Errorf("failed to execute measurement template: %v", err)
+		return
+	}
+	if b.Len() == 0 {
+		p.errorLog.Errorf("measurement template for %q resulted in an empty name", m.Name())
+		return
+	}
+	m.SetName(b.String())
+}
+
+// renameSourceField renames the field named by the source placeholder of the
+// given parsed metric to the key of the parsed field or tag.
+func renameSourceField(m telegraf.Metric, key string) {
+	if v, found := m.GetField(sourceFieldName); found {
+		m.RemoveField(sourceFieldName)
+		m.AddField(key, v)
+	}
+}
+
+// stringFieldsToTags converts all string fields of the given metric to tags
+// keeping all other fields.
+func stringFieldsToTags(m telegraf.Metric) {
+	for _, field := range slices.Clone(m.FieldList()) {
+		if v, ok := field.Value.(string); ok {
+			m.RemoveField(field.Key)
+			m.AddTag(field.Key, v)
+		}
+	}
+}
+
+// sample returns a human-readable representation of the name, tags and
+// fields of the given metric.
+func sample(m telegraf.Metric) string {
+	parts := make([]string, 0, 1+len(m.TagList())+len(m.FieldList()))
+	parts = append(parts, m.Name())
+	for _, tag := range m.TagList() {
+		parts = append(parts, tag.Key+"="+tag.Value)
+	}
+	for _, field := range m.FieldList() {
+		parts = append(parts, fmt.Sprintf("%s=%v", field.Key, field.Value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseChain parses the given data using the parser and, if parsing fails,
+// tries the fallback parsers in order. The result of the first successful
+// parser is returned along with that parser. If all parsers fail, the error
+// of the given parser is returned.
+func (p *Parser) parseChain(parser telegraf.Parser, source string, data []byte) ([]telegraf.Metric, telegraf.Parser, int, error) {
+	metrics, skipped, err := p.parseWith(parser, source, data)
+	if err == nil {
+		return metrics, parser, skipped, nil
+	}
+
+	for _, fallback := range p.fallbackParsers {
+		if m, skipped, ferr := p.parseWith(fallback, source, data); ferr == nil {
+			return m, fallback, skipped, nil
+		}
+	}
+	return nil, parser, 0, err
+}
+
+// parseWith parses the given data using the parser or, for non-reentrant
+// parsers, the instance of the parser exclusively used by this processor.
+// Parsers keeping state per source get the given source of the data. The
+// number of rows skipped by the parser is returned along with the metrics.
+func (p *Parser) parseWith(parser telegraf.Parser, source string, data []byte) ([]telegraf.Metric, int, error) {
+	instance, found := p.instances[parser]
+	if !found {
+		return parseSource(parser, source, data)
+	}
+
+	instance.Lock()
+	defer instance.Unlock()
+	return parseSource(instance.parser, source, data)
+}
+
+func parseSource(parser telegraf.Parser, source string, data []byte) ([]telegraf.Metric, int, error) {
+	var metrics []telegraf.Metric
+	var err error
+	if sp, ok := parser.(telegraf.SourceParser); ok {
+		metrics, err = sp.ParseSource(source, data)
+	} else {
+		metrics, err = parser.Parse(data)
+	}
+
+	var skipped int
+	if sp, ok := unwrapParser(parser).(skippingParser); ok {
+		skipped = sp.SkippedRows()
+	}
+	return metrics, skipped, err
+}
+
+// skippingParser is implemented by parsers skipping malformed rows of the
+// data instead of failing, e.g. the CSV parser with skip errors enabled.
+type skippingParser interface {
+	SkippedRows() int
+}
+
+// sourceID identifies the given field or tag of the series of the metric,
+// so state kept by parsers is never shared across fields or series.
+func sourceID(m telegraf.Metric, key string) string {
+	return strconv.FormatUint(m.HashID(), 16) + "/" + key
+}
+
+// flushInterval is the interval for checking parsers for data held back
+// longer than the parser's timeout.
+const flushInterval = time.Second
+
+// flushState contains the state of flushing data held back by parsers and
+// the origins of the data of each source.
+type flushState struct {
+	acc     telegraf.Accumulator
+	origins map[string]*origin
+	done    chan struct{}
+	wg      sync.WaitGroup
+	sync.Mutex
+}
+
+// origin describes the field or tag of a metric the data of a source was
+// taken from, so metrics flushed later can be processed like parsed ones.
+type origin struct {
+	metric telegraf.Metric
+	key    string
+	parser telegraf.Parser
+	steps  decodingSteps
+}
+
+// isFlushing checks if the given parser might hold back data across calls.
+func isFlushing(parser telegraf.Parser) bool {
+	_, ok := unwrapParser(parser).(telegraf.FlushingParser)
+	return ok
+}
+
+// allParsers returns the configured parsers without duplicates.
+func (p *Parser) allParsers() []telegraf.Parser {
+	parsers := make([]telegraf.Parser, 0, len(p.fieldParsers)+len(p.fallbackParsers)+1)
+	add := func(parser telegraf.Parser) {
+		if parser != nil && !slices.Contains(parsers, parser) {
+			parsers = append(parsers, parser)
+		}
+	}
+
+	add(p.parser)
+	for _, parser := range p.fieldParsers {
+		add(parser)
+	}
+	for _, parser := range p.fallbackParsers {
+		add(parser)
+	}
+	return parsers
+}
+
+// remember records the origin of the data parsed for the given source while
+// flushing is active, omitting the fields to keep the memory footprint low.
+func (p *Parser) remember(source string, m telegraf.Metric, key string, parser telegraf.Parser, steps decodingSteps) {
+	state := p.flushing
+	if state == nil {
+		return
+	}
+
+	state.Lock()
+	defer state.Unlock()
+	state.origins[source] = &origin{
+		metric: metric.New(m.Name(), m.Tags(), nil, m.Time()),
+		key:    key,
+		parser: parser,
+		steps:  steps,
+	}
+}
+
+// flush collects the data held back by the parsers, or all of it if forced,
+// and processes the resulting metrics like parsed data of their origin.
+// Parsed fields of the original metric are not available anymore, so only
+// the tags, name and timestamp of the origin are merged.
+func (p *Parser) flush(force bool) []telegraf.Metric {
+	var results []telegraf.Metric
+	for _, parser := range p.allParsers() {
+		if !isFlushing(parser) {
+			continue
+		}
+
+		start := time.Now()
+		flushed := p.flushWith(parser, force)
+		end := time.Now()
+
+		for source, metrics := range flushed {
+			p.flushing.Lock()
+			o, found := p.flushing.origins[source]
+			delete(p.flushing.origins, source)
+			p.flushing.Unlock()
+			if !found {
+				results = append(results, metrics...)
+				continue
+			}
+
+			for _, m := range metrics {
+				if m.Name() == "" || m.Name() == "parser" {
+					m.SetName(o.metric.Name())
+				}
+				p.fallbackTimestamp(m, o.metric, parser, start, end)
+				renameSourceField(m, o.key)
+				p.parseNested(m, o.parser, source, o.steps, 1)
+				p.promote(m)
+				p.exclude(m)
+				p.addPrefixes(m)
+				p.addSourceTag(m, o.key)
+			}
+
+			merged := p.mergeMetrics(append([]telegraf.Metric{o.metric.Copy()}, metrics...))
+			if merged == nil {
+				for _, m := range metrics {
+					p.applyTemplate(m)
+				}
+				results = append(results, metrics...)
+				continue
+			}
+			if len(merged.FieldList()) > 0 {
+				p.applyTemplate(merged)
+				results = append(results, merged)
+			}
+		}
+	}
+	return results
+}
+
+// flushWith flushes the given parser using the exclusive instance of
+// non-reentrant parsers.
+func (p *Parser) flushWith(parser telegraf.Parser, force bool) map[string][]telegraf.Metric {
+	instance, found := p.instances[parser]
+	if !found {
+		return parser.(telegraf.FlushingParser).Flush(force)
+	}
+
+	instance.Lock()
+	defer instance.Unlock()
+	return instance.parser.(telegraf.FlushingParser).Flush(force)
+}
+
+// parserInstance is an instance of a non-reentrant parser exclusively used
+// by the processor. The lock serializes concurrent calls to Apply, so state
+// kept by the parser across calls, e.g. pending multiline records, is
+// preserved.
+type parserInstance struct {
+	parser telegraf.Parser
+	sync.Mutex
+}
+
+// setTimeFunc makes parsers supporting a time function use the missing time
+// for metrics without a timestamp, so those metrics are reliably detected
+// when a timestamp fallback is configured. The function is also set for the
+// exclusive instance of non-reentrant parsers.
+func (p *Parser) setTimeFunc(parser telegraf.Parser) {
+	// running parsers always forward the function, so check the wrapped parser
+	if _, ok := unwrapParser(parser).(telegraf.TimeFuncParser); !ok {
+		return
+	}
+
+	fn := func() time.Time { return missingTime }
+	parser.(telegraf.TimeFuncParser).SetTimeFunc(fn)
+	if instance, found := p.instances[parser]; found {
+		instance.parser.(telegraf.TimeFuncParser).SetTimeFunc(fn)
+	}
+
+	if p.timeFuncParsers == nil {
+		p.timeFuncParsers = make(map[telegraf.Parser]bool)
+	}
+	p.timeFuncParsers[parser] = true
+}
+
+// unwrapParser returns the parser wrapped by a running parser, so the
+// capabilities of the actual parser implementation can be checked.
+func unwrapParser(parser telegraf.Parser) telegraf.Parser {
+	if unwrapped, ok := parser.(*models.RunningParser); ok {
+		return unwrapped.Parser
+	}
+	return parser
+}
+
+// addInstance creates the exclusive instance for non-reentrant parsers.
+// Cloning errors are returned by Init, so parsers must be set before
+// initializing the processor.
+func (p *Parser) addInstance(parser telegraf.Parser) {
+	// running parsers always provide cloning, so check the wrapped parser
+	if _, ok := unwrapParser(parser).(telegraf.NonReentrantParser); !ok {
+		return
+	}
+
+	if _, found := p.instances[parser]; found {
+		return
+	}
+
+	instance, err := parser.(telegraf.NonReentrantParser).Clone()
+	if err != nil {
+		p.cloneError = errors.Join(p.cloneError, fmt.Errorf("cloning parser failed: %w", err))
+		return
+	}
+
+	if p.instances == nil {
+		p.instances = make(map[telegraf.Parser]*parserInstance)
+	}
+	p.instances[parser] = &parserInstance{parser: instance}
 }
 
 func (p *Parser) toBytes(value interface{}) ([]byte, error) {
@@ -186,6 +1335,10 @@ func (p *Parser) toBytes(value interface{}) ([]byte, error) {
 		return []byte(v), nil
 	}
 
+	if p.CoerceToString {
+		return []byte(fmt.Sprint(value)), nil
+	}
+
 	var buf bytes.Buffer
 	if err := gobin.Write(&buf, internal.HostEndianness, value); err != nil {
 		return nil, err
@@ -194,6 +1347,64 @@ func (p *Parser) toBytes(value interface{}) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// errorLogger coalesces errors occurring repeatedly, e.g. for every metric,
+// by logging at most one error per interval. The number of errors
+// suppressed since the last logged error is reported at the end of the
+// interval, even if no further errors occur. A zero interval logs all errors.
+type errorLogger struct {
+	log      telegraf.Logger
+	interval time.Duration
+
+	last       time.Time
+	suppressed int
+	flush      *time.Timer
+	mu         sync.Mutex
+}
+
+func (l *errorLogger) Errorf(format string, args ...interface{}) {
+	if l.interval <= 0 {
+		l.log.Errorf(format, args...)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !l.last.IsZero() && now.Sub(l.last) < l.interval {
+		l.suppressed++
+		if l.flush == nil {
+			l.flush = time.AfterFunc(l.last.Add(l.interval).Sub(now), l.report)
+		}
+		return
+	}
+
+	l.reportSuppressed(now)
+	l.log.Errorf(format, args...)
+	l.last = now
+}
+
+// report logs the number of suppressed errors at the end of the interval.
+func (l *errorLogger) report() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reportSuppressed(time.Now())
+}
+
+// reportSuppressed logs and resets the number of suppressed errors, the
+// caller must hold the lock.
+func (l *errorLogger) reportSuppressed(now time.Time) {
+	if l.flush != nil {
+		l.flush.Stop()
+		l.flush = nil
+	}
+	if l.suppressed > 0 {
+		l.log.Errorf("%d error(s) suppressed in the last %s", l.suppressed, now.Sub(l.last).Truncate(time.Millisecond))
+	}
+	l.suppressed = 0
+}
+
 func init() {
 	processors.Add("parser", func() telegraf.Processor {
 		return &Parser{DropOriginal: false}
@@ -1,21 +1,33 @@
 package parser
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/parsers"
 	"github.com/influxdata/telegraf/plugins/parsers/binary"
+	"github.com/influxdata/telegraf/plugins/parsers/graphite"
 	"github.com/influxdata/telegraf/plugins/parsers/grok"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
 	"github.com/influxdata/telegraf/plugins/parsers/json"
 	"github.com/influxdata/telegraf/plugins/parsers/logfmt"
+	"github.com/influxdata/telegraf/plugins/parsers/protobuf"
+	"github.com/influxdata/telegraf/plugins/parsers/syslog"
 	"github.com/influxdata/telegraf/plugins/parsers/value"
+	"github.com/influxdata/telegraf/plugins/processors"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -25,6 +37,7 @@ func TestApply(t *testing.T) {
 		parseFields  []string
 		parseTags    []string
 		parseBase64  []string
+		parseHex     []string
 		parser       telegraf.Parser
 		dropOriginal bool
 		merge        string
@@ -177,6 +190,113 @@ func TestApply(t *testing.T) {
 					time.Unix(0, 0)),
 			},
 		},
+		{
+			name:         "parse multi-line field keep",
+			parseFields:  []string{"message"},
+			parser:       &influx.Parser{},
+			dropOriginal: false,
+			input: metric.New(
+				"influxField",
+				map[string]string{},
+				map[string]interface{}{
+					"message": "cpu,host=a usage=1 1530654676316265790\nmem,host=a free=2 1530654676316265790",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"influxField",
+					map[string]string{},
+					map[string]interface{}{
+						"message": "cpu,host=a usage=1 1530654676316265790\nmem,host=a free=2 1530654676316265790",
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"usage": float64(1)},
+					time.Unix(0, 1530654676316265790)),
+				metric.New(
+					"mem",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"free": float64(2)},
+					time.Unix(0, 1530654676316265790)),
+			},
+		},
+		{
+			name:         "parse multi-line field drop",
+			parseFields:  []string{"message"},
+			parser:       &influx.Parser{},
+			dropOriginal: true,
+			input: metric.New(
+				"influxField",
+				map[string]string{},
+				map[string]interface{}{
+					"message": "cpu,host=a usage=1 1530654676316265790\nmem,host=a free=2 1530654676316265790",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"usage": float64(1)},
+					time.Unix(0, 1530654676316265790)),
+				metric.New(
+					"mem",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"free": float64(2)},
+					time.Unix(0, 1530654676316265790)),
+			},
+		},
+		{
+			name:         "parse multi-line field merge",
+			parseFields:  []string{"message"},
+			parser:       &influx.Parser{},
+			dropOriginal: false,
+			merge:        "override",
+			input: metric.New(
+				"influxField",
+				map[string]string{},
+				map[string]interface{}{
+					"message": "cpu,host=a usage=1 1530654676316265790\nmem,host=a free=2 1530654676316265790",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"mem",
+					map[string]string{"host": "a"},
+					map[string]interface{}{
+						"message": "cpu,host=a usage=1 1530654676316265790\nmem,host=a free=2 1530654676316265790",
+						"usage":   float64(1),
+						"free":    float64(2),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse one field keep with measurement name and precision",
+			parseFields:  []string{"message"},
+			parser:       &influx.Parser{TimestampTruncate: config.Duration(time.Second)},
+			dropOriginal: false,
+			merge:        "override-with-timestamp",
+			input: metric.New(
+				"influxField",
+				map[string]string{},
+				map[string]interface{}{
+					"message": "deal,computer_name=hosta message=\"stuff\" 1530654676316265790",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"deal",
+					map[string]string{
+						"computer_name": "hosta",
+					},
+					map[string]interface{}{
+						"message": "stuff",
+					},
+					time.Unix(1530654676, 0)),
+			},
+		},
 		{
 			name:         "parse one field override replaces name",
 			parseFields:  []string{"message"},
@@ -205,6 +325,62 @@ func TestApply(t *testing.T) {
 					time.Unix(0, 0)),
 			},
 		},
+		{
+			name:         "parse one field override with logfmt measurement key",
+			parseFields:  []string{"message"},
+			dropOriginal: false,
+			merge:        "override",
+			parser:       &logfmt.Parser{MeasurementKey: "event"},
+			input: metric.New(
+				"logfmtField",
+				map[string]string{
+					"some": "tag",
+				},
+				map[string]interface{}{
+					"message": "event=login user=alice",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"login",
+					map[string]string{
+						"some": "tag",
+					},
+					map[string]interface{}{
+						"message": "event=login user=alice",
+						"user":    "alice",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse one field override without logfmt measurement key",
+			parseFields:  []string{"message"},
+			dropOriginal: false,
+			merge:        "override",
+			parser:       &logfmt.Parser{MeasurementKey: "event"},
+			input: metric.New(
+				"logfmtField",
+				map[string]string{
+					"some": "tag",
+				},
+				map[string]interface{}{
+					"message": "user=alice",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"logfmtField",
+					map[string]string{
+						"some": "tag",
+					},
+					map[string]interface{}{
+						"message": "user=alice",
+						"user":    "alice",
+					},
+					time.Unix(0, 0)),
+			},
+		},
 		{
 			name:         "parse grok field",
 			parseFields:  []string{"grokSample"},
@@ -397,6 +573,33 @@ func TestApply(t *testing.T) {
 					time.Unix(0, 0)),
 			},
 		},
+		{
+			name:         "parse one field with tag keys and merge",
+			parseFields:  []string{"message"},
+			dropOriginal: true,
+			merge:        "override",
+			parser:       &logfmt.Parser{TagKeys: []string{"level", "host"}},
+			input: metric.New(
+				"singleField",
+				map[string]string{},
+				map[string]interface{}{
+					"message": `level=error host=example.org msg="connection lost" retries=3`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"singleField",
+					map[string]string{
+						"level": "error",
+						"host":  "example.org",
+					},
+					map[string]interface{}{
+						"msg":     "connection lost",
+						"retries": int64(3),
+					},
+					time.Unix(0, 0)),
+			},
+		},
 		{
 			name:         "parse one tag keep",
 			parseTags:    []string{"sample"},
@@ -631,6 +834,61 @@ func TestApply(t *testing.T) {
 					time.Unix(1593287020, 0)),
 			},
 		},
+		{
+			name:        "override with unix_auto timestamp",
+			parseFields: []string{"value"},
+			merge:       "override-with-timestamp",
+			parser: &json.Parser{
+				TimeKey:    "timestamp",
+				TimeFormat: "unix_auto",
+			},
+			input: metric.New(
+				"myname",
+				map[string]string{},
+				map[string]interface{}{
+					"value": `{"timestamp": 1593287020500, "value": 42.1}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"myname",
+					map[string]string{},
+					map[string]interface{}{
+						"value": float64(42.1),
+					},
+					time.Unix(1593287020, 500000000)),
+			},
+		},
+		{
+			name:        "replace timestamp only",
+			parseFields: []string{"value"},
+			merge:       "replace-timestamp-only",
+			parser: &json.Parser{
+				TimeKey:    "timestamp",
+				TimeFormat: "2006-01-02 15:04:05",
+				TagKeys:    []string{"host"},
+			},
+			input: metric.New(
+				"myname",
+				map[string]string{
+					"some": "tag",
+				},
+				map[string]interface{}{
+					"value": `{"timestamp": "2020-06-27 19:43:40", "value": 42.1, "host": "localhost"}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"myname",
+					map[string]string{
+						"some": "tag",
+					},
+					map[string]interface{}{
+						"value": `{"timestamp": "2020-06-27 19:43:40", "value": 42.1, "host": "localhost"}`,
+					},
+					time.Unix(1593287020, 0)),
+			},
+		},
 		{
 			name:        "non-string field with binary parser",
 			parseFields: []string{"value"},
@@ -709,6 +967,90 @@ func TestApply(t *testing.T) {
 					time.Unix(1593287020, 0)),
 			},
 		},
+		{
+			name:     "hex field with binary parser",
+			parseHex: []string{"value"},
+			merge:    "override",
+			parser: &binary.Parser{
+				Configs: []binary.Config{
+					{
+						MetricName: "parser",
+						EmitRaw:    true,
+						Entries: []binary.Entry{
+							{Name: "alarm_0", Type: "bool", Bits: 1},
+							{Name: "alarm_1", Type: "bool", Bits: 1},
+							{Name: "alarm_2", Type: "bool", Bits: 1},
+							{Name: "alarm_3", Type: "bool", Bits: 1},
+							{Name: "alarm_4", Type: "bool", Bits: 1},
+							{Name: "alarm_5", Type: "bool", Bits: 1},
+							{Name: "alarm_6", Type: "bool", Bits: 1},
+							{Name: "alarm_7", Type: "bool", Bits: 1},
+						},
+					},
+				},
+			},
+			input: metric.New(
+				"myname",
+				map[string]string{},
+				map[string]interface{}{
+					"value": "0D",
+				},
+				time.Unix(1593287020, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"myname",
+					map[string]string{},
+					map[string]interface{}{
+						"value":   "0D",
+						"raw_hex": "0d",
+						"alarm_0": false,
+						"alarm_1": false,
+						"alarm_2": false,
+						"alarm_3": false,
+						"alarm_4": true,
+						"alarm_5": true,
+						"alarm_6": false,
+						"alarm_7": true,
+					},
+					time.Unix(1593287020, 0)),
+			},
+		},
+		{
+			name:     "length-prefixed string field with binary parser",
+			parseHex: []string{"value"},
+			merge:    "override",
+			parser: &binary.Parser{
+				Configs: []binary.Config{
+					{
+						MetricName: "parser",
+						Entries: []binary.Entry{
+							{Name: "name_length", Type: "uint8", Omit: true},
+							{Name: "name", Type: "string", LengthRef: "name_length", Assignment: "tag"},
+							{Name: "status", Type: "uint8"},
+						},
+					},
+				},
+			},
+			input: metric.New(
+				"myname",
+				map[string]string{},
+				map[string]interface{}{
+					"value": "0568656C6C6F0D",
+				},
+				time.Unix(1593287020, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"myname",
+					map[string]string{
+						"name": "hello",
+					},
+					map[string]interface{}{
+						"value":  "0568656C6C6F0D",
+						"status": uint8(13),
+					},
+					time.Unix(1593287020, 0)),
+			},
+		},
 		{
 			name:         "test base 64 field single",
 			parseBase64:  []string{"sample"},
@@ -738,25 +1080,53 @@ func TestApply(t *testing.T) {
 			},
 		},
 		{
-			name:         "parse two base64 fields",
-			parseBase64:  []string{"field_1", "field_2"},
+			name:         "test base64 field with protobuf parser",
+			parseBase64:  []string{"payload"},
 			dropOriginal: true,
-			parser: &json.Parser{
-				TagKeys: []string{"lvl", "msg", "err", "fatal"},
+			parser: &protobuf.Parser{
+				DescriptorSet: "../../parsers/protobuf/testdata/sensor.pb",
+				MessageType:   "telegraf.test.Battery",
 			},
 			input: metric.New(
-				"bigMeasure",
-				map[string]string{},
+				"battery",
+				map[string]string{
+					"some": "tag",
+				},
 				map[string]interface{}{
-					"field_1": `eyJsdmwiOiJpbmZvIiwibXNnIjoiaHR0cCByZXF1ZXN0In0=`,
-					"field_2": `eyJlcnIiOiJmYXRhbCIsImZhdGFsIjoic2VjdXJpdHkgdGhyZWF0In0=`,
+					"payload": `CWZmZmZmZgpAEFA=`,
 				},
 				time.Unix(0, 0)),
 			expected: []telegraf.Metric{
 				metric.New(
-					"bigMeasure",
-					map[string]string{
-						"lvl": "info",
+					"battery",
+					map[string]string{},
+					map[string]interface{}{
+						"voltage": 3.3,
+						"level":   uint64(80),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse two base64 fields",
+			parseBase64:  []string{"field_1", "field_2"},
+			dropOriginal: true,
+			parser: &json.Parser{
+				TagKeys: []string{"lvl", "msg", "err", "fatal"},
+			},
+			input: metric.New(
+				"bigMeasure",
+				map[string]string{},
+				map[string]interface{}{
+					"field_1": `eyJsdmwiOiJpbmZvIiwibXNnIjoiaHR0cCByZXF1ZXN0In0=`,
+					"field_2": `eyJlcnIiOiJmYXRhbCIsImZhdGFsIjoic2VjdXJpdHkgdGhyZWF0In0=`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bigMeasure",
+					map[string]string{
+						"lvl": "info",
 						"msg": "http request",
 					},
 					map[string]interface{}{},
@@ -806,140 +1176,2801 @@ func TestApply(t *testing.T) {
 					time.Unix(0, 0)),
 			},
 		},
+		{
+			name:         "parse fields matching glob",
+			parseFields:  []string{"log_*"},
+			dropOriginal: true,
+			parser:       &logfmt.Parser{},
+			input: metric.New(
+				"globbed",
+				map[string]string{},
+				map[string]interface{}{
+					"log_0": `lvl=info`,
+					"log_1": `msg="http request"`,
+					"other": `lvl=error`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"globbed",
+					map[string]string{},
+					map[string]interface{}{
+						"lvl": "info",
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"globbed",
+					map[string]string{},
+					map[string]interface{}{
+						"msg": "http request",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse tags matching glob with merge",
+			parseTags:    []string{"payload.*"},
+			dropOriginal: false,
+			merge:        "override",
+			parser:       &logfmt.Parser{},
+			input: metric.New(
+				"globbed",
+				map[string]string{
+					"payload.a": "lvl=info",
+					"payload.b": "method=POST",
+				},
+				map[string]interface{}{},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"globbed",
+					map[string]string{
+						"payload.a": "lvl=info",
+						"payload.b": "method=POST",
+					},
+					map[string]interface{}{
+						"lvl":    "info",
+						"method": "POST",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse glob without matching field",
+			parseFields:  []string{"log_*"},
+			dropOriginal: false,
+			parser:       &logfmt.Parser{},
+			input: metric.New(
+				"globbed",
+				map[string]string{},
+				map[string]interface{}{
+					"message": `lvl=info`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"globbed",
+					map[string]string{},
+					map[string]interface{}{
+						"message": `lvl=info`,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse one field with keep-keys merge",
+			parseFields:  []string{"sample"},
+			dropOriginal: false,
+			merge:        "keep-keys",
+			parser:       &logfmt.Parser{},
+			input: metric.New(
+				"singleField",
+				map[string]string{
+					"some": "tag",
+				},
+				map[string]interface{}{
+					"lvl":    "error",
+					"sample": `lvl=info msg="http request"`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"singleField",
+					map[string]string{
+						"some": "tag",
+					},
+					map[string]interface{}{
+						"lvl":    "error",
+						"msg":    "http request",
+						"sample": `lvl=info msg="http request"`,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse two fields with keep-keys merge and drop original",
+			parseFields:  []string{"field_1", "field_2"},
+			dropOriginal: true,
+			merge:        "keep-keys",
+			parser:       &logfmt.Parser{},
+			input: metric.New(
+				"bigMeasure",
+				map[string]string{},
+				map[string]interface{}{
+					"field_1": `lvl=info msg="http request"`,
+					"field_2": `err=fatal`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bigMeasure",
+					map[string]string{},
+					map[string]interface{}{
+						"lvl": "info",
+						"msg": "http request",
+						"err": "fatal",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "parse syslog field with structured data",
+			parseFields:  []string{"line"},
+			dropOriginal: true,
+			parser:       &syslog.Parser{},
+			input: metric.New(
+				"transport",
+				map[string]string{"source": "queue"},
+				map[string]interface{}{
+					"line": `<165>1 2003-10-11T22:14:15.003Z mymachine evntslog - ID47 [exampleSDID@32473 iut="3" eventID="1011"] An application event`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"transport",
+					map[string]string{
+						"severity": "notice",
+						"facility": "local4",
+						"hostname": "mymachine",
+						"appname":  "evntslog",
+					},
+					map[string]interface{}{
+						"version":                   uint16(1),
+						"priority":                  165,
+						"facility_code":             20,
+						"severity_code":             5,
+						"msgid":                     "ID47",
+						"exampleSDID@32473_iut":     "3",
+						"exampleSDID@32473_eventID": "1011",
+						"message":                   "An application event",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, ok := tt.parser.(telegraf.Initializer); ok {
+				require.NoError(t, p.Init())
+			}
+			plugin := Parser{
+				ParseFields:  tt.parseFields,
+				ParseTags:    tt.parseTags,
+				Base64Fields: tt.parseBase64,
+				HexFields:    tt.parseHex,
+				DropOriginal: tt.dropOriginal,
+				Merge:        tt.merge,
+				Log:          testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(tt.parser)
+
+			output := plugin.Apply(tt.input)
+			t.Logf("Testing: %s", tt.name)
+
+			// check timestamp when using with-timestamp merge type
+			if tt.merge == "override-with-timestamp" || tt.merge == "replace-timestamp-only" {
+				testutil.RequireMetricsEqual(t, tt.expected, output, testutil.SortMetrics())
+			} else {
+				testutil.RequireMetricsEqual(t, tt.expected, output, testutil.SortMetrics(), testutil.IgnoreTime())
+			}
+		})
+	}
+}
+
+func TestGraphiteField(t *testing.T) {
+	parser := &graphite.Parser{Templates: []string{"servers.* .host.resource.measurement"}}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:  []string{"line"},
+		DropOriginal: true,
+		Log:          testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"tail",
+		map[string]string{},
+		map[string]interface{}{"line": "servers.host01.cpu.load;dc=eu 0.75 1435077219"},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		metric.New(
+			"load",
+			map[string]string{"host": "host01", "resource": "cpu", "dc": "eu"},
+			map[string]interface{}{"value": 0.75},
+			time.Unix(1435077219, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output)
+}
+
+func TestFieldParsers(t *testing.T) {
+	jsonParser := &json.Parser{TagKeys: []string{"lvl"}}
+	require.NoError(t, jsonParser.Init())
+	logfmtParser := &logfmt.Parser{TagKeys: []string{"lvl"}}
+	require.NoError(t, logfmtParser.Init())
+
+	plugin := &Parser{
+		ParseFields:  []string{"json", "logfmt"},
+		DropOriginal: false,
+		Merge:        "override",
+		Log:          testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(jsonParser)
+	plugin.SetFieldParser("logfmt", logfmtParser)
+
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"json":   `{"lvl":"info","count":42}`,
+			"logfmt": `lvl=info msg="http request"`,
+		},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{
+				"lvl": "info",
+			},
+			map[string]interface{}{
+				"json":   `{"lvl":"info","count":42}`,
+				"logfmt": `lvl=info msg="http request"`,
+				"count":  float64(42),
+				"msg":    "http request",
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestFallbackParsers(t *testing.T) {
+	jsonParser := &json.Parser{TagKeys: []string{"lvl"}}
+	require.NoError(t, jsonParser.Init())
+	logfmtParser := &logfmt.Parser{TagKeys: []string{"lvl"}}
+	require.NoError(t, logfmtParser.Init())
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields:  []string{"message"},
+		DropOriginal: true,
+		Log:          testLogger,
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(jsonParser)
+	plugin.AddFallbackParser(logfmtParser)
+
+	input := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{"message": `{"lvl":"info","count":42}`},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{"message": `lvl=warn msg="http request"`},
+			time.Unix(0, 0)),
+	}
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"lvl": "info"},
+			map[string]interface{}{"count": float64(42)},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{"lvl": "warn"},
+			map[string]interface{}{"msg": "http request"},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+	require.Empty(t, testLogger.Errors())
+}
+
+func TestPrefixes(t *testing.T) {
+	tests := []struct {
+		name         string
+		dropOriginal bool
+		merge        string
+		expected     []telegraf.Metric
+	}{
+		{
+			name:  "merge",
+			merge: "override",
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"lvl":        "error",
+						"parsed_lvl": "info",
+					},
+					map[string]interface{}{
+						"msg":        "original",
+						"sample":     `lvl=info msg="http request"`,
+						"parsed_msg": "http request",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "drop original",
+			dropOriginal: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"parsed_lvl": "info",
+					},
+					map[string]interface{}{
+						"parsed_msg": "http request",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &logfmt.Parser{TagKeys: []string{"lvl"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				ParseFields:  []string{"sample"},
+				DropOriginal: tt.dropOriginal,
+				Merge:        tt.merge,
+				FieldPrefix:  "parsed_",
+				TagPrefix:    "parsed_",
+				Log:          testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{
+					"lvl": "error",
+				},
+				map[string]interface{}{
+					"msg":    "original",
+					"sample": `lvl=info msg="http request"`,
+				},
+				time.Unix(0, 0))
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestExclude(t *testing.T) {
+	tests := []struct {
+		name          string
+		excludeFields []string
+		excludeTags   []string
+		fieldPrefix   string
+		dropOriginal  bool
+		merge         string
+		input         telegraf.Metric
+		expected      []telegraf.Metric
+	}{
+		{
+			name:         "parse one field drop original",
+			excludeTags:  []string{"msg"},
+			dropOriginal: true,
+			input: metric.New(
+				"singleField",
+				map[string]string{"some": "tag"},
+				map[string]interface{}{
+					"sample": `{"ts":"2018-07-24T19:43:40.275Z","lvl":"info","msg":"http request","method":"POST"}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"singleField",
+					map[string]string{
+						"ts":     "2018-07-24T19:43:40.275Z",
+						"lvl":    "info",
+						"method": "POST",
+					},
+					map[string]interface{}{},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:          "glob patterns with merge",
+			excludeFields: []string{"debug_*"},
+			excludeTags:   []string{"ts", "method"},
+			merge:         "override",
+			input: metric.New(
+				"singleField",
+				map[string]string{"method": "GET"},
+				map[string]interface{}{
+					"sample":     `{"ts":"2018-07-24T19:43:40.275Z","lvl":"info","method":"POST","debug_id":1,"debug_trace":2,"code":200}`,
+					"debug_mode": true,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"singleField",
+					map[string]string{
+						"lvl":    "info",
+						"method": "GET",
+					},
+					map[string]interface{}{
+						"sample":     `{"ts":"2018-07-24T19:43:40.275Z","lvl":"info","method":"POST","debug_id":1,"debug_trace":2,"code":200}`,
+						"debug_mode": true,
+						"code":       float64(200),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:          "exclude before prefixing",
+			excludeFields: []string{"debug_*"},
+			fieldPrefix:   "parsed_",
+			dropOriginal:  true,
+			input: metric.New(
+				"singleField",
+				map[string]string{},
+				map[string]interface{}{
+					"sample": `{"debug_id":1,"code":200}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"singleField",
+					map[string]string{},
+					map[string]interface{}{
+						"parsed_code": float64(200),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{TagKeys: []string{"ts", "lvl", "msg", "method"}}
+			require.NoError(t, parser.Init())
+
+			plugin := Parser{
+				ParseFields:   []string{"sample"},
+				ExcludeFields: tt.excludeFields,
+				ExcludeTags:   tt.excludeTags,
+				FieldPrefix:   tt.fieldPrefix,
+				DropOriginal:  tt.dropOriginal,
+				Merge:         tt.merge,
+				Log:           testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			output := plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestTimestampFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback string
+		timeKey  string
+		input    string
+		expected time.Time
+	}{
+		{
+			name:     "original",
+			fallback: "original",
+			input:    `{"lvl":"info","msg":"http request"}`,
+			expected: time.Unix(1700000000, 0),
+		},
+		{
+			name:     "original with parsed timestamp",
+			fallback: "original",
+			timeKey:  "ts",
+			input:    `{"ts":"2018-07-24T19:43:40.275Z","lvl":"info","msg":"http request"}`,
+			expected: time.Date(2018, 7, 24, 19, 43, 40, 275000000, time.UTC),
+		},
+		{
+			name:     "zero",
+			fallback: "zero",
+			input:    `{"lvl":"info","msg":"http request"}`,
+			expected: time.Unix(0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{
+				TagKeys:    []string{"lvl", "msg"},
+				TimeKey:    tt.timeKey,
+				TimeFormat: "2006-01-02T15:04:05Z07:00",
+			}
+			require.NoError(t, parser.Init())
+
+			plugin := Parser{
+				ParseFields:       []string{"sample"},
+				DropOriginal:      true,
+				TimestampFallback: tt.fallback,
+				Log:               testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"singleField",
+				map[string]string{},
+				map[string]interface{}{"sample": tt.input},
+				time.Unix(1700000000, 0))
+			expected := []telegraf.Metric{
+				metric.New(
+					"singleField",
+					map[string]string{
+						"lvl": "info",
+						"msg": "http request",
+					},
+					map[string]interface{}{},
+					tt.expected),
+			}
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, expected, output)
+		})
+	}
+}
+
+func TestTimestampFallbackTimeFunc(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		parser   telegraf.Parser
+		input    string
+		expected time.Time
+	}{
+		{
+			name:     "missing timestamp with precision",
+			parser:   &influx.Parser{TimestampTruncate: config.Duration(time.Second)},
+			input:    "test value=42i",
+			expected: time.Unix(1700000000, 0),
+		},
+		{
+			name:     "current timestamp",
+			parser:   &influx.Parser{},
+			input:    fmt.Sprintf("test value=42i %d", now.UnixNano()),
+			expected: now,
+		},
+		{
+			name:     "missing timestamp with logfmt",
+			parser:   &logfmt.Parser{},
+			input:    "value=42i",
+			expected: time.Unix(1700000000, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.parser.(telegraf.Initializer).Init())
+
+			plugin := Parser{
+				ParseFields:       []string{"sample"},
+				DropOriginal:      true,
+				TimestampFallback: "original",
+				Log:               testutil.Logger{Name: "processor.parser"},
+			}
+			plugin.SetParser(tt.parser)
+			require.NoError(t, plugin.Init())
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{"sample": tt.input},
+				time.Unix(1700000000, 0))
+
+			output := plugin.Apply(input)
+			require.Len(t, output, 1)
+			require.Equal(t, tt.expected.UnixNano(), output[0].Time().UnixNano())
+		})
+	}
+}
+
+func TestTimestampFallbackTimeFuncFromConfig(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["sample"]
+  drop_original = true
+  timestamp_fallback = "original"
+  data_format = "influx"
+  influx_timestamp_truncate = "1s"
+`
+
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(cfg)))
+	require.Len(t, c.Processors, 1)
+
+	plugin := c.Processors[0].Processor.(processors.HasUnwrap).Unwrap().(*Parser)
+	plugin.Log = testutil.Logger{Name: "processor.parser"}
+	require.NoError(t, plugin.Init())
+
+	// The truncated current time of metrics without a timestamp is only
+	// detected if the time function is set through the running parser
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{"sample": "test value=42i"},
+		time.Unix(1700000000, 0))
+
+	output := plugin.Apply(input)
+	require.Len(t, output, 1)
+	require.Equal(t, time.Unix(1700000000, 0).UnixNano(), output[0].Time().UnixNano())
+}
+
+func TestTimestampFallbackNow(t *testing.T) {
+	plugin := Parser{
+		ParseFields:  []string{"sample"},
+		DropOriginal: true,
+		Log:          testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(&logfmt.Parser{})
+
+	input := metric.New(
+		"singleField",
+		map[string]string{},
+		map[string]interface{}{"sample": `lvl=info`},
+		time.Unix(0, 0))
+
+	start := time.Now()
+	output := plugin.Apply(input)
+	require.Len(t, output, 1)
+	require.False(t, output[0].Time().Before(start))
+}
+
+func TestInvalidTimestampFallback(t *testing.T) {
+	plugin := Parser{TimestampFallback: "later"}
+	require.EqualError(t, plugin.Init(), "unrecognized timestamp fallback: later")
+}
+
+func TestMeasurementTemplate(t *testing.T) {
+	tests := []struct {
+		name         string
+		template     string
+		dropOriginal bool
+		merge        string
+		expected     []telegraf.Metric
+	}{
+		{
+			name:     "keep original",
+			template: `{{.Name}}_{{.Tag "region"}}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{
+						"sample": `region=eu value=42`,
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"test_eu",
+					map[string]string{
+						"region": "eu",
+					},
+					map[string]interface{}{
+						"value": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:     "merge",
+			template: `{{.Name}}_{{.Tag "region"}}`,
+			merge:    "override",
+			expected: []telegraf.Metric{
+				metric.New(
+					"test_eu",
+					map[string]string{
+						"region": "eu",
+					},
+					map[string]interface{}{
+						"sample": `region=eu value=42`,
+						"value":  int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "drop original",
+			template:     `{{.Tag "region"}}`,
+			dropOriginal: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"eu",
+					map[string]string{
+						"region": "eu",
+					},
+					map[string]interface{}{
+						"value": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "execution error keeps name",
+			template:     `{{index (.Field "value") 0}}`,
+			dropOriginal: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"region": "eu",
+					},
+					map[string]interface{}{
+						"value": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "empty result keeps name",
+			template:     `{{.Tag "unknown"}}`,
+			dropOriginal: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"region": "eu",
+					},
+					map[string]interface{}{
+						"value": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &logfmt.Parser{TagKeys: []string{"region"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				ParseFields:         []string{"sample"},
+				DropOriginal:        tt.dropOriginal,
+				Merge:               tt.merge,
+				MeasurementTemplate: tt.template,
+				Log:                 testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"sample": `region=eu value=42`,
+				},
+				time.Unix(0, 0))
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestInvalidMeasurementTemplate(t *testing.T) {
+	plugin := &Parser{MeasurementTemplate: "{{.Name"}
+	require.ErrorContains(t, plugin.Init(), "creating measurement template failed")
+}
+
+func TestDryRun(t *testing.T) {
+	parser := &logfmt.Parser{TagKeys: []string{"lvl"}}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:  []string{"sample"},
+		DropOriginal: true,
+		Merge:        "override",
+		DryRun:       true,
+		Log:          testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"sample": `lvl=info msg="http request"`,
+			},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"sample": `lvl=info msg="http request`,
+			},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"other": `lvl=info`,
+			},
+			time.Unix(0, 0)),
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{
+				"parse_status": "ok",
+			},
+			map[string]interface{}{
+				"sample":       `lvl=info msg="http request"`,
+				"parse_sample": "test lvl=info msg=http request",
+			},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{
+				"parse_status": "fail",
+			},
+			map[string]interface{}{
+				"sample": `lvl=info msg="http request`,
+			},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"other": `lvl=info`,
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input...)
+	require.Len(t, output, len(input))
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestParsedTagsAsTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		dropOriginal bool
+		merge        string
+		expected     []telegraf.Metric
+	}{
+		{
+			name: "keep original",
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"sample": "region=eu-west latency=42",
+					},
+					map[string]interface{}{
+						"value": int64(1),
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"test",
+					map[string]string{
+						"region": "eu-west",
+					},
+					map[string]interface{}{
+						"latency": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:  "merge override",
+			merge: "override",
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"sample": "region=eu-west latency=42",
+						"region": "eu-west",
+					},
+					map[string]interface{}{
+						"value":   int64(1),
+						"latency": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:  "merge keep-keys",
+			merge: "keep-keys",
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"sample": "region=eu-west latency=42",
+						"region": "eu-west",
+					},
+					map[string]interface{}{
+						"value":   int64(1),
+						"latency": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "drop original",
+			dropOriginal: true,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"region": "eu-west",
+					},
+					map[string]interface{}{
+						"latency": int64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &logfmt.Parser{}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				ParseTags:        []string{"sample"},
+				ParsedTagsAsTags: true,
+				DropOriginal:     tt.dropOriginal,
+				Merge:            tt.merge,
+				Log:              testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{
+					"sample": "region=eu-west latency=42",
+				},
+				map[string]interface{}{
+					"value": int64(1),
+				},
+				time.Unix(0, 0))
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDepth int
+		value    string
+		expected []telegraf.Metric
+	}{
+		{
+			name:     "single level",
+			maxDepth: 1,
+			value:    `{"lvl": "info", "payload": "{\"value\": 42, \"unit\": \"ms\"}"}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{
+						"lvl":     "info",
+						"payload": `{"value": 42, "unit": "ms"}`,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:     "nested json",
+			maxDepth: 2,
+			value:    `{"lvl": "info", "payload": "{\"value\": 42, \"unit\": \"ms\"}"}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{
+						"lvl":           "info",
+						"payload_value": float64(42),
+						"payload_unit":  "ms",
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:     "depth limit",
+			maxDepth: 2,
+			value:    `{"payload": "{\"inner\": \"{\\\"value\\\": 42}\"}"}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{
+						"payload_inner": `{"value": 42}`,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:     "three levels",
+			maxDepth: 3,
+			value:    `{"payload": "{\"inner\": \"{\\\"value\\\": 42}\"}"}`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{
+						"payload_inner_value": float64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{StringFields: []string{"*"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				ParseFields:  []string{"sample"},
+				DropOriginal: true,
+				MaxDepth:     tt.maxDepth,
+				Log:          testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"sample": tt.value,
+				},
+				time.Unix(0, 0))
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestMaxDepthNoProgress(t *testing.T) {
+	parser := &value.Parser{
+		MetricName: "parser",
+		DataType:   "string",
+	}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:  []string{"sample"},
+		DropOriginal: true,
+		MaxDepth:     10,
+		Log:          testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"sample": "hello",
+		},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"value": "hello",
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestInvalidMaxDepth(t *testing.T) {
+	plugin := &Parser{MaxDepth: -1}
+	require.ErrorContains(t, plugin.Init(), "invalid max depth")
+}
+
+func TestSourceFieldName(t *testing.T) {
+	parser := &value.Parser{
+		MetricName: "parser",
+		DataType:   "float",
+		FieldName:  "{{source}}",
+	}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields: []string{"a", "b"},
+		ParseTags:   []string{"c"},
+		Merge:       "override",
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"test",
+		map[string]string{
+			"c": "3.5",
+		},
+		map[string]interface{}{
+			"a": "1.5",
+			"b": "2.5",
+		},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{
+				"c": "3.5",
+			},
+			map[string]interface{}{
+				"a": 1.5,
+				"b": 2.5,
+				"c": 3.5,
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestCondition(t *testing.T) {
+	parser := &logfmt.Parser{}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields: []string{"message"},
+		Merge:       "override",
+		Condition:   `tags.source == "web"`,
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"source": "web"},
+			map[string]interface{}{"message": "status=200"},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{"source": "db"},
+			map[string]interface{}{"message": "status=200"},
+			time.Unix(0, 0)),
+	}
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"source": "web"},
+			map[string]interface{}{
+				"message": "status=200",
+				"status":  int64(200),
+			},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{"source": "db"},
+			map[string]interface{}{"message": "status=200"},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input...)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestInvalidCondition(t *testing.T) {
+	plugin := &Parser{Condition: `tags.source`}
+	require.ErrorContains(t, plugin.Init(), "compiling condition failed")
+}
+
+func TestMergeFieldOrder(t *testing.T) {
+	plugin := &Parser{
+		ParseFields: []string{"field_1", "field_2"},
+		Merge:       "override",
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(&logfmt.Parser{})
+
+	for i := 0; i < 100; i++ {
+		// Alternate the field order of the input metric, the parsed fields
+		// must always be appended in the same order.
+		var expected []string
+		input := metric.New("test", map[string]string{}, map[string]interface{}{}, time.Unix(0, 0))
+		input.AddField("original", int64(42))
+		if i%2 == 0 {
+			input.AddField("field_2", "b=2")
+			input.AddField("field_1", "a=1")
+			expected = []string{"original", "field_2", "field_1", "a", "b"}
+		} else {
+			input.AddField("field_1", "a=1")
+			input.AddField("field_2", "b=2")
+			expected = []string{"original", "field_1", "field_2", "a", "b"}
+		}
+
+		output := plugin.Apply(input)
+		require.Len(t, output, 1)
+
+		actual := make([]string, 0, len(expected))
+		for _, field := range output[0].FieldList() {
+			actual = append(actual, field.Key)
+		}
+		require.Equal(t, expected, actual)
+	}
+}
+
+func TestMergeTagsAndFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		merge        string
+		mergeTags    string
+		mergeFields  string
+		expectedTags map[string]string
+		expected     map[string]interface{}
+	}{
+		{
+			name:         "override tags and replace fields",
+			mergeTags:    "override",
+			mergeFields:  "replace",
+			expectedTags: map[string]string{"host": "a", "env": "dev", "region": "eu"},
+			expected:     map[string]interface{}{"count": float64(2), "size": float64(3)},
+		},
+		{
+			name:         "keep tags and override fields",
+			mergeTags:    "keep",
+			mergeFields:  "override",
+			expectedTags: map[string]string{"host": "a", "env": "prod", "region": "eu"},
+			expected: map[string]interface{}{
+				"message": `{"env": "dev", "region": "eu", "count": 2, "size": 3}`,
+				"count":   float64(2),
+				"size":    float64(3),
+			},
+		},
+		{
+			name:         "replace tags and keep fields",
+			mergeTags:    "replace",
+			mergeFields:  "keep",
+			expectedTags: map[string]string{"env": "dev", "region": "eu"},
+			expected: map[string]interface{}{
+				"message": `{"env": "dev", "region": "eu", "count": 2, "size": 3}`,
+				"count":   int64(1),
+				"size":    float64(3),
+			},
+		},
+		{
+			name:         "tags default to legacy keep-keys",
+			merge:        "keep-keys",
+			mergeFields:  "replace",
+			expectedTags: map[string]string{"host": "a", "env": "prod", "region": "eu"},
+			expected:     map[string]interface{}{"count": float64(2), "size": float64(3)},
+		},
+		{
+			name:         "fields default to legacy override",
+			merge:        "override",
+			mergeTags:    "keep",
+			expectedTags: map[string]string{"host": "a", "env": "prod", "region": "eu"},
+			expected: map[string]interface{}{
+				"message": `{"env": "dev", "region": "eu", "count": 2, "size": 3}`,
+				"count":   float64(2),
+				"size":    float64(3),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{TagKeys: []string{"env", "region"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				ParseFields: []string{"message"},
+				Merge:       tt.merge,
+				MergeTags:   tt.mergeTags,
+				MergeFields: tt.mergeFields,
+				Log:         testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{"host": "a", "env": "prod"},
+				map[string]interface{}{
+					"message": `{"env": "dev", "region": "eu", "count": 2, "size": 3}`,
+					"count":   int64(1),
+				},
+				time.Unix(0, 0))
+			expected := []telegraf.Metric{
+				metric.New("test", tt.expectedTags, tt.expected, time.Unix(0, 0)),
+			}
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, expected, output)
+		})
+	}
+}
+
+func TestMergeTagsOrFieldsWithoutMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		mergeTags   string
+		mergeFields string
+	}{
+		{
+			name:      "tags only",
+			mergeTags: "keep",
+		},
+		{
+			name:        "fields only",
+			mergeFields: "replace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Merging the unset keys must never be enabled implicitly
+			plugin := &Parser{
+				ParseFields: []string{"message"},
+				MergeTags:   tt.mergeTags,
+				MergeFields: tt.mergeFields,
+				Log:         testutil.Logger{Name: "processor.parser"},
+			}
+			require.ErrorContains(t, plugin.Init(), "merge must be set")
+		})
+	}
+}
+
+func TestMergeReplaceWithoutResult(t *testing.T) {
+	plugin := &Parser{
+		ParseFields: []string{"message"},
+		MergeTags:   "replace",
+		MergeFields: "replace",
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(&json.Parser{})
+
+	// Keep the original keys if parsing failed
+	input := metric.New(
+		"test",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"message": "not json"},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{input.Copy()}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output)
+}
+
+func TestInvalidMergeTagsAndFields(t *testing.T) {
+	plugin := Parser{MergeTags: "fake"}
+	require.ErrorContains(t, plugin.Init(), "unrecognized merge value: fake")
+
+	plugin = Parser{MergeFields: "fake"}
+	require.ErrorContains(t, plugin.Init(), "unrecognized merge value: fake")
+
+	plugin = Parser{Merge: "replace-timestamp-only", MergeFields: "keep"}
+	require.ErrorContains(t, plugin.Init(), "cannot be combined")
+}
+
+func TestInvalidMerge(t *testing.T) {
+	plugin := Parser{Merge: "fake"}
+	require.Error(t, plugin.Init())
+}
+
+func TestValidMerge(t *testing.T) {
+	for _, merge := range []string{"", "override", "override-with-timestamp", "keep-keys", "replace-timestamp-only"} {
+		t.Run(merge, func(t *testing.T) {
+			plugin := Parser{Merge: merge}
+			require.NoError(t, plugin.Init())
+		})
+	}
+}
+
+func TestBadApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		parseFields []string
+		parser      telegraf.Parser
+		input       telegraf.Metric
+		expected    []telegraf.Metric
+	}{
+		{
+			name:        "field not found",
+			parseFields: []string{"bad_field"},
+			parser:      &json.Parser{},
+			input: metric.New(
+				"bad",
+				map[string]string{},
+				map[string]interface{}{
+					"some_field": 5,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bad",
+					map[string]string{},
+					map[string]interface{}{
+						"some_field": 5,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "non string field",
+			parseFields: []string{"some_field"},
+			parser:      &json.Parser{},
+			input: metric.New(
+				"bad",
+				map[string]string{},
+				map[string]interface{}{
+					"some_field": 5,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bad",
+					map[string]string{},
+					map[string]interface{}{
+						"some_field": 5,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "inexact number in one field",
+			parseFields: []string{"good", "bad"},
+			parser:      &json.Parser{NumberHandling: "strict"},
+			input: metric.New(
+				"bad",
+				map[string]string{},
+				map[string]interface{}{
+					"good": `{"id": 42}`,
+					"bad":  `{"id": 12345678901234567890}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bad",
+					map[string]string{},
+					map[string]interface{}{
+						"good": `{"id": 42}`,
+						"bad":  `{"id": 12345678901234567890}`,
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"bad",
+					map[string]string{},
+					map[string]interface{}{
+						"id": float64(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "checksum mismatch in one field",
+			parseFields: []string{"good", "bad"},
+			parser: &binary.Parser{
+				Endianness: "be",
+				Configs: []binary.Config{
+					{
+						MetricName: "frame",
+						Entries: []binary.Entry{
+							{Name: "value", Type: "uint8"},
+							{Type: "crc16"},
+						},
+					},
+				},
+			},
+			input: metric.New(
+				"bad",
+				map[string]string{},
+				map[string]interface{}{
+					"good": "\x2a\x64\xd8",
+					"bad":  "\x2a\x00\x00",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bad",
+					map[string]string{},
+					map[string]interface{}{
+						"good": "\x2a\x64\xd8",
+						"bad":  "\x2a\x00\x00",
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"frame",
+					map[string]string{},
+					map[string]interface{}{
+						"value": uint8(42),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "too many fields in one field",
+			parseFields: []string{"good", "bad"},
+			parser:      &influx.Parser{MaxFields: 1},
+			input: metric.New(
+				"bad",
+				map[string]string{},
+				map[string]interface{}{
+					"good": "cpu value=1 0",
+					"bad":  "cpu value=1,other=2 0",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"bad",
+					map[string]string{},
+					map[string]interface{}{
+						"good": "cpu value=1 0",
+						"bad":  "cpu value=1,other=2 0",
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"value": float64(1),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, ok := tt.parser.(telegraf.Initializer); ok {
+				require.NoError(t, p.Init())
+			}
+
+			plugin := Parser{
+				ParseFields: tt.parseFields,
+				Log:         testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(tt.parser)
+
+			output := plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestDropOriginalEmptyResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		dropOnEmpty bool
+		input       string
+		expected    []telegraf.Metric
+	}{
+		{
+			name:  "empty array keeps original",
+			input: `[]`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{"sample": `[]`},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "empty array with drop on empty",
+			dropOnEmpty: true,
+			input:       `[]`,
+			expected:    []telegraf.Metric{},
+		},
+		{
+			name:     "parse error drops original",
+			input:    `{"value": `,
+			expected: []telegraf.Metric{},
+		},
+		{
+			name:        "non-empty result drops original",
+			dropOnEmpty: true,
+			input:       `[{"value": 42}]`,
+			expected: []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{"value": float64(42)},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{}
+			require.NoError(t, parser.Init())
+
+			plugin := Parser{
+				ParseFields:  []string{"sample"},
+				DropOriginal: true,
+				DropOnEmpty:  tt.dropOnEmpty,
+				Log:          testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{"sample": tt.input},
+				time.Unix(0, 0))
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestMetricOnError(t *testing.T) {
+	tests := []struct {
+		name         string
+		parseFields  []string
+		parseTags    []string
+		dropOriginal bool
+		merge        string
+		input        telegraf.Metric
+		expected     []telegraf.Metric
+	}{
+		{
+			name:        "one field fails [keep]",
+			parseFields: []string{"good", "bad"},
+			input: metric.New(
+				"success",
+				map[string]string{},
+				map[string]interface{}{
+					"good": `{"lvl":"info"}`,
+					"bad":  "why",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"success",
+					map[string]string{},
+					map[string]interface{}{
+						"good":         `{"lvl":"info"}`,
+						"bad":          "why",
+						"parse_errors": int64(1),
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"success",
+					map[string]string{
+						"lvl": "info",
+					},
+					map[string]interface{}{},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "one field and one tag fail [merge]",
+			parseFields: []string{"good", "bad"},
+			parseTags:   []string{"broken"},
+			merge:       "override",
+			input: metric.New(
+				"success",
+				map[string]string{
+					"broken": "{",
+				},
+				map[string]interface{}{
+					"good": `{"lvl":"info"}`,
+					"bad":  "why",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"success",
+					map[string]string{
+						"broken": "{",
+						"lvl":    "info",
+					},
+					map[string]interface{}{
+						"good":         `{"lvl":"info"}`,
+						"bad":          "why",
+						"parse_errors": int64(2),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:         "one field fails [replace]",
+			parseFields:  []string{"good", "bad"},
+			dropOriginal: true,
+			input: metric.New(
+				"success",
+				map[string]string{},
+				map[string]interface{}{
+					"good": `{"lvl":"info"}`,
+					"bad":  "why",
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"success",
+					map[string]string{
+						"lvl": "info",
+					},
+					map[string]interface{}{
+						"parse_errors": int64(1),
+					},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "all fields succeed",
+			parseFields: []string{"good"},
+			input: metric.New(
+				"success",
+				map[string]string{},
+				map[string]interface{}{
+					"good": `{"lvl":"info"}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"success",
+					map[string]string{},
+					map[string]interface{}{
+						"good":         `{"lvl":"info"}`,
+						"parse_errors": int64(0),
+					},
+					time.Unix(0, 0)),
+				metric.New(
+					"success",
+					map[string]string{
+						"lvl": "info",
+					},
+					map[string]interface{}{},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:        "no matching field",
+			parseFields: []string{"good"},
+			input: metric.New(
+				"success",
+				map[string]string{},
+				map[string]interface{}{
+					"other": `{"lvl":"info"}`,
+				},
+				time.Unix(0, 0)),
+			expected: []telegraf.Metric{
+				metric.New(
+					"success",
+					map[string]string{},
+					map[string]interface{}{
+						"other": `{"lvl":"info"}`,
+					},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{TagKeys: []string{"lvl"}}
+			require.NoError(t, parser.Init())
+
+			plugin := Parser{
+				ParseFields:   tt.parseFields,
+				ParseTags:     tt.parseTags,
+				DropOriginal:  tt.dropOriginal,
+				Merge:         tt.merge,
+				MetricOnError: true,
+				Log:           testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			output := plugin.Apply(tt.input)
+			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.SortMetrics(), testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestEmitDuration(t *testing.T) {
+	parser := &grok.Parser{
+		Patterns: []string{"%{WORD:lvl:tag} %{NUMBER:value:int}"},
+		Log:      testutil.Logger{},
+	}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:  []string{"message"},
+		Merge:        "override",
+		EmitDuration: true,
+		Log:          testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"message": "info 42",
+			},
+			time.Unix(0, 0)),
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"other": "info 42",
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input...)
+	require.Len(t, output, 2)
+
+	duration, found := output[0].GetField("parse_duration_ns")
+	require.True(t, found)
+	require.IsType(t, int64(0), duration)
+	require.Positive(t, duration)
+	require.Equal(t, int64(42), output[0].Fields()["value"])
+
+	// Metrics without fields to parse are not annotated
+	require.False(t, output[1].HasField("parse_duration_ns"))
+}
+
+func TestBase64FieldValidation(t *testing.T) {
+	testMetric := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"b": `eyJsdmwiOiJpbmZvIiwibXNnIjoiaHR0cCByZXF1ZXN0In0=`,
+		},
+		time.Unix(0, 0))
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields:  []string{"a"},
+		Base64Fields: []string{"b"},
+		Log:          testLogger,
+	}
+	plugin.SetParser(&json.Parser{})
+	require.NoError(t, plugin.Init())
+	plugin.Apply(testMetric)
+	require.Empty(t, testLogger.Errors())
+
+	plugin = &Parser{
+		ParseFields:  []string{"b"},
+		Base64Fields: []string{"b"},
+		Log:          testLogger,
+	}
+	plugin.SetParser(&json.Parser{})
+	require.NoError(t, plugin.Init())
+	plugin.Apply(testMetric)
+	require.NotEmpty(t, testLogger.Errors())
+}
+
+func TestGzipFields(t *testing.T) {
+	encoder, err := internal.NewGzipEncoder()
+	require.NoError(t, err)
+	compressed, err := encoder.Encode([]byte(`{"lvl":"info","msg":"http request"}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		parseBase64 []string
+		parseGzip   []string
+		value       string
+	}{
+		{
+			name:      "gzip only",
+			parseGzip: []string{"sample"},
+			value:     string(compressed),
+		},
+		{
+			name:        "base64 and gzip",
+			parseBase64: []string{"sample"},
+			parseGzip:   []string{"sample"},
+			value:       base64.StdEncoding.EncodeToString(compressed),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{TagKeys: []string{"lvl", "msg"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				Base64Fields: tt.parseBase64,
+				GzipFields:   tt.parseGzip,
+				DropOriginal: true,
+				Log:          testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"sample": tt.value,
+				},
+				time.Unix(0, 0))
+			expected := []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"lvl": "info",
+						"msg": "http request",
+					},
+					map[string]interface{}{},
+					time.Unix(0, 0)),
+			}
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestGzipFieldValidation(t *testing.T) {
+	testMetric := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"b": `{"lvl":"info"}`,
+		},
+		time.Unix(0, 0))
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields: []string{"b"},
+		GzipFields:  []string{"b"},
+		Log:         testLogger,
+	}
+	plugin.SetParser(&json.Parser{})
+	require.NoError(t, plugin.Init())
+	plugin.Apply(testMetric)
+	require.NotEmpty(t, testLogger.Errors())
+
+	// Uncompressed data must be reported as error
+	testLogger = &testutil.CaptureLogger{}
+	plugin = &Parser{
+		GzipFields: []string{"b"},
+		Log:        testLogger,
+	}
+	plugin.SetParser(&json.Parser{})
+	require.NoError(t, plugin.Init())
+	plugin.Apply(testMetric)
+	require.NotEmpty(t, testLogger.Errors())
+}
+
+func TestSourceFieldTag(t *testing.T) {
+	parser := &json.Parser{TagKeys: []string{"lvl", "err"}}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:    []string{"field_1", "field_2"},
+		DropOriginal:   true,
+		SourceFieldTag: "source",
+		Log:            testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"bigMeasure",
+		map[string]string{},
+		map[string]interface{}{
+			"field_1": `{"lvl":"info","msg":"http request"}`,
+			"field_2": `{"err":"fatal","fatal":"security threat"}`,
+		},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		metric.New(
+			"bigMeasure",
+			map[string]string{
+				"lvl":    "info",
+				"source": "field_1",
+			},
+			map[string]interface{}{},
+			time.Unix(0, 0)),
+		metric.New(
+			"bigMeasure",
+			map[string]string{
+				"err":    "fatal",
+				"source": "field_2",
+			},
+			map[string]interface{}{},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestPromoteToTags(t *testing.T) {
+	parser := &json.Parser{StringFields: []string{"*"}}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:   []string{"message"},
+		Merge:         "override",
+		PromoteToTags: []string{"labels_*"},
+		TagPrefix:     "k8s_",
+		Log:           testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"test",
+		map[string]string{"host": "a"},
+		map[string]interface{}{
+			"message": `{"labels": {"app": "web", "replicas": 3, "canary": true}, "value": 42}`,
+		},
+		time.Unix(0, 0))
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{
+				"host":                "a",
+				"k8s_labels_app":      "web",
+				"k8s_labels_replicas": "3",
+				"k8s_labels_canary":   "true",
+			},
+			map[string]interface{}{
+				"message": `{"labels": {"app": "web", "replicas": 3, "canary": true}, "value": 42}`,
+				"value":   float64(42),
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output)
+}
+
+func TestMaxMetrics(t *testing.T) {
+	parser := &json.Parser{}
+	require.NoError(t, parser.Init())
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields:  []string{"data_*"},
+		DropOriginal: true,
+		MaxMetrics:   100,
+		Log:          testLogger,
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	elements := make([]string, 0, 1000)
+	for i := range 1000 {
+		elements = append(elements, fmt.Sprintf(`{"value": %d}`, i))
+	}
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"data_a": "[" + strings.Join(elements, ",") + "]",
+			"data_b": `{"value": -1}`,
+		},
+		time.Unix(0, 0))
+
+	expected := make([]telegraf.Metric, 0, 100)
+	for i := range 100 {
+		expected = append(expected, metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{"value": float64(i)},
+			time.Unix(0, 0)))
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+	require.Len(t, testLogger.Errors(), 1)
+	require.Contains(t, testLogger.Errors()[0], "exceeded the maximum of 100 metrics")
+}
+
+func TestMaxMetricsReached(t *testing.T) {
+	parser := &json.Parser{}
+	require.NoError(t, parser.Init())
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields:  []string{"data_*"},
+		ParseTags:    []string{"payload"},
+		DropOriginal: true,
+		MaxMetrics:   2,
+		Log:          testLogger,
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"test",
+		map[string]string{"payload": `{"value": -2}`},
+		map[string]interface{}{
+			"data_a": `[{"value": 0}, {"value": 1}]`,
+			"data_b": `{"value": -1}`,
+		},
+		time.Unix(0, 0))
+
+	expected := []telegraf.Metric{
+		metric.New("test", map[string]string{}, map[string]interface{}{"value": float64(0)}, time.Unix(0, 0)),
+		metric.New("test", map[string]string{}, map[string]interface{}{"value": float64(1)}, time.Unix(0, 0)),
+	}
+
+	// The remaining field and tag must not be parsed at all
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+	require.Len(t, testLogger.Errors(), 1)
+	require.Contains(t, testLogger.Errors()[0], "reached the maximum of 2 metrics")
+}
+
+func TestInvalidMaxMetrics(t *testing.T) {
+	plugin := &Parser{MaxMetrics: -1}
+	require.ErrorContains(t, plugin.Init(), "invalid max metrics -1")
+}
+
+func TestFailureMeasurement(t *testing.T) {
+	parser := &json.Parser{}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:        []string{"message"},
+		FailureMeasurement: "parse_failures",
+		Log:                testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"message": `{"value": 42`},
+			time.Unix(1577923199, 0)),
+		metric.New(
+			"test",
+			map[string]string{"host": "b"},
+			map[string]interface{}{"message": `{"value": 42}`},
+			time.Unix(1577923199, 0)),
+	}
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"message": `{"value": 42`},
+			time.Unix(1577923199, 0)),
+		metric.New(
+			"parse_failures",
+			map[string]string{
+				"host":   "a",
+				"source": "message",
+				"error":  "",
+			},
+			map[string]interface{}{"raw": `{"value": 42`},
+			time.Unix(1577923199, 0)),
+		metric.New(
+			"test",
+			map[string]string{"host": "b"},
+			map[string]interface{}{"message": `{"value": 42}`},
+			time.Unix(1577923199, 0)),
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{"value": float64(42)},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input...)
+	require.Len(t, output, len(expected))
+	errorTag, found := output[1].GetTag("error")
+	require.True(t, found)
+	require.NotEmpty(t, errorTag)
+	output[1].AddTag("error", "")
+	require.Equal(t, time.Unix(1577923199, 0), output[1].Time())
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+}
+
+func TestDuplicateKeyError(t *testing.T) {
+	parser := &json.Parser{DuplicateKeys: "error"}
+	require.NoError(t, parser.Init())
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields: []string{"message"},
+		Log:         testLogger,
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{"message": `{"a":1,"a":2}`},
+		time.Unix(0, 0))
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{input}, output)
+	require.Len(t, testLogger.Errors(), 1)
+	require.Contains(t, testLogger.Errors()[0], `could not parse field message: duplicate key "a"`)
+}
+
+func TestAutoDecompress(t *testing.T) {
+	data := []byte(`{"lvl":"info","msg":"http request"}`)
+
+	tests := []struct {
+		name     string
+		encoding string
+	}{
+		{
+			name:     "gzip",
+			encoding: "gzip",
+		},
+		{
+			name:     "zstd",
+			encoding: "zstd",
+		},
+		{
+			name:     "zlib",
+			encoding: "zlib",
+		},
+		{
+			name:     "uncompressed",
+			encoding: "identity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder, err := internal.NewContentEncoder(tt.encoding)
+			require.NoError(t, err)
+			compressed, err := encoder.Encode(data)
+			require.NoError(t, err)
+
+			parser := &json.Parser{TagKeys: []string{"lvl", "msg"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				Base64Fields:   []string{"sample"},
+				AutoDecompress: true,
+				DropOriginal:   true,
+				Log:            testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"sample": base64.StdEncoding.EncodeToString(compressed),
+				},
+				time.Unix(0, 0))
+			expected := []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"lvl": "info",
+						"msg": "http request",
+					},
+					map[string]interface{}{},
+					time.Unix(0, 0)),
+			}
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestAutoDecompressInvalid(t *testing.T) {
+	testMetric := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"b": string([]byte{0x1f, 0x8b, 0x00, 0x01}),
+		},
+		time.Unix(0, 0))
+
+	testLogger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields:    []string{"b"},
+		AutoDecompress: true,
+		Log:            testLogger,
+	}
+	plugin.SetParser(&json.Parser{})
+	require.NoError(t, plugin.Init())
+	plugin.Apply(testMetric)
+	require.NotEmpty(t, testLogger.Errors())
+	require.Contains(t, testLogger.Errors()[0], "could not decompress gzip field b")
+}
+
+func TestLogErrorInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval config.Duration
+		expected int
+	}{
+		{
+			name:     "disabled",
+			expected: 10,
+		},
+		{
+			name:     "coalesced",
+			interval: config.Duration(time.Hour),
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testLogger := &testutil.CaptureLogger{}
+			plugin := &Parser{
+				ParseFields:      []string{"sample"},
+				LogErrorInterval: tt.interval,
+				Log:              testLogger,
+			}
+			plugin.SetParser(&json.Parser{})
+			require.NoError(t, plugin.Init())
+
+			for i := 0; i < 10; i++ {
+				plugin.Apply(metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{"sample": "not json"},
+					time.Unix(0, 0)),
+				)
+			}
+			require.Len(t, testLogger.Errors(), tt.expected)
+		})
+	}
+}
+
+func TestErrorLoggerSummary(t *testing.T) {
+	testLogger := &testutil.CaptureLogger{}
+	log := &errorLogger{log: testLogger, interval: 50 * time.Millisecond}
+
+	for i := 0; i < 5; i++ {
+		log.Errorf("failure %d", i)
+	}
+	time.Sleep(60 * time.Millisecond)
+	log.Errorf("failure %d", 5)
+
+	errs := testLogger.Errors()
+	require.Len(t, errs, 3)
+	require.Contains(t, errs[0], "failure 0")
+	require.Contains(t, errs[1], "4 error(s) suppressed")
+	require.Contains(t, errs[2], "failure 5")
+}
+
+func TestErrorLoggerFlush(t *testing.T) {
+	testLogger := &testutil.CaptureLogger{}
+	log := &errorLogger{log: testLogger, interval: 50 * time.Millisecond}
+
+	for i := 0; i < 5; i++ {
+		log.Errorf("failure %d", i)
+	}
+
+	// The suppressed errors must be reported even without further errors
+	require.Eventually(t, func() bool {
+		return len(testLogger.Errors()) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Contains(t, testLogger.Errors()[1], "4 error(s) suppressed")
+}
+
+func TestStopFlushesSuppressedErrors(t *testing.T) {
+	logger := &testutil.CaptureLogger{}
+	plugin := &Parser{
+		ParseFields:      []string{"message"},
+		LogErrorInterval: config.Duration(time.Hour),
+		Log:              logger,
+	}
+	plugin.SetParser(&json.Parser{})
+	require.NoError(t, plugin.Init())
+
+	for i := 0; i < 3; i++ {
+		plugin.Apply(metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{"message": "invalid"},
+			time.Unix(0, 0)),
+		)
+	}
+	require.Len(t, logger.Errors(), 1)
+
+	// Stopping the wrapped processor must report the suppressed errors
+	// right away and release the pending summary timer
+	processors.NewStreamingProcessorFromProcessor(plugin).Stop()
+	require.Len(t, logger.Errors(), 2)
+	require.Contains(t, logger.Errors()[1], "2 error(s) suppressed")
+	require.Nil(t, plugin.errorLog.flush)
+}
+
+func TestHexFieldValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		parseFields []string
+		parseBase64 []string
+		value       string
+		expectError bool
+	}{
+		{
+			name:  "lower case",
+			value: "7b226c766c223a22696e666f227d",
+		},
+		{
+			name:  "upper case",
+			value: "7B226C766C223A22696E666F227D",
+		},
+		{
+			name:        "odd length",
+			value:       "7b226c766c223a22696e666f227",
+			expectError: true,
+		},
+		{
+			name:        "non-hex characters",
+			value:       "7x226c766c223a22696e666f227d",
+			expectError: true,
+		},
+		{
+			name:        "also in parse fields",
+			parseFields: []string{"b"},
+			value:       "7b226c766c223a22696e666f227d",
+			expectError: true,
+		},
+		{
+			name:        "also in base64 fields",
+			parseBase64: []string{"b"},
+			value:       "7b226c766c223a22696e666f227d",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testMetric := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"b": tt.value,
+				},
+				time.Unix(0, 0))
+
+			testLogger := &testutil.CaptureLogger{}
+			plugin := &Parser{
+				ParseFields:  tt.parseFields,
+				Base64Fields: tt.parseBase64,
+				HexFields:    []string{"b"},
+				Log:          testLogger,
+			}
+			plugin.SetParser(&json.Parser{})
+			require.NoError(t, plugin.Init())
+			output := plugin.Apply(testMetric)
+			if tt.expectError {
+				require.NotEmpty(t, testLogger.Errors())
+				require.Len(t, output, 1)
+			} else {
+				require.Empty(t, testLogger.Errors())
+				require.Len(t, output, 2)
+			}
+		})
+	}
+}
+
+func TestURLDecodeFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		parseBase64 []string
+		value       string
+	}{
+		{
+			name:  "percent-encoded json",
+			value: "%7B%22lvl%22%3A%22info%22%2C%22msg%22%3A%22http%20request%22%7D",
+		},
+		{
+			name:        "percent-encoded base64",
+			parseBase64: []string{"sample"},
+			value:       "eyJsdmwiOiJpbmZvIiwibXNnIjoiaHR0cCByZXF1ZXN0In0%3D",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &json.Parser{TagKeys: []string{"lvl", "msg"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				Base64Fields:    tt.parseBase64,
+				URLDecodeFields: []string{"sample"},
+				DropOriginal:    true,
+				Log:             testutil.Logger{Name: "processor.parser"},
+			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
+
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"sample": tt.value,
+				},
+				time.Unix(0, 0))
+			expected := []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{
+						"lvl": "info",
+						"msg": "http request",
+					},
+					map[string]interface{}{},
+					time.Unix(0, 0)),
+			}
+
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
+		})
+	}
+}
+
+func TestURLDecodeFieldValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		parseFields []string
+		value       string
+		expectError bool
+	}{
+		{
+			name:  "valid",
+			value: "%7B%22lvl%22%3A%22info%22%7D",
+		},
+		{
+			name:        "invalid escape",
+			value:       "%7B%22lvl%22%3A%22info%22%7",
+			expectError: true,
+		},
+		{
+			name:        "also in parse fields",
+			parseFields: []string{"b"},
+			value:       "%7B%22lvl%22%3A%22info%22%7D",
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if p, ok := tt.parser.(telegraf.Initializer); ok {
-				require.NoError(t, p.Init())
-			}
-			plugin := Parser{
-				ParseFields:  tt.parseFields,
-				ParseTags:    tt.parseTags,
-				Base64Fields: tt.parseBase64,
-				DropOriginal: tt.dropOriginal,
-				Merge:        tt.merge,
-				Log:          testutil.Logger{Name: "processor.parser"},
-			}
-			plugin.SetParser(tt.parser)
-
-			output := plugin.Apply(tt.input)
-			t.Logf("Testing: %s", tt.name)
+			testMetric := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{
+					"b": tt.value,
+				},
+				time.Unix(0, 0))
 
-			// check timestamp when using with-timestamp merge type
-			if tt.merge == "override-with-timestamp" {
-				testutil.RequireMetricsEqual(t, tt.expected, output, testutil.SortMetrics())
+			testLogger := &testutil.CaptureLogger{}
+			plugin := &Parser{
+				ParseFields:     tt.parseFields,
+				URLDecodeFields: []string{"b"},
+				Log:             testLogger,
+			}
+			plugin.SetParser(&json.Parser{})
+			require.NoError(t, plugin.Init())
+			output := plugin.Apply(testMetric)
+			if tt.expectError {
+				require.NotEmpty(t, testLogger.Errors())
+				require.Len(t, output, 1)
 			} else {
-				testutil.RequireMetricsEqual(t, tt.expected, output, testutil.SortMetrics(), testutil.IgnoreTime())
+				require.Empty(t, testLogger.Errors())
+				require.Len(t, output, 2)
 			}
 		})
 	}
 }
 
-func TestInvalidMerge(t *testing.T) {
-	plugin := Parser{Merge: "fake"}
-	require.Error(t, plugin.Init())
+func TestCoerceToString(t *testing.T) {
+	parser := &value.Parser{
+		DataType:  "string",
+		FieldName: "{{source}}",
+	}
+	require.NoError(t, parser.Init())
+
+	plugin := &Parser{
+		ParseFields:    []string{"packed", "ratio", "flag"},
+		CoerceToString: true,
+		Merge:          "override",
+		Log:            testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(parser)
+
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{
+			"packed": 1203,
+			"ratio":  0.5,
+			"flag":   true,
+		},
+		time.Unix(0, 0))
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{},
+			map[string]interface{}{
+				"packed": "1203",
+				"ratio":  "0.5",
+				"flag":   "true",
+			},
+			time.Unix(0, 0)),
+	}
+
+	output := plugin.Apply(input)
+	testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
 }
 
-func TestBadApply(t *testing.T) {
+func TestCharacterEncoding(t *testing.T) {
+	// "café crème" encoded in Latin-1
+	latin1 := "{\"msg\":\"caf\xe9 cr\xe8me\"}"
+
 	tests := []struct {
 		name        string
 		parseFields []string
-		parser      telegraf.Parser
-		input       telegraf.Metric
-		expected    []telegraf.Metric
+		b64Fields   []string
+		value       string
 	}{
 		{
-			name:        "field not found",
-			parseFields: []string{"bad_field"},
-			parser:      &json.Parser{},
-			input: metric.New(
-				"bad",
-				map[string]string{},
-				map[string]interface{}{
-					"some_field": 5,
-				},
-				time.Unix(0, 0)),
-			expected: []telegraf.Metric{
-				metric.New(
-					"bad",
-					map[string]string{},
-					map[string]interface{}{
-						"some_field": 5,
-					},
-					time.Unix(0, 0)),
-			},
+			name:        "plain",
+			parseFields: []string{"sample"},
+			value:       latin1,
 		},
 		{
-			name:        "non string field",
-			parseFields: []string{"some_field"},
-			parser:      &json.Parser{},
-			input: metric.New(
-				"bad",
-				map[string]string{},
-				map[string]interface{}{
-					"some_field": 5,
-				},
-				time.Unix(0, 0)),
-			expected: []telegraf.Metric{
-				metric.New(
-					"bad",
-					map[string]string{},
-					map[string]interface{}{
-						"some_field": 5,
-					},
-					time.Unix(0, 0)),
-			},
+			name:      "base64",
+			b64Fields: []string{"sample"},
+			value:     base64.StdEncoding.EncodeToString([]byte(latin1)),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if p, ok := tt.parser.(telegraf.Initializer); ok {
-				require.NoError(t, p.Init())
+			parser := &json.Parser{StringFields: []string{"msg"}}
+			require.NoError(t, parser.Init())
+
+			plugin := &Parser{
+				ParseFields:       tt.parseFields,
+				Base64Fields:      tt.b64Fields,
+				CharacterEncoding: "latin1",
+				DropOriginal:      true,
+				Log:               testutil.Logger{Name: "processor.parser"},
 			}
+			require.NoError(t, plugin.Init())
+			plugin.SetParser(parser)
 
-			plugin := Parser{
-				ParseFields: tt.parseFields,
-				Log:         testutil.Logger{Name: "processor.parser"},
+			input := metric.New(
+				"test",
+				map[string]string{},
+				map[string]interface{}{"sample": tt.value},
+				time.Unix(0, 0))
+			expected := []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{"msg": "café crème"},
+					time.Unix(0, 0)),
 			}
-			plugin.SetParser(tt.parser)
 
-			output := plugin.Apply(tt.input)
-			testutil.RequireMetricsEqual(t, tt.expected, output, testutil.IgnoreTime())
+			output := plugin.Apply(input)
+			testutil.RequireMetricsEqual(t, expected, output, testutil.IgnoreTime())
 		})
 	}
 }
 
-func TestBase64FieldValidation(t *testing.T) {
-	testMetric := metric.New(
+func TestInvalidCharacterEncoding(t *testing.T) {
+	plugin := &Parser{CharacterEncoding: "klingon"}
+	require.ErrorContains(t, plugin.Init(), `unsupported character encoding "klingon"`)
+}
+
+func TestSkipOnInitError(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["message"]
+  data_format = "grok"
+  grok_patterns = ["%{NOT_EXISTING}"]
+  skip_on_init_error = true
+`
+
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(cfg)))
+	require.Len(t, c.Processors, 1)
+
+	// The processor must be disabled and pass all metrics unchanged
+	logger := &testutil.CaptureLogger{}
+	plugin := c.Processors[0].Processor.(processors.HasUnwrap).Unwrap().(*Parser)
+	plugin.Log = logger
+	require.NoError(t, plugin.Init())
+	require.Len(t, logger.Errors(), 1)
+	require.Contains(t, logger.Errors()[0], "Disabling processor")
+
+	input := metric.New(
 		"test",
 		map[string]string{},
-		map[string]interface{}{
-			"b": `eyJsdmwiOiJpbmZvIiwibXNnIjoiaHR0cCByZXF1ZXN0In0=`,
-		},
+		map[string]interface{}{"message": "hello world"},
 		time.Unix(0, 0))
+	expected := []telegraf.Metric{input.Copy()}
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(input))
+}
 
-	testLogger := &testutil.CaptureLogger{}
-	plugin := &Parser{
-		ParseFields:  []string{"a"},
-		Base64Fields: []string{"b"},
-		Log:          testLogger,
-	}
-	plugin.SetParser(&json.Parser{})
-	require.NoError(t, plugin.Init())
-	plugin.Apply(testMetric)
-	require.Empty(t, testLogger.Errors())
+func TestSkipOnInitErrorDisabled(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["message"]
+  data_format = "grok"
+  grok_patterns = ["%{NOT_EXISTING}"]
+`
 
-	plugin = &Parser{
-		ParseFields:  []string{"b"},
-		Base64Fields: []string{"b"},
-		Log:          testLogger,
-	}
-	plugin.SetParser(&json.Parser{})
-	require.NoError(t, plugin.Init())
-	plugin.Apply(testMetric)
-	require.NotEmpty(t, testLogger.Errors())
+	c := config.NewConfig()
+	require.ErrorContains(t, c.LoadConfigData([]byte(cfg)), "adding parser failed")
+}
+
+func TestSkipOnInitErrorUnknownFormat(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["message"]
+  data_format = "not_existing"
+  skip_on_init_error = true
+`
+
+	c := config.NewConfig()
+	require.ErrorContains(t, c.LoadConfigData([]byte(cfg)), "undefined but requested parser")
 }
 
 func TestTracking(t *testing.T) {
@@ -1014,6 +4045,339 @@ func TestTracking(t *testing.T) {
 	}
 }
 
+// statefulParser is a non-reentrant parser failing on concurrent use
+type statefulParser struct {
+	logfmt.Parser
+	busy   atomic.Bool
+	clones *atomic.Int64
+}
+
+func (p *statefulParser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	if !p.busy.CompareAndSwap(false, true) {
+		return nil, errors.New("concurrent use")
+	}
+	defer p.busy.Store(false)
+	time.Sleep(time.Millisecond)
+	return p.Parser.Parse(buf)
+}
+
+func (p *statefulParser) Clone() (telegraf.Parser, error) {
+	p.clones.Add(1)
+	return &statefulParser{clones: p.clones}, nil
+}
+
+func TestConcurrentApply(t *testing.T) {
+	grokParser := &grok.Parser{
+		Patterns: []string{"%{TIMESTAMP_ISO8601:time:ts} %{NUMBER:value:int}"},
+		Log:      testutil.Logger{},
+	}
+	require.NoError(t, grokParser.Init())
+
+	var clones atomic.Int64
+	plugin := &Parser{
+		ParseFields: []string{"message", "payload"},
+		Merge:       "override",
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	require.NoError(t, plugin.Init())
+	plugin.SetParser(grokParser)
+	plugin.SetFieldParser("payload", &statefulParser{clones: &clones})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				input := metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{
+						"message": "2024-01-02T03:04:05Z 42",
+						"payload": "a=1",
+					},
+					time.Unix(0, 0))
+				output := plugin.Apply(input)
+				if !assert.Len(t, output, 1) {
+					return
+				}
+				assert.Equal(t, int64(42), output[0].Fields()["value"])
+				assert.Equal(t, int64(1), output[0].Fields()["a"])
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The processor must use exactly one instance per non-reentrant parser
+	// and never the shared instance directly
+	require.Equal(t, int64(1), clones.Load())
+}
+
+func TestSkippedRowsFromConfig(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["data"]
+  metric_on_error = true
+  data_format = "csv"
+  csv_header_row_count = 1
+  csv_skip_errors = true
+`
+
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(cfg)))
+	require.Len(t, c.Processors, 1)
+
+	plugin := c.Processors[0].Processor.(processors.HasUnwrap).Unwrap().(*Parser)
+	plugin.Log = testutil.Logger{Name: "processor.parser"}
+	require.NoError(t, plugin.Init())
+
+	input := metric.New(
+		"test",
+		map[string]string{},
+		map[string]interface{}{"data": "a,b\n1,2\n3\n4,5,6\n7,8\n"},
+		time.Unix(0, 0))
+
+	// The rows skipped by the parser must be counted as parse errors
+	output := plugin.Apply(input)
+	require.Len(t, output, 3)
+	require.Equal(t, int64(2), output[0].Fields()["parse_errors"])
+}
+
+func TestConcurrentApplyFromConfig(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["message"]
+  merge = "override"
+  data_format = "grok"
+  grok_patterns = ["%{TIMESTAMP_ISO8601:time:ts} %{NUMBER:value:int}"]
+`
+
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(cfg)))
+	require.Len(t, c.Processors, 1)
+
+	plugin := c.Processors[0].Processor.(processors.HasUnwrap).Unwrap().(*Parser)
+	plugin.Log = testutil.Logger{Name: "processor.parser"}
+	require.NoError(t, plugin.Init())
+
+	// The running parser created by the config must be cloned into an
+	// exclusive instance wrapping a separate grok parser
+	require.Len(t, plugin.instances, 1)
+	instance, found := plugin.instances[plugin.parser]
+	require.True(t, found)
+	require.NotSame(t, unwrapParser(plugin.parser), unwrapParser(instance.parser))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				input := metric.New(
+					"test",
+					map[string]string{},
+					map[string]interface{}{"message": "2024-01-02T03:04:05Z 42"},
+					time.Unix(0, 0))
+				output := plugin.Apply(input)
+				if !assert.Len(t, output, 1) {
+					return
+				}
+				assert.Equal(t, int64(42), output[0].Fields()["value"])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMultilineSources(t *testing.T) {
+	grokParser := &grok.Parser{
+		Patterns:         []string{`%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}`},
+		MultilineStart:   `^\d{4}-\d{2}-\d{2}T`,
+		MultilineTimeout: config.Duration(time.Hour),
+		Log:              testutil.Logger{},
+	}
+	require.NoError(t, grokParser.Init())
+
+	plugin := &Parser{
+		ParseFields: []string{"message"},
+		Merge:       "override",
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	plugin.SetParser(grokParser)
+	require.NoError(t, plugin.Init())
+
+	inputs := []telegraf.Metric{
+		metric.New("test", map[string]string{"host": "a"}, map[string]interface{}{"message": "2022-12-01T12:41:45Z ERROR boom"}, time.Unix(0, 0)),
+		metric.New("test", map[string]string{"host": "b"}, map[string]interface{}{"message": "2022-12-01T12:41:46Z INFO started"}, time.Unix(0, 0)),
+		metric.New("test", map[string]string{"host": "a"}, map[string]interface{}{"message": "2022-12-01T12:41:47Z INFO next"}, time.Unix(0, 0)),
+	}
+
+	// Records pending for one series must never be emitted for another
+	var actual []telegraf.Metric
+	for _, input := range inputs {
+		for _, m := range plugin.Apply(input) {
+			if _, found := m.GetTag("level"); found {
+				actual = append(actual, m)
+			}
+		}
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"host": "a", "level": "ERROR"},
+			map[string]interface{}{"message": "boom"},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestMultilineSourcesFromConfig(t *testing.T) {
+	cfg := `
+[[processors.parser]]
+  parse_fields = ["message"]
+  merge = "override"
+  data_format = "grok"
+  grok_patterns = ['%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}']
+  grok_multiline_start = '^\d{4}-\d{2}-\d{2}T'
+  grok_multiline_timeout = "1h"
+`
+
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(cfg)))
+	require.Len(t, c.Processors, 1)
+
+	plugin := c.Processors[0].Processor.(processors.HasUnwrap).Unwrap().(*Parser)
+	plugin.Log = testutil.Logger{Name: "processor.parser"}
+	require.NoError(t, plugin.Init())
+
+	inputs := []telegraf.Metric{
+		metric.New("test", map[string]string{"host": "a"}, map[string]interface{}{"message": "2022-12-01T12:41:45Z ERROR boom"}, time.Unix(0, 0)),
+		metric.New("test", map[string]string{"host": "b"}, map[string]interface{}{"message": "2022-12-01T12:41:46Z INFO started"}, time.Unix(0, 0)),
+		metric.New("test", map[string]string{"host": "a"}, map[string]interface{}{"message": "2022-12-01T12:41:47Z INFO next"}, time.Unix(0, 0)),
+	}
+
+	// The running parser must forward the source, so records pending for
+	// one series are never emitted for another
+	var actual []telegraf.Metric
+	for _, input := range inputs {
+		for _, m := range plugin.Apply(input) {
+			if _, found := m.GetTag("level"); found {
+				actual = append(actual, m)
+			}
+		}
+	}
+
+	expected := []telegraf.Metric{
+		metric.New(
+			"test",
+			map[string]string{"host": "a", "level": "ERROR"},
+			map[string]interface{}{"message": "boom"},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestMultilineFlush(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		stop    bool
+	}{
+		{
+			name:    "timeout",
+			timeout: "50ms",
+		},
+		{
+			name:    "stop",
+			timeout: "1h",
+			stop:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := `
+[[processors.parser]]
+  parse_fields = ["message"]
+  merge = "override"
+  data_format = "grok"
+  grok_patterns = ['%{TIMESTAMP_ISO8601:timestamp:ts-rfc3339} %{LOGLEVEL:level:tag} %{MULTILINEDATA:message}']
+  grok_multiline_start = '^\d{4}-\d{2}-\d{2}T'
+  grok_multiline_timeout = "` + tt.timeout + `"
+`
+
+			c := config.NewConfig()
+			require.NoError(t, c.LoadConfigData([]byte(cfg)))
+			require.Len(t, c.Processors, 1)
+
+			processor := c.Processors[0].Processor
+			plugin := processor.(processors.HasUnwrap).Unwrap().(*Parser)
+			plugin.Log = testutil.Logger{Name: "processor.parser"}
+			require.NoError(t, plugin.Init())
+
+			var acc testutil.Accumulator
+			require.NoError(t, processor.Start(&acc))
+
+			input := metric.New(
+				"test",
+				map[string]string{"host": "a"},
+				map[string]interface{}{"message": "2022-12-01T12:41:45Z ERROR boom"},
+				time.Unix(0, 0),
+			)
+			require.NoError(t, processor.Add(input, &acc))
+			require.Equal(t, uint64(1), acc.NMetrics())
+
+			// The pending record must be emitted even if no further data of
+			// the source arrives
+			if tt.stop {
+				processor.Stop()
+			} else {
+				require.Eventually(t, func() bool {
+					return acc.NMetrics() == 2
+				}, 5*time.Second, 50*time.Millisecond)
+				processor.Stop()
+			}
+
+			expected := []telegraf.Metric{
+				metric.New(
+					"test",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"message": "2022-12-01T12:41:45Z ERROR boom"},
+					time.Unix(0, 0),
+				),
+				metric.New(
+					"test",
+					map[string]string{"host": "a", "level": "ERROR"},
+					map[string]interface{}{"message": "boom"},
+					time.Unix(0, 0),
+				),
+			}
+			testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+// failingCloneParser is a non-reentrant parser which cannot be cloned
+type failingCloneParser struct {
+	logfmt.Parser
+}
+
+func (*failingCloneParser) Clone() (telegraf.Parser, error) {
+	return nil, errors.New("no clone")
+}
+
+func TestCloneError(t *testing.T) {
+	plugin := &Parser{
+		ParseFields: []string{"message"},
+		Log:         testutil.Logger{Name: "processor.parser"},
+	}
+	plugin.SetParser(&failingCloneParser{})
+	require.ErrorContains(t, plugin.Init(), "cloning parser failed: no clone")
+}
+
 // Benchmarks
 
 func getMetricFields(m telegraf.Metric) interface{} {
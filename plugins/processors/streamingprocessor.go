@@ -26,6 +26,9 @@ func (sp *streamingProcessor) SampleConfig() string {
 
 func (sp *streamingProcessor) Start(acc telegraf.Accumulator) error {
 	sp.acc = acc
+	if p, ok := sp.processor.(starter); ok {
+		return p.Start(acc)
+	}
 	return nil
 }
 
@@ -37,6 +40,21 @@ func (sp *streamingProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) e
 }
 
 func (sp *streamingProcessor) Stop() {
+	if p, ok := sp.processor.(stopper); ok {
+		p.Stop()
+	}
+}
+
+// starter is implemented by processors emitting metrics in the background,
+// independent of the metrics passed in
+type starter interface {
+	Start(acc telegraf.Accumulator) error
+}
+
+// stopper is implemented by processors that need to release resources or
+// flush pending state when shutting down
+type stopper interface {
+	Stop()
 }
 
 // Make the streamingProcessor of type Initializer to be able